@@ -0,0 +1,99 @@
+package gkBoot
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+
+	"github.com/yomiji/gkBoot/request"
+)
+
+// Client
+//
+// Sends requests relative to a single base url with the options it was created with, such as WithClientTLS,
+// WithTimeout or WithMiddleware, so they are given once rather than on every call:
+//
+//	users := gkBoot.NewClient("https://users.internal", gkBoot.WithTimeout(5*time.Second))
+//
+//	var resp GetUserResponse
+//	err := users.Do(ctx, GetUserRequest{ID: 7}, &resp)
+//
+// Every request is sent by the same *http.Client, created along with the Client unless one is given by
+// WithHTTPClient, so connections are pooled across calls without relying on http.DefaultClient. A Client is
+// never modified once created and is safe for concurrent use.
+type Client struct {
+	baseUrl    string
+	httpClient *http.Client
+	opts       []ClientOption
+}
+
+// NewClient
+//
+// Creates a Client sending requests relative to the given base url with the given options. Without
+// WithHTTPClient the requests are sent by a dedicated *http.Client whose transport is a clone of
+// http.DefaultTransport.
+func NewClient(baseUrl string, opts ...ClientOption) *Client {
+	httpClient := newClientConfig(opts...).HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Transport: http.DefaultTransport.(*http.Transport).Clone()}
+	}
+
+	return &Client{
+		baseUrl:    baseUrl,
+		httpClient: httpClient,
+		opts:       append([]ClientOption{WithHTTPClient(httpClient)}, opts...),
+	}
+}
+
+// BaseURL
+//
+// The base url the requests of the Client are sent relative to.
+func (c *Client) BaseURL() string {
+	return c.baseUrl
+}
+
+// HTTPClient
+//
+// The *http.Client sending every request of the Client.
+func (c *Client) HTTPClient() *http.Client {
+	return c.httpClient
+}
+
+// With
+//
+// Returns a copy of the Client with the given options appended, sharing the *http.Client of the original.
+func (c *Client) With(opts ...ClientOption) *Client {
+	next := *c
+	next.opts = make([]ClientOption, 0, len(c.opts)+len(opts))
+	next.opts = append(append(next.opts, c.opts...), opts...)
+
+	return &next
+}
+
+// Do
+//
+// Generates the client request from the given request object and sends it as DoRequestWithContext does,
+// applying the options of the Client followed by the given options. The response is decoded into responseObj,
+// which must be a pointer; a nil responseObj discards the response body.
+func (c *Client) Do(
+		ctx context.Context, clientRequest request.HttpRequest, responseObj interface{}, opts ...ClientOption,
+) error {
+	cfg := newClientConfig(c.With(opts...).opts...)
+
+	r, err := generateClientRequest(ctx, c.baseUrl, clientRequest, cfg)
+	if err != nil {
+		return err
+	}
+
+	err = applyRequestOptions(r, cfg)
+	if err != nil {
+		return err
+	}
+
+	// a nil pointer receives nothing, even though its type may implement the response interfaces
+	if value := reflect.ValueOf(responseObj); value.Kind() == reflect.Pointer && value.IsNil() {
+		responseObj = nil
+	}
+
+	return doGeneratedRequestInto(r, responseObj, cfg)
+}
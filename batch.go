@@ -0,0 +1,223 @@
+package gkBoot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/yomiji/gkBoot/request"
+	"github.com/yomiji/gkBoot/response"
+)
+
+// BatchCall
+//
+// A single call of a batch sent by DoBatchRequest. The body document of Request (see request.BodyProvider and
+// JSONBody) becomes one element of the batch and the element of the batch response carrying the same "id" is
+// decoded into Response. A nil Response marks a notification, for which no response is expected.
+type BatchCall struct {
+	Request  request.HttpRequest
+	Response any
+}
+
+// DoBatchRequest
+//
+// Sends the calls as a single POST to the path of the first call, the body being a JSON array holding the
+// body document of every call in order, as expected by JSON-RPC batch endpoints:
+//
+//	sum, product := new(SumResponse), new(ProductResponse)
+//	err := gkBoot.DoBatchRequest(
+//	  baseUrl, []gkBoot.BatchCall{
+//	    {Request: SumRequest{ID: 1, Params: []int{1, 2}}, Response: sum},
+//	    {Request: ProductRequest{ID: 2, Params: []int{3, 4}}, Response: product},
+//	  },
+//	)
+//
+// The response array is split by the "id" member of each element, so the server may answer in any order.
+// Every call expecting a response must carry a unique id and the request fails when no element answers it.
+// Elements answering no call, such as an error with a null id, are ignored unless a call goes unanswered,
+// in which case they are reported in the error. A non-2xx status fails the request as a whole.
+func DoBatchRequest(baseUrl string, calls []BatchCall, opts ...ClientOption) error {
+	return DoBatchRequestWithContext(context.Background(), baseUrl, calls, opts...)
+}
+
+// DoBatchRequestWithContext
+//
+// Sends the calls as DoBatchRequest does, attaching the given context to the outgoing request, so cancelling
+// the context or exceeding its deadline aborts the batch. In that case the returned error wraps the error of
+// the context (context.Canceled or context.DeadlineExceeded).
+func DoBatchRequestWithContext(ctx context.Context, baseUrl string, calls []BatchCall, opts ...ClientOption) error {
+	if len(calls) == 0 {
+		return fmt.Errorf("batch request requires at least one call")
+	}
+
+	cfg := newClientConfig(opts...)
+	codec := withJSONFactories(defaultCodec(), cfg)
+
+	elements := make([][]byte, 0, len(calls))
+	// pending maps the compacted id of each call expecting a response to the call
+	pending := make(map[string]BatchCall, len(calls))
+
+	for i, call := range calls {
+		if call.Request == nil {
+			return fmt.Errorf("batch call %d has no request", i)
+		}
+
+		srName := call.Request.Info().Name
+
+		if err := validateClientRequest(call.Request); err != nil {
+			return fmt.Errorf("batch call %d of client %s: %w", i, srName, err)
+		}
+
+		document := bodyDocument(call.Request)
+		if document == nil {
+			return fmt.Errorf("batch call %d of client %s has no body document", i, srName)
+		}
+
+		element, err := codec.Marshal(document)
+		if err != nil {
+			return fmt.Errorf("client generation failed, %s, of batch call %d of client %s", err, i, srName)
+		}
+
+		elements = append(elements, element)
+
+		if call.Response == nil {
+			continue
+		}
+
+		id, ok := batchElementID(element)
+		if !ok {
+			return fmt.Errorf("batch call %d of client %s expects a response but has no id", i, srName)
+		}
+		if _, exists := pending[id]; exists {
+			return fmt.Errorf("batch call %d of client %s repeats the id %s", i, srName, id)
+		}
+
+		pending[id] = call
+	}
+
+	body := append(append([]byte{'['}, bytes.Join(elements, []byte{','})...), ']')
+
+	srPath := strings.TrimLeft(calls[0].Request.Info().Path, "/")
+	joinedStr := strings.TrimRight(baseUrl, "/") + "/" + srPath
+	u, err := url.Parse(joinedStr)
+	if err != nil {
+		return fmt.Errorf("client generation failed, %s, attempted url: %s", err, joinedStr)
+	}
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("client generation failed, %s, attempted url: %s", err, joinedStr)
+	}
+
+	r.Header.Set("Content-Type", codec.ContentType())
+	r.Header.Set("Accept", codec.ContentType())
+
+	err = applyRequestOptions(r, cfg)
+	if err != nil {
+		return err
+	}
+
+	resp, err := sendClientRequest(r, cfg)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	respBody, release, err := readBody(resp.Body, cfg.BufferPool)
+	if err != nil {
+		return fmt.Errorf("unable to parse response body for %s %s due to %s", r.Method, r.URL, err)
+	}
+
+	defer release()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		if typedErr, ok := decodeErrorResponse(resp, resp.StatusCode, respBody, cfg); ok {
+			return typedErr
+		}
+
+		errorObj := struct {
+			response.ErrorResponse
+		}{}
+		errorObj.NewError(resp.StatusCode, "%s: %s", http.StatusText(resp.StatusCode), respBody)
+
+		return errorObj
+	}
+
+	// a batch made only of notifications may be answered with an empty body
+	if len(pending) == 0 && len(bytes.TrimSpace(respBody)) == 0 {
+		return nil
+	}
+
+	var results []json.RawMessage
+
+	err = json.Unmarshal(respBody, &results)
+	if err != nil {
+		return fmt.Errorf("unable to split batch response for %s %s due to %s: %s", r.Method, r.URL, err, respBody)
+	}
+
+	var unmatched []string
+
+	for _, result := range results {
+		id, _ := batchElementID(result)
+
+		call, ok := pending[id]
+		if !ok {
+			unmatched = append(unmatched, string(result))
+			continue
+		}
+
+		delete(pending, id)
+
+		err = codec.Unmarshal(result, call.Response)
+		if err != nil {
+			return fmt.Errorf("unable to decode batch response %s for %s %s due to %s", id, r.Method, r.URL, err)
+		}
+
+		err = runResponseHooks(r, call.Response, cfg)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(pending) > 0 {
+		missing := make([]string, 0, len(pending))
+		for id := range pending {
+			missing = append(missing, id)
+		}
+		sort.Strings(missing)
+
+		return fmt.Errorf(
+			"batch response for %s %s answered no call with id %s, unmatched elements: [%s]", r.Method, r.URL,
+			strings.Join(missing, ", "), strings.Join(unmatched, ", "),
+		)
+	}
+
+	return nil
+}
+
+// batchElementID
+//
+// extracts the compacted "id" member of a batch element, the boolean result is false when the element is
+// not an object or its id is absent or null
+func batchElementID(element []byte) (string, bool) {
+	var envelope struct {
+		ID json.RawMessage `json:"id"`
+	}
+
+	if err := json.Unmarshal(element, &envelope); err != nil || len(envelope.ID) == 0 {
+		return "", false
+	}
+
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, envelope.ID); err != nil || compacted.String() == "null" {
+		return "", false
+	}
+
+	return compacted.String(), true
+}
@@ -0,0 +1,80 @@
+package gkBoot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/yomiji/gkBoot/request"
+	"github.com/yomiji/gkBoot/response"
+)
+
+const octetStreamContentType = "application/octet-stream"
+
+// DoRequestBinary
+//
+// Generates the client request from the given request object and sends the body bytes as is, with a
+// Content-Type and Accept of application/octet-stream, storing the response body in responseBody. Nothing is
+// marshaled in either direction, which suits blob upload and download endpoints:
+//
+//	var blob []byte
+//	err := gkBoot.DoRequestBinary(ctx, baseUrl, UploadRequest{ID: id}, contents, &blob)
+//
+// The body bytes replace any body given by the request object; a nil body sends none. A nil responseBody
+// discards the response body. Any 2xx status indicates success, any other status results in an error carrying
+// the status and body of the response, or the typed error configured by WithErrorType or
+// WithFieldErrorsDecoder.
+func DoRequestBinary[RequestType request.HttpRequest](
+		ctx context.Context, baseUrl string, clientRequest RequestType, body []byte, responseBody *[]byte,
+		opts ...ClientOption,
+) error {
+	cfg := newClientConfig(opts...)
+
+	r, err := generateClientRequest(ctx, baseUrl, clientRequest, cfg)
+	if err != nil {
+		return err
+	}
+
+	if body != nil {
+		setRequestBytes(r, body)
+		r.Header.Set("Content-Type", octetStreamContentType)
+	}
+	r.Header.Set("Accept", octetStreamContentType)
+
+	err = applyRequestOptions(r, cfg)
+	if err != nil {
+		return err
+	}
+
+	resp, err := sendClientRequest(r, cfg)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	received, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("unable to parse response body for %s %s due to %s", r.Method, r.URL, err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		if typedErr, ok := decodeErrorResponse(resp, resp.StatusCode, received, cfg); ok {
+			return typedErr
+		}
+
+		errorObj := struct {
+			response.ErrorResponse
+		}{}
+		errorObj.NewError(resp.StatusCode, "%s: %s", http.StatusText(resp.StatusCode), received)
+
+		return errorObj
+	}
+
+	if responseBody != nil {
+		*responseBody = received
+	}
+
+	return nil
+}
@@ -0,0 +1,337 @@
+package gkBoot
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// the values of the embed tag
+const (
+	embedFlatten = "flatten"
+	embedNest    = "nest"
+)
+
+// shapedField
+//
+// a member of the body document rebuilt by shapeBodyDocument, holding the json key and options of the field
+// along with its value and the embedding depth used to resolve conflicting keys as encoding/json does
+type shapedField struct {
+	key    string
+	opts   string
+	tagged bool
+	depth  int
+	value  reflect.Value
+}
+
+// shapeBodyDocument
+//
+// rebuilds a request object marshaled in full as the body when it, or a struct it embeds, carries an
+// embed tag. An embed:"nest" field is marshaled under its json name, or its field name, rather than
+// flattened into the body, while an embed:"flatten" struct field has its fields promoted into the body as if
+// it were embedded without a json name. Other fields keep their json names and options. Documents
+// implementing json.Marshaler are left unchanged.
+func shapeBodyDocument(document interface{}) interface{} {
+	if _, ok := document.(json.Marshaler); ok {
+		return document
+	}
+
+	value := reflect.ValueOf(document)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return document
+		}
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct || !hasEmbedTag(value.Type(), map[reflect.Type]bool{}) {
+		return document
+	}
+
+	var fields []shapedField
+	collectShapedFields(value, 0, &fields)
+	fields = dominantShapedFields(fields)
+
+	structFields := make([]reflect.StructField, 0, len(fields))
+	for i, field := range fields {
+		structFields = append(
+			structFields, reflect.StructField{
+				Name: fmt.Sprintf("F%d", i),
+				Type: field.value.Type(),
+				Tag:  reflect.StructTag(fmt.Sprintf("json:%q", field.key+field.opts)),
+			},
+		)
+	}
+
+	shaped := reflect.New(reflect.StructOf(structFields)).Elem()
+	for i, field := range fields {
+		shaped.Field(i).Set(field.value)
+	}
+
+	return shaped.Interface()
+}
+
+// hasEmbedTag
+//
+// reports whether a field of the struct type, or of a struct it embeds, carries an embed tag
+func hasEmbedTag(structType reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[structType] {
+		return false
+	}
+	seen[structType] = true
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+
+		if _, ok := field.Tag.Lookup("embed"); ok {
+			return true
+		}
+
+		if field.Anonymous {
+			fieldType := field.Type
+			for fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct && hasEmbedTag(fieldType, seen) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// collectShapedFields
+//
+// walks the exported fields of the struct value in order, promoting the fields of embedded and flattened
+// structs, including embedded structs of unexported type. Fields of nil embedded pointers are left out, as
+// are fields tagged json:"-".
+func collectShapedFields(value reflect.Value, depth int, fields *[]shapedField) {
+	valueType := value.Type()
+
+	for i := 0; i < valueType.NumField(); i++ {
+		fieldDesc := valueType.Field(i)
+		// encoding/json promotes the exported fields of an embedded struct of unexported type
+		embeddedStruct := fieldDesc.Anonymous && indirectKind(fieldDesc.Type) == reflect.Struct
+		if !fieldDesc.IsExported() && !embeddedStruct {
+			continue
+		}
+
+		jsonTag := fieldDesc.Tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(jsonTag, ",")
+		if opts != "" {
+			opts = "," + opts
+		}
+
+		fieldVal := value.Field(i)
+
+		embed := fieldDesc.Tag.Get("embed")
+		promote := embed == embedFlatten || (fieldDesc.Anonymous && name == "" && embed != embedNest)
+
+		if promote {
+			structVal := fieldVal
+			for structVal.Kind() == reflect.Ptr && !structVal.IsNil() {
+				structVal = structVal.Elem()
+			}
+
+			if structVal.Kind() == reflect.Struct {
+				collectShapedFields(structVal, depth+1, fields)
+				continue
+			}
+			if structVal.Kind() == reflect.Ptr && indirectKind(structVal.Type()) == reflect.Struct {
+				// a nil pointer to a promoted struct has no fields to write
+				continue
+			}
+		}
+
+		if !fieldDesc.IsExported() {
+			// only the promoted fields of an unexported embedded struct are written, as encoding/json does
+			continue
+		}
+
+		key := name
+		if key == "" {
+			key = fieldDesc.Name
+		}
+
+		*fields = append(
+			*fields, shapedField{key: key, opts: opts, tagged: name != "", depth: depth, value: fieldVal},
+		)
+	}
+}
+
+// dominantShapedFields
+//
+// resolves fields sharing a json key as encoding/json does, the shallowest field wins, a tagged field wins
+// among fields of the same depth and a key left ambiguous is dropped
+func dominantShapedFields(fields []shapedField) []shapedField {
+	byKey := make(map[string][]int, len(fields))
+	for i, field := range fields {
+		byKey[field.key] = append(byKey[field.key], i)
+	}
+
+	dominant := make([]shapedField, 0, len(fields))
+
+	for i, field := range fields {
+		candidates := byKey[field.key]
+		if len(candidates) == 1 {
+			dominant = append(dominant, field)
+			continue
+		}
+
+		minDepth := fields[candidates[0]].depth
+		for _, candidate := range candidates {
+			minDepth = min(minDepth, fields[candidate].depth)
+		}
+
+		var shallowest, tagged []int
+		for _, candidate := range candidates {
+			if fields[candidate].depth == minDepth {
+				shallowest = append(shallowest, candidate)
+				if fields[candidate].tagged {
+					tagged = append(tagged, candidate)
+				}
+			}
+		}
+
+		winner := -1
+		if len(tagged) == 1 {
+			winner = tagged[0]
+		} else if len(tagged) == 0 && len(shallowest) == 1 {
+			winner = shallowest[0]
+		}
+
+		if winner == i {
+			dominant = append(dominant, field)
+		}
+	}
+
+	return dominant
+}
+
+// indirectKind
+//
+// the kind of the type once every pointer is dereferenced
+func indirectKind(t reflect.Type) reflect.Kind {
+	return indirectType(t).Kind()
+}
+
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// mapBodyNames
+//
+// rebuilds the document with the json key of every struct field, at any depth, renamed by the mapper. Keys
+// of maps and values implementing json.Marshaler or encoding.TextMarshaler, such as netip.Addr, are left as
+// they are.
+func mapBodyNames(document interface{}, mapper func(name string) string) interface{} {
+	value := reflect.ValueOf(document)
+	if !value.IsValid() {
+		return document
+	}
+
+	return mapValueNames(value, mapper).Interface()
+}
+
+// mapValueNames
+//
+// mapBodyNames for a single value, returning the value itself when it holds no struct to rename
+func mapValueNames(value reflect.Value, mapper func(name string) string) reflect.Value {
+	if !holdsNamedFields(value.Type(), map[reflect.Type]bool{}) {
+		return value
+	}
+
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+		return mapValueNames(value.Elem(), mapper)
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return value
+		}
+
+		elements := make([]interface{}, value.Len())
+		for i := range elements {
+			elements[i] = mapValueNames(value.Index(i), mapper).Interface()
+		}
+
+		return reflect.ValueOf(elements)
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+
+		entries := reflect.MakeMapWithSize(reflect.MapOf(value.Type().Key(), anyType), value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			entries.SetMapIndex(iter.Key(), mapValueNames(iter.Value(), mapper))
+		}
+
+		return entries
+	case reflect.Struct:
+		var fields []shapedField
+		collectShapedFields(value, 0, &fields)
+		fields = dominantShapedFields(fields)
+
+		structFields := make([]reflect.StructField, 0, len(fields))
+		values := make([]reflect.Value, 0, len(fields))
+		for i, field := range fields {
+			fieldVal := mapValueNames(field.value, mapper)
+			structFields = append(
+				structFields, reflect.StructField{
+					Name: fmt.Sprintf("F%d", i),
+					Type: fieldVal.Type(),
+					Tag:  reflect.StructTag(fmt.Sprintf("json:%q", mapper(field.key)+field.opts)),
+				},
+			)
+			values = append(values, fieldVal)
+		}
+
+		mapped := reflect.New(reflect.StructOf(structFields)).Elem()
+		for i, fieldVal := range values {
+			mapped.Field(i).Set(fieldVal)
+		}
+
+		return mapped
+	}
+
+	return value
+}
+
+var anyType = reflect.TypeOf((*interface{})(nil)).Elem()
+
+// holdsNamedFields
+//
+// reports whether values of the type may hold struct fields to rename, a struct not implementing
+// json.Marshaler or encoding.TextMarshaler, directly or through pointers, interfaces, slices, arrays or maps
+func holdsNamedFields(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType) {
+		return false
+	}
+	if t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		return false
+	}
+	if seen[t] {
+		return true
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Struct:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return holdsNamedFields(t.Elem(), seen)
+	default:
+		return false
+	}
+}
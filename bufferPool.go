@@ -0,0 +1,92 @@
+package gkBoot
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// BufferPool
+//
+// Reuses the buffers response bodies are read into, given to requests through WithBufferPool so that busy
+// clients do not allocate a new buffer for every response. Buffers grown beyond the size cap of the pool
+// are discarded rather than returned, so a single large response does not pin its memory. A BufferPool is
+// safe for concurrent use and may be shared by any number of requests.
+type BufferPool struct {
+	pool    sync.Pool
+	maxSize int
+}
+
+// NewBufferPool
+//
+// Creates a pool retaining buffers of at most maxSize bytes. A maxSize of zero or less retains buffers of
+// any size.
+func NewBufferPool(maxSize int) *BufferPool {
+	return &BufferPool{
+		pool: sync.Pool{
+			New: func() interface{} {
+				return new(bytes.Buffer)
+			},
+		},
+		maxSize: maxSize,
+	}
+}
+
+// get
+//
+// takes an empty buffer from the pool
+func (p *BufferPool) get() *bytes.Buffer {
+	buf := p.pool.Get().(*bytes.Buffer)
+	buf.Reset()
+
+	return buf
+}
+
+// put
+//
+// returns the buffer to the pool unless it has grown beyond the size cap
+func (p *BufferPool) put(buf *bytes.Buffer) {
+	if p.maxSize > 0 && buf.Cap() > p.maxSize {
+		return
+	}
+
+	p.pool.Put(buf)
+}
+
+// readBody
+//
+// reads the body in full, into a buffer of the pool when one is given. The returned release function hands
+// the buffer back to the pool and must only be called once the body bytes are no longer referenced. Once the
+// body is given to user code that may keep it, such as an ErrorDecoder, a BodyStatusCoder or a registered
+// Decoder, the buffer is left out of the pool instead.
+func readBody(body io.Reader, pool *BufferPool) ([]byte, func(), error) {
+	if pool == nil {
+		b, err := io.ReadAll(body)
+
+		return b, func() {}, err
+	}
+
+	buf := pool.get()
+
+	_, err := buf.ReadFrom(body)
+	if err != nil {
+		pool.put(buf)
+
+		return nil, func() {}, err
+	}
+
+	return buf.Bytes(), func() { pool.put(buf) }, nil
+}
+
+// isBuiltinDecoder
+//
+// reports whether the decoder is one of the decoders of the package, which never keep the body bytes they are
+// given beyond what the json.Unmarshaler and encoding.TextUnmarshaler contracts allow
+func isBuiltinDecoder(decoder Decoder) bool {
+	switch decoder.(type) {
+	case jsonCodec, configuredJSONCodec, TextDecoder:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,73 @@
+package gkBoot
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/yomiji/gkBoot/request"
+)
+
+// Caller
+//
+// A fluent builder for sending requests of a single request type. Each With method returns a new Caller
+// carrying the accumulated options, so a partially configured Caller may be kept and reused:
+//
+//	users := gkBoot.Call[GetUserRequest, GetUserResponse]("http://localhost:8080").
+//	  WithHeader("Authorization", "Bearer token")
+//
+//	var resp GetUserResponse
+//	err := users.WithQueryValues(url.Values{"expand": {"groups"}}).Do(GetUserRequest{ID: 7}, &resp)
+type Caller[RequestType request.HttpRequest, ResponseType any] struct {
+	baseUrl string
+	ctx     context.Context
+	opts    []ClientOption
+}
+
+// Call
+//
+// Begins a fluent Caller for the request and response types, sending requests relative to the given base url.
+func Call[RequestType request.HttpRequest, ResponseType any](baseUrl string) *Caller[RequestType, ResponseType] {
+	return &Caller[RequestType, ResponseType]{baseUrl: baseUrl, ctx: context.Background()}
+}
+
+// With
+//
+// Returns a copy of the Caller with the given options appended.
+func (c *Caller[RequestType, ResponseType]) With(opts ...ClientOption) *Caller[RequestType, ResponseType] {
+	next := *c
+	next.opts = make([]ClientOption, 0, len(c.opts)+len(opts))
+	next.opts = append(append(next.opts, c.opts...), opts...)
+
+	return &next
+}
+
+// WithContext
+//
+// Returns a copy of the Caller that sends its requests with the given context.
+func (c *Caller[RequestType, ResponseType]) WithContext(ctx context.Context) *Caller[RequestType, ResponseType] {
+	next := c.With()
+	next.ctx = ctx
+
+	return next
+}
+
+// WithHeader
+//
+// Returns a copy of the Caller that sets the given header on its requests.
+func (c *Caller[RequestType, ResponseType]) WithHeader(key, value string) *Caller[RequestType, ResponseType] {
+	return c.With(WithHeader(key, value))
+}
+
+// WithQueryValues
+//
+// Returns a copy of the Caller that merges the given query parameters into its requests.
+func (c *Caller[RequestType, ResponseType]) WithQueryValues(values url.Values) *Caller[RequestType, ResponseType] {
+	return c.With(WithQueryValues(values))
+}
+
+// Do
+//
+// Sends the request with every accumulated option, decoding the response into the response object.
+func (c *Caller[RequestType, ResponseType]) Do(clientRequest RequestType, responseObj *ResponseType) error {
+	return DoRequestWithContext[RequestType, ResponseType](c.ctx, c.baseUrl, clientRequest, responseObj, c.opts...)
+}
@@ -0,0 +1,125 @@
+package gkBoot
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+)
+
+// CanonicalURL
+//
+// Produces a stable representation of the URL of the request for use as a cache or de-duplication key.
+// Equivalent requests produce the same result regardless of the order of their query parameters:
+//
+//   - the scheme and host are lower-cased and default ports (80 for http, 443 for https) are dropped
+//   - the escaped path is cleaned of duplicate slashes and dot segments, keeping a trailing slash when
+//     present, so an escaped slash such as /a%2Fb stays distinct from /a/b
+//   - the opaque request-target of an OpaquePather is kept verbatim in place of the path
+//   - query parameters are sorted by name, the order of repeated values for a name is kept
+//   - the fragment and any user information are dropped
+//
+// The package has no cache or single-flight layer of its own, the key is meant for those built around the
+// client, such as a middleware given to WithMiddleware.
+func CanonicalURL(r *http.Request) string {
+	if r == nil || r.URL == nil {
+		return ""
+	}
+
+	u := r.URL
+	scheme := strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Host)
+
+	if (scheme == "http" && strings.HasSuffix(host, ":80")) || (scheme == "https" && strings.HasSuffix(host, ":443")) {
+		host = host[:strings.LastIndex(host, ":")]
+	}
+
+	canonicalPath := "/"
+	if u.Opaque != "" {
+		canonicalPath = u.Opaque
+	} else if escapedPath := u.EscapedPath(); escapedPath != "" {
+		canonicalPath = path.Clean("/" + escapedPath)
+		if strings.HasSuffix(escapedPath, "/") && canonicalPath != "/" {
+			canonicalPath += "/"
+		}
+	}
+
+	var builder strings.Builder
+
+	if scheme != "" {
+		builder.WriteString(scheme)
+		builder.WriteString("://")
+	}
+	builder.WriteString(host)
+	builder.WriteString(canonicalPath)
+
+	if query := u.Query(); len(query) > 0 {
+		builder.WriteString("?")
+		// Encode sorts by name and keeps the order of values for each name
+		builder.WriteString(query.Encode())
+	}
+
+	return builder.String()
+}
+
+// CanonicalQueryString
+//
+// Produces the sign-ready form of the query of the request, as used by query signing schemes such as AWS
+// Signature Version 4 presigned URLs:
+//
+//   - names and values are percent-encoded as in RFC 3986, leaving only A-Z, a-z, 0-9, '-', '.', '_' and '~'
+//     unescaped, with upper case hex digits and spaces as %20
+//   - parameters are sorted by encoded name, repeated names by encoded value
+//   - every parameter is written as name=value joined by '&', a parameter without value as name=
+//
+// Once the string has been signed, AppendQueryParam adds the signature without reordering the query.
+func CanonicalQueryString(r *http.Request) string {
+	if r == nil || r.URL == nil {
+		return ""
+	}
+
+	query, _ := url.ParseQuery(r.URL.RawQuery)
+
+	pairs := make([][2]string, 0, len(query))
+	for name, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, [2]string{EscapeRFC3986(name), EscapeRFC3986(value)})
+		}
+	}
+
+	sort.Slice(
+		pairs, func(i, j int) bool {
+			if pairs[i][0] != pairs[j][0] {
+				return pairs[i][0] < pairs[j][0]
+			}
+			return pairs[i][1] < pairs[j][1]
+		},
+	)
+
+	encoded := make([]string, 0, len(pairs))
+	for _, pair := range pairs {
+		encoded = append(encoded, pair[0]+"="+pair[1])
+	}
+
+	return strings.Join(encoded, "&")
+}
+
+// AppendQueryParam
+//
+// Appends the parameter to the end of the query of the request, escaped as CanonicalQueryString escapes it.
+// The parameters already present are left in their order and encoding, so a query set to its canonical form
+// stays canonical with the signature following it:
+//
+//	r.URL.RawQuery = gkBoot.CanonicalQueryString(r)
+//	gkBoot.AppendQueryParam(r, "X-Signature", sign(r.URL.RawQuery))
+func AppendQueryParam(r *http.Request, name, value string) {
+	param := EscapeRFC3986(name) + "=" + EscapeRFC3986(value)
+
+	if r.URL.RawQuery == "" {
+		r.URL.RawQuery = param
+		return
+	}
+
+	r.URL.RawQuery += "&" + param
+}
@@ -0,0 +1,71 @@
+package gkBoot
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ChecksumAlgorithm
+//
+// Names the digest of the request body sent by WithContentChecksum along with the header carrying it
+type ChecksumAlgorithm string
+
+const (
+	// ChecksumMD5 sends the base64 encoded MD5 digest of the body in the Content-MD5 header
+	ChecksumMD5 ChecksumAlgorithm = "md5"
+	// ChecksumSHA256 sends the hex encoded SHA-256 digest of the body in the x-amz-content-sha256 header
+	ChecksumSHA256 ChecksumAlgorithm = "sha256"
+)
+
+// applyContentChecksum
+//
+// sets the checksum header of the algorithm computed over the body as it is sent, after marshaling and
+// compression. A body that cannot be replayed is buffered first; a request without a body is given the
+// digest of an empty body.
+func applyContentChecksum(r *http.Request, algorithm ChecksumAlgorithm) error {
+	var body []byte
+
+	if r.Body != nil && r.Body != http.NoBody {
+		if r.GetBody == nil {
+			if err := bufferRequestBody(r); err != nil {
+				return err
+			}
+		}
+
+		replay, err := r.GetBody()
+		if err != nil {
+			return fmt.Errorf("unable to read request body for checksum: %w", err)
+		}
+
+		body, err = io.ReadAll(replay)
+		replay.Close()
+		if err != nil {
+			return fmt.Errorf("unable to read request body for checksum: %w", err)
+		}
+
+		// a replay may share its source with the body, such as a file, so the body is replayed afresh
+		r.Body.Close()
+		r.Body, err = r.GetBody()
+		if err != nil {
+			return fmt.Errorf("unable to replay request body after checksum: %w", err)
+		}
+	}
+
+	switch algorithm {
+	case ChecksumMD5:
+		sum := md5.Sum(body)
+		r.Header.Set("Content-MD5", base64.StdEncoding.EncodeToString(sum[:]))
+	case ChecksumSHA256:
+		sum := sha256.Sum256(body)
+		r.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(sum[:]))
+	default:
+		return fmt.Errorf("unsupported content checksum algorithm '%s'", algorithm)
+	}
+
+	return nil
+}
@@ -2,18 +2,26 @@ package gkBoot
 
 import (
 	"bytes"
+	"container/list"
 	"context"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
+	"os"
 	"reflect"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/go-kit/log/level"
 	http2 "golang.org/x/net/http2"
 
 	"github.com/yomiji/gkBoot/helpers"
@@ -23,7 +31,11 @@ import (
 
 var (
 	MalformedRequestErr = errors.New("malformed request")
-	HTTP2GlobalCA       = []*tls.Config{nil}
+	// HTTP2GlobalCA is never read by the client.
+	//
+	// Deprecated: set the TLS configuration of every request with SetDefaultTLSConfig, or of a single request
+	// with WithClientTLS.
+	HTTP2GlobalCA = []*tls.Config{nil}
 )
 
 // SkipClientValidation is an interface that can be implemented by a request object to skip client validation
@@ -102,19 +114,177 @@ type Requester interface {
 	Request(ctx context.Context) (*http.Request, error)
 }
 
+// OpaquePather
+//
+// When implemented by a request object, the returned request-target is written verbatim in the request line
+// of the generated request, in place of the route path, so that unusual encodings such as %2F or dot segments
+// reach the server untouched. This is the complement of a transparent proxy forwarding the exact target it
+// received.
+//
+// The target is set as the Opaque of the request URL: a client cannot set RequestURI, which is only read on
+// the server side. The target replaces the whole path, including any path of the base url, so only the scheme
+// and host of the base url are used and path fields have no effect. The query generated from the request
+// object is still appended. A target beginning with a single '/' is sent as is, while a target beginning
+// with '//' is sent in absolute form ("http://host/path"), as expected by forward proxies. An empty target
+// leaves the generated path in place.
+type OpaquePather interface {
+	OpaquePath() string
+}
+
+// GenerateClientRequest
+//
+// Generates an *http.Request from the given request object, relative to the given base url. This is the
+// client side complement to GenerateRequestDecoder and reads the same 'request' (or swaggest) tags to place
+// each field in the relative part of the http request. The route path is appended to the path of the base url
+// as declared, keeping a trailing slash, as strict routers tell /users/ from /users:
+//
+//	type ConcreteObject struct {
+//	  ID      int              `request:"path!"`                   // replaces {ID} in the route path
+//	  Value   string           `request:"header" alias:"X-Value"`  // sent as the "X-Value" header
+//	  MyBool  bool             `request:"query" json:"myBool"`     // sent as the "myBool" query param
+//	  Session string           `request:"cookie"`                  // sent as the "Session" cookie
+//	  Body    CustomBodyStruct `request:"form"`                    // json request body (json.Marshal)
+//	  Avatar  FileUpload       `request:"multipart" alias:"avatar"` // file part of a multipart/form-data body
+//	  Filters url.Values       `request:"queryRest"`               // one query param per entry of the map
+//	  Payload io.Reader        `request:"raw"`                     // request body sent as is, []byte or string
+//	  Scans   []ScanFile       `request:"files" alias:"scan"`      // one multipart file part per element
+//	}
+//
+// A trailing '!' marks the field as required. A field tagged with requiredFor:"POST,PUT" is required only
+// when the method of the route is listed, so a request struct may be shared across operations. Only missing
+// (nil) values fail the requirement, unless WithStrictRequired is given to GenerateClientRequestWithOptions.
+// Every missing field is reported in a single error, joined with the error of Validate, unless WithFailFast
+// is given.
+//
+// A single form field is marshaled as the whole request body. Several form fields are merged into one
+// application/x-www-form-urlencoded body, a slice field repeating its name once per element. Form fields
+// alongside multipart or files fields build one multipart/form-data body with them instead, each form field a
+// part marshaled by its codec and carrying the codec's Content-Type.
+//
+// The following tags modify how a field value is written:
+//
+//	delimiter:"comma|space|pipe|multi"  joins slice elements (default comma), multi repeats the query key or
+//	                                    header once per element
+//	explode:"true|false"                the OpenAPI explode of a slice, true repeating the key as multi does
+//	                                    and false joining the elements with commas (default), overridden by
+//	                                    a delimiter tag
+//	urlEncode:"true"                    query escapes the value, path values are always path escaped
+//	rawPath:"true"                      substitutes a path value without escaping it, so that its slashes
+//	                                    and escapes reach the server as given
+//	timeFormat:"2006-01-02"             the layout of a time.Time value (default RFC3339), or unix and unixMilli
+//	                                    for the seconds or milliseconds since the epoch
+//	contentType:"image/png"             the Content-Type of a multipart file part, in place of the type
+//	                                    guessed from the file name extension, or of a form body, encoded by
+//	                                    the codec registered for the type, as JSON for a +json type such as
+//	                                    application/merge-patch+json
+//	mapStyle:"deepObject|form"          expands a map query field into name[key]=value (default) or key=value
+//	                                    parameters, in sorted key order
+//	objectStyle:"deepObject|dotted|json"
+//	                                    expands a struct query field into name[child]=value (default) or
+//	                                    name.child=value parameters, nested structs in turn, each named by
+//	                                    its alias, json or field name. json sends the struct as one JSON value
+//	nullMode:"explicit"                 sends a nil query field as name=null rather than omitting it
+//	compress:"gzip"                     compresses the marshaled request body, setting the Content-Encoding
+//	format:"hex|octal|binary|%04d"      writes integers in the named base or through the fmt verb, in place
+//	                                    of base 10
+//	request:"query,omitempty"           leaves out an optional query, header or cookie field holding an empty
+//	                                    value (false, 0, "", nil or an empty slice or map). The omitempty
+//	                                    option of the json tag does the same, unless the request tag carries
+//	                                    ",keepempty", the request tag taking precedence over the json tag
+//	enum:"a,b,c"                        the values allowed for a string or integer field, or for each element
+//	                                    of a slice, checked along with Validate unless the request object
+//	                                    implements SkipClientValidation. Zero values are not checked
+//	min:"1" max:"100"                   the bounds of a number, or of each element of a slice, checked as
+//	                                    enum is. The swaggest minimum and maximum tags are read as well
+//	minLength:"3" maxLength:"64"        the bounds of the number of characters of a string, checked as enum is
+//	pattern:"^[A-Z]{3}$"                a regular expression a string, or each element of a slice, must
+//	                                    match, checked as enum is. An invalid expression results in an error
+//	                                    wrapping ErrInvalidPattern
+//	file:"name|contentType|contents"    marks the field of a struct element of a files slice holding the file name,
+//	                                    the Content-Type or the contents (io.Reader or []byte) of its part
+//	embed:"nest|flatten"                nests an embedded struct under its json or field name in a JSONBody
+//	                                    body, or flattens the fields of a struct field into it
+//
+// A request object embedding JSONBody is marshaled in full as the body of the request. A request object
+// implementing request.BodyProvider sends the document returned by Body instead, whether or not it also
+// embeds JSONBody, leaving the path, query and header fields out of the body:
+//
+//	type PatchUser struct {
+//	  ID    int               `request:"path!"`
+//	  Patch map[string]string `json:"-"`
+//	}
+//
+//	func (p PatchUser) Body() interface{} { return p.Patch }
 func GenerateClientRequest(baseUrl string, serviceRequest request.HttpRequest) (*http.Request, error) {
+	return GenerateClientRequestContext(context.Background(), baseUrl, serviceRequest)
+}
+
+// GenerateClientRequestContext
+//
+// Generates the client request as GenerateClientRequest does, passing the given context to a Requester and
+// attaching it to the returned request, so a Requester doing work of its own, such as fetching a token, may
+// honor cancellation.
+func GenerateClientRequestContext(
+		ctx context.Context, baseUrl string, serviceRequest request.HttpRequest,
+) (*http.Request, error) {
+	return generateClientRequest(ctx, baseUrl, serviceRequest, newClientConfig())
+}
+
+// GenerateClientRequestWithOptions
+//
+// Generates the client request from the given request object, applying each ClientOption that affects
+// the generation of the request. See ClientConfig for the available options.
+func GenerateClientRequestWithOptions(
+		baseUrl string, serviceRequest request.HttpRequest, opts ...ClientOption,
+) (*http.Request, error) {
+	cfg := newClientConfig(opts...)
+
+	r, err := generateClientRequest(context.Background(), baseUrl, serviceRequest, cfg)
+	if err != nil {
+		return r, err
+	}
+
+	err = applyRequestOptions(r, cfg)
+	if err != nil {
+		return r, err
+	}
+
+	return r, nil
+}
+
+func generateClientRequest(
+		ctx context.Context, baseUrl string, serviceRequest request.HttpRequest, cfg *ClientConfig,
+) (
+		*http.Request, error,
+) {
 	if serviceRequest == nil {
 		return nil, fmt.Errorf("nil client not supported")
 	}
 
-	if validator, ok := serviceRequest.(request.Validator); ok {
-		if _, shouldSkip := serviceRequest.(SkipClientValidation); !shouldSkip {
-			if validationErr := validator.Validate(); validationErr != nil {
-				return nil, fmt.Errorf("client validation err: %w", validationErr)
-			}
-		}
+	validationErr := validateClientRequest(serviceRequest)
+	if validationErr != nil && cfg.FailFast {
+		return nil, validationErr
+	}
+
+	r, err := buildClientRequest(ctx, baseUrl, serviceRequest, cfg)
+
+	// the fields are still assigned when validation fails, reporting every error at once
+	if validationErr != nil {
+		return nil, errors.Join(validationErr, err)
 	}
 
+	return r, err
+}
+
+// buildClientRequest
+//
+// assembles the http request of the request object, its url from the base url and the service path and its
+// fields by their request tags
+func buildClientRequest(
+		ctx context.Context, baseUrl string, serviceRequest request.HttpRequest, cfg *ClientConfig,
+) (
+		*http.Request, error,
+) {
 	// make base url
 	var srPath = serviceRequest.Info().Path
 	baseUrl = strings.TrimRight(baseUrl, "/")
@@ -130,12 +300,14 @@ func GenerateClientRequest(baseUrl string, serviceRequest request.HttpRequest) (
 	// shortcut request generation using a Requester
 	if requester, ok := serviceRequest.(Requester); ok {
 		var r *http.Request
-		r, err = requester.Request(context.Background())
+		r, err = requester.Request(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("client generation failed [%s] %w %w", joinedStr, err, MalformedRequestErr)
 		}
+		r = r.WithContext(ctx)
 		r.URL = u
 		r.Method = string(srMethod)
+		applyOpaquePath(r, serviceRequest)
 		return r, nil
 	}
 
@@ -155,27 +327,171 @@ func GenerateClientRequest(baseUrl string, serviceRequest request.HttpRequest) (
 
 	var requestResult *http.Request
 
-	if _, ok := serviceRequest.(jsonBody); ok {
+	codec := defaultCodec()
+
+	if contentTyper, ok := serviceRequest.(BodyContentTyper); ok {
+		codec, err = codecForContentType(contentTyper.BodyContentType())
+		if err != nil {
+			return nil, fmt.Errorf("client generation failed, %s, of client %s", err, srName)
+		}
+	}
+
+	codec = withJSONFactories(codec, cfg)
+
+	if document := bodyDocument(serviceRequest); document != nil {
 		var body []byte
 
-		body, err = json.Marshal(serviceRequest)
+		body, err = codec.Marshal(document)
 		if err != nil {
 			return nil, fmt.Errorf("client generation failed, %s, of client %s", err, srName)
 		}
 
-		requestResult, err = http.NewRequest(string(srMethod), u.String(), bytes.NewReader(body))
+		requestResult, err = http.NewRequestWithContext(ctx, string(srMethod), u.String(), bytes.NewReader(body))
+		if err == nil {
+			requestResult.Header.Set(contentTypeHeader, codec.ContentType())
+		}
 	} else {
-		requestResult, err = http.NewRequest(string(srMethod), u.String(), nil)
+		requestResult, err = http.NewRequestWithContext(ctx, string(srMethod), u.String(), nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("client generation failed, %s, of client %s", err, srName)
 	}
 
-	err = assignRequest(requestResult, clientValue)
+	state := newAssignmentState(cfg)
+	state.codec = codec
+
+	err = assignRequest(requestResult, clientValue, state)
 	if err != nil {
 		return requestResult, fmt.Errorf("client field assignment failed, for client %s: %w", srName, err)
 	}
 
+	err = writeFormFields(requestResult, state)
+	if err != nil {
+		return requestResult, fmt.Errorf("client form body failed, for client %s: %w", srName, err)
+	}
+
+	// a header field decides the Content-Type over the body, whichever field was assigned first
+	if state.contentType != "" {
+		requestResult.Header.Set(contentTypeHeader, state.contentType)
+	}
+
+	if len(state.multipartParts) > 0 {
+		err = writeMultipartBody(requestResult, state.multipartParts)
+		if err != nil {
+			return requestResult, fmt.Errorf("client multipart body failed, for client %s: %w", srName, err)
+		}
+	}
+
+	// a field assigning the Accept header takes precedence over the registered decoders
+	if requestResult.Header.Get("Accept") == "" {
+		requestResult.Header.Set("Accept", registeredAccept())
+	}
+
+	if selector, ok := serviceRequest.(BodyEncodingSelector); ok {
+		err = writeSelectedBody(requestResult, selector, cfg)
+		if err != nil {
+			return requestResult, fmt.Errorf("client body selection failed, for client %s: %w", srName, err)
+		}
+	}
+
+	applyOpaquePath(requestResult, serviceRequest)
+
+	// a placeholder left in the path is sent literally, failing on the server with a confusing 404
+	if unfilled := unfilledPathPlaceholders(serviceRequest.Info().Path, requestResult); len(unfilled) > 0 {
+		return requestResult, fmt.Errorf(
+			"client generation failed, no field fills the path placeholders %s of client %s, attempted url: %s",
+			strings.Join(unfilled, ", "), srName, requestResult.URL,
+		)
+	}
+
 	return requestResult, nil
 }
 
+// unfilledPathPlaceholders
+//
+// lists the {name} placeholders of the route path still present in the path of the generated request. The
+// path of a request sent with an opaque request-target is not checked.
+func unfilledPathPlaceholders(routePath string, r *http.Request) []string {
+	if r.URL.Opaque != "" {
+		return nil
+	}
+
+	var unfilled []string
+
+	for rest := routePath; ; {
+		start := strings.Index(rest, "{")
+		if start < 0 {
+			break
+		}
+
+		end := strings.Index(rest[start:], "}")
+		if end < 0 {
+			break
+		}
+
+		placeholder := rest[start : start+end+1]
+		if strings.Contains(r.URL.Path, placeholder) {
+			unfilled = append(unfilled, placeholder)
+		}
+
+		rest = rest[start+end+1:]
+	}
+
+	return unfilled
+}
+
+// applyOpaquePath
+//
+// writes the request-target of a request object implementing OpaquePather as the opaque part of the url
+func applyOpaquePath(r *http.Request, serviceRequest request.HttpRequest) {
+	if pather, ok := serviceRequest.(OpaquePather); ok {
+		if target := pather.OpaquePath(); target != "" {
+			r.URL.Opaque = target
+		}
+	}
+}
+
+// validateClientRequest
+//
+// runs the validation of a request object implementing request.Validator and checks the values of its fields
+// against their enum, min, max, minLength, maxLength and pattern tags, unless it implements
+// SkipClientValidation
+func validateClientRequest(serviceRequest request.HttpRequest) error {
+	if _, shouldSkip := serviceRequest.(SkipClientValidation); shouldSkip {
+		return nil
+	}
+
+	var validationErr error
+
+	if validator, ok := serviceRequest.(request.Validator); ok {
+		if err := validator.Validate(); err != nil {
+			validationErr = fmt.Errorf("client validation err: %w", err)
+		}
+	}
+
+	if err := validateTaggedFields(reflect.ValueOf(serviceRequest)); err != nil {
+		validationErr = errors.Join(validationErr, fmt.Errorf("client validation err: %w", err))
+	}
+
+	return validationErr
+}
+
+// bodyDocument
+//
+// the document sent as the body of the request, a BodyProvider taking precedence over an embedded JSONBody.
+// The result is nil when the request object has no body document.
+func bodyDocument(serviceRequest request.HttpRequest) interface{} {
+	if provider, ok := serviceRequest.(request.BodyProvider); ok {
+		return provider.Body()
+	}
+
+	if _, ok := serviceRequest.(jsonBody); ok {
+		return shapeBodyDocument(serviceRequest)
+	}
+
+	return nil
+}
+
 func DoRequest[RequestType request.HttpRequest, ResponseType any](
 		baseUrl string,
 		clientRequest RequestType,
@@ -190,383 +506,2267 @@ func DoRequest[RequestType request.HttpRequest, ResponseType any](
 	return DoGeneratedRequest[ResponseType](c, responseObj, tlsConfig...)
 }
 
-func DoGeneratedRequest[ResponseType any](
-		r *http.Request, responseObj *ResponseType, tlsConfig ...*tls.Config,
+// DoRequestWithOptions
+//
+// Generates the client request from the given request object and sends it, applying each ClientOption to
+// the sending of the request and the processing of the response. See ClientConfig for the available options.
+func DoRequestWithOptions[RequestType request.HttpRequest, ResponseType any](
+		baseUrl string,
+		clientRequest RequestType,
+		responseObj *ResponseType,
+		opts ...ClientOption,
+) error {
+	return DoRequestWithContext[RequestType, ResponseType](
+		context.Background(), baseUrl, clientRequest, responseObj, opts...,
+	)
+}
+
+// DoRequestTyped
+//
+// Sends the request as DoRequestWithOptions does, decoding the JSON body of a non-2xx response into a new
+// ErrorType that is returned as the error (see WithErrorType):
+//
+//	var apiErr *APIError
+//	err := gkBoot.DoRequestTyped[GetUserRequest, GetUserResponse, *APIError](base, req, &resp)
+//	if errors.As(err, &apiErr) {
+//	  // inspect apiErr
+//	}
+func DoRequestTyped[RequestType request.HttpRequest, ResponseType any, ErrorType error](
+		baseUrl string,
+		clientRequest RequestType,
+		responseObj *ResponseType,
+		opts ...ClientOption,
+) error {
+	return DoRequestWithContext[RequestType, ResponseType](
+		context.Background(), baseUrl, clientRequest, responseObj, append(opts, WithErrorType[ErrorType]())...,
+	)
+}
+
+// DoRequestWithContext
+//
+// Generates the client request from the given request object and sends it using the given context, applying
+// each ClientOption as DoRequestWithOptions does. The context is passed to a Requester when the request object
+// implements one and is attached to the outgoing request, so cancelling the context or exceeding its deadline
+// aborts the call. In that case the returned error wraps the error of the context (context.Canceled or
+// context.DeadlineExceeded).
+func DoRequestWithContext[RequestType request.HttpRequest, ResponseType any](
+		ctx context.Context,
+		baseUrl string,
+		clientRequest RequestType,
+		responseObj *ResponseType,
+		opts ...ClientOption,
 ) error {
-	client := http.DefaultClient
+	cfg := newClientConfig(opts...)
 
-	if len(tlsConfig) > 0 {
-		client.Transport = &http2.Transport{TLSClientConfig: tlsConfig[0]}
+	c, err := generateClientRequest(ctx, baseUrl, clientRequest, cfg)
+	if err != nil {
+		return err
 	}
 
-	resp, err := client.Do(r)
+	err = applyRequestOptions(c, cfg)
 	if err != nil {
 		return err
 	}
 
-	var temp interface{} = responseObj
+	return doGeneratedRequest(c, responseObj, cfg)
+}
 
-	if statusCoder, ok := temp.(response.CodedResponse); ok {
-		statusCoder.NewCode(resp.StatusCode)
+// DoGeneratedRequest
+//
+// Sends the given request and processes the response into the response object. When a TLS configuration is
+// given, the request is sent by a client whose transport uses that configuration, negotiating HTTP/2 or
+// HTTP/1.1 with the server (see WithForceHTTP2). The TLS configuration applies to this call only;
+// http.DefaultClient is never modified. Without one, or given nil, the configuration set by
+// SetDefaultTLSConfig is used. Transports are cached by the *tls.Config pointer, so the same configuration
+// should be reused across calls rather than created for each one, which would dial new connections every time.
+//
+// The status and headers are given to a response object implementing response.CodedResponse or
+// response.HeaderReceiver. A response object implementing response.ErredResponse receives an error for any
+// status other than 200, or other than the codes it declares by implementing response.AcceptableCoder. The
+// body is then given to the first of the following interfaces implemented by the response object, in order of
+// precedence:
+//
+//  1. response.StreamReceiver receives the unread response
+//  2. response.StreamTo receives the body of a 2xx response copied into its writer
+//  3. response.CodedCaptureReader receives the status and the unread body
+//  4. response.CaptureReader receives the unread body, unless the status is an error given to
+//     response.ErredResponse
+//  5. response.RawBodyReceiver receives the body bytes
+//  6. json.Unmarshaler decodes the body bytes
+//
+// Response objects implementing none of these are decoded by the decoder registered for the Content-Type of
+// the response (see RegisterDecoder), or by json.Unmarshal when no decoder matches. The body of a 3xx response,
+// returned when a redirect is not followed (see WithRedirectPolicy), is never decoded this way, nor is an empty
+// body, such as that of a 204 No Content, unless the status is 400 or above.
+func DoGeneratedRequest[ResponseType any](
+		r *http.Request, responseObj *ResponseType, tlsConfig ...*tls.Config,
+) error {
+	cfg := newClientConfig()
+
+	if len(tlsConfig) > 0 && tlsConfig[0] != nil {
+		cfg.TLSConfig = tlsConfig[0]
 	}
 
-	if captureReader, ok := temp.(response.CaptureReader); ok {
-		err = captureReader.Capture(resp.Body)
-		if err != nil {
-			return fmt.Errorf("unable to capture response body for %s %s due to %s", r.Method, r.URL, err)
-		}
+	return doGeneratedRequest(r, responseObj, cfg)
+}
 
-		return nil
-	}
+// DoGeneratedRequestWithOptions
+//
+// Sends the given request, applying each ClientOption to the sending of the request and the processing of
+// the response. See ClientConfig for the available options.
+//
+// The request need not be generated by GenerateClientRequest; a request built with the standard library
+// receives the same treatment. Every option that operates on the *http.Request itself (such as
+// WithQueryValues) is applied before sending and every option that operates on the response applies in
+// full, as do the response interfaces with the precedence described by DoGeneratedRequest.
+func DoGeneratedRequestWithOptions[ResponseType any](
+		r *http.Request, responseObj *ResponseType, opts ...ClientOption,
+) error {
+	return DoGeneratedRequestWithContext[ResponseType](r.Context(), r, responseObj, opts...)
+}
 
-	defer resp.Body.Close()
+// DoGeneratedRequestWithRetry
+//
+// Sends the given request as DoGeneratedRequestWithOptions does, retrying failed attempts according to the
+// policy. The request body is replayed for each attempt and retries stop once the deadline of the request
+// context is exceeded.
+func DoGeneratedRequestWithRetry[ResponseType any](
+		r *http.Request, responseObj *ResponseType, policy RetryPolicy, opts ...ClientOption,
+) error {
+	return DoGeneratedRequestWithOptions[ResponseType](r, responseObj, append(opts, WithRetryPolicy(policy))...)
+}
+
+// DoGeneratedRequestWithClient
+//
+// Sends the given request with the given client, applying each ClientOption as DoGeneratedRequestWithOptions
+// does. The client is used as provided, with its own transport, timeouts and connection pool.
+func DoGeneratedRequestWithClient[ResponseType any](
+		client *http.Client, r *http.Request, responseObj *ResponseType, opts ...ClientOption,
+) error {
+	opts = append([]ClientOption{WithHTTPClient(client)}, opts...)
+
+	return DoGeneratedRequestWithOptions[ResponseType](r, responseObj, opts...)
+}
 
-	var body []byte
+// DoGeneratedRequestWithContext
+//
+// Sends the given request with the given context attached, applying each ClientOption as
+// DoGeneratedRequestWithOptions does. Cancelling the context or exceeding its deadline aborts the call and the
+// returned error wraps the error of the context (context.Canceled or context.DeadlineExceeded).
+func DoGeneratedRequestWithContext[ResponseType any](
+		ctx context.Context, r *http.Request, responseObj *ResponseType, opts ...ClientOption,
+) error {
+	cfg := newClientConfig(opts...)
+
+	if ctx != r.Context() {
+		r = r.WithContext(ctx)
+	}
 
-	body, err = io.ReadAll(resp.Body)
+	err := applyRequestOptions(r, cfg)
 	if err != nil {
-		return fmt.Errorf("unable to parse response body for %s %s due to %s", r.Method, r.URL, err)
+		return err
 	}
 
-	// if the response object is nil, only non-200 indicates error
-	if responseObj == nil {
-		if resp.StatusCode != 200 {
-			errorObj := struct {
-				response.ErrorResponse
-			}{}
-			errorObj.NewError(resp.StatusCode, http.StatusText(resp.StatusCode), "body", body)
+	return doGeneratedRequest(r, responseObj, cfg)
+}
 
-			return errorObj
-		}
+// DoRequestNoResponse
+//
+// Generates the client request from the given request object and sends it without decoding the response.
+// Any 2xx status indicates success and the response body is discarded. Any other status results in an error
+// carrying the status and body of the response, or the typed error configured by WithErrorType or
+// WithFieldErrorsDecoder.
+func DoRequestNoResponse(baseUrl string, clientRequest request.HttpRequest, opts ...ClientOption) error {
+	cfg := newClientConfig(opts...)
+
+	c, err := generateClientRequest(context.Background(), baseUrl, clientRequest, cfg)
+	if err != nil {
+		return err
+	}
 
-		return nil
+	err = applyRequestOptions(c, cfg)
+	if err != nil {
+		return err
 	}
 
-	if erredResponse, ok := temp.(response.ErredResponse); ok {
-		if resp.StatusCode != http.StatusOK {
-			erredResponse.NewError(resp.StatusCode, "from response: %s", body)
-		}
+	resp, err := sendClientRequest(c, cfg)
+	if err != nil {
+		return err
 	}
 
-	if unmarshalAble, ok := temp.(json.Unmarshaler); ok {
-		err = unmarshalAble.UnmarshalJSON(body)
+	defer resp.Body.Close()
+
+	if validator, ok := cfg.StatusValidators[resp.StatusCode]; ok && validator != nil {
+		err = validateResponseBody(resp, validator)
 		if err != nil {
-			return fmt.Errorf("unable to decode response body for %s %s due to %s", r.Method, r.URL, err)
+			return fmt.Errorf("response validation failed for %s %s: %w", c.Method, c.URL, err)
 		}
+	}
 
-		return nil
+	if absentErr, ok := absentNotFound(resp, nil, cfg); ok {
+		return absentErr
 	}
 
-	return json.Unmarshal(body, responseObj)
-}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var body []byte
 
-func assignRequest(r *http.Request, value reflect.Value) error {
-	baseVal := value
-	baseValType := value.Type()
-	baseValKind := baseValType.Kind()
+		body, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("unable to parse response body for %s %s due to %s", c.Method, c.URL, err)
+		}
 
-	// ensure that the first value is always a kind of struct
-	if baseValKind != reflect.Struct {
-		if baseVal.CanInterface() {
-			objName := helpers.GetFriendlyRequestName(baseVal.Interface())
-			return fmt.Errorf("request object '%s' must be a Struct type", objName)
-		} else {
-			return fmt.Errorf("request object must be a Struct type for path %s", r.URL.RawPath)
+		if typedErr, ok := decodeErrorResponse(resp, resp.StatusCode, body, cfg); ok {
+			return typedErr
 		}
+
+		errorObj := struct {
+			response.ErrorResponse
+		}{}
+		errorObj.NewError(resp.StatusCode, "%s: %s", http.StatusText(resp.StatusCode), body)
+
+		return errorObj
 	}
 
-	// iterate over all the fields in the struct
-	for i := 0; i < baseValType.NumField(); i++ {
-		var err error
+	// drain so the connection may be reused
+	_, err = io.Copy(io.Discard, resp.Body)
+	if err != nil {
+		return fmt.Errorf("unable to discard response body for %s %s due to %s", c.Method, c.URL, err)
+	}
 
-		fieldDesc := baseValType.Field(i)
+	return nil
+}
 
-		fieldVal := baseVal.Field(i)
+// transportKey
+//
+// identifies a transport built for the TLS configuration, base transport, local address and protocol of a config
+type transportKey struct {
+	tlsConfig  *tls.Config
+	base       *http.Transport
+	localAddr  string
+	forceHTTP2 bool
+}
 
-		// if it is a pointer we need to init and get the element that is the concrete val
-		if fieldDesc.Type.Kind() == reflect.Ptr {
-			// traverse pointer set
-			for ; !fieldVal.IsZero() && fieldVal.Type().Kind() == reflect.Ptr; fieldVal = fieldVal.Elem() {
-			}
-		}
+// maxCachedTransports bounds the transports kept by the cache, each holding its own idle connections
+const maxCachedTransports = 64
 
-		requestTag, alias, jsonAlias, encode := readClientTag(fieldDesc)
+// transportCache
+//
+// keeps the transport built for each transportKey so connections are reused across calls. The key holds the
+// *tls.Config pointer, so a configuration created anew for every call would build a new transport each time;
+// the cache keeps only the most recently used transports, closing the idle connections of those it evicts.
+type transportCache struct {
+	mu      sync.Mutex
+	entries map[transportKey]*list.Element
+	order   *list.List
+}
 
-		urlEncode, _ := strconv.ParseBool(encode)
+// transportEntry
+//
+// a cached transport along with its key, the elements of the recency list of a transportCache
+type transportEntry struct {
+	key       transportKey
+	transport http.RoundTripper
+}
 
-		if requestTag == "" && (fieldDesc.Type.Kind() == reflect.Struct || (fieldDesc.Anonymous && fieldVal.CanSet())) {
-			// recurse if embedded structure
-			return assignRequest(r, fieldVal)
-		} else if requestTag == "form" {
-			fieldName := fieldDesc.Name
+// transports caches the transport built for each transportKey
+var transports = &transportCache{entries: map[transportKey]*list.Element{}, order: list.New()}
 
-			if jsonAlias != "" {
-				fieldName = jsonAlias
-			}
+// load
+//
+// returns the transport cached for the key, marking it as the most recently used
+func (c *transportCache) load(key transportKey) (http.RoundTripper, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
 
-			if alias != "" {
-				fieldName = alias
-			}
+	return element.Value.(*transportEntry).transport, true
+}
 
-			err = writeRequestBody(r, fieldName, fieldVal)
-			if err != nil {
-				return err
-			}
-		} else if requestTag != "" {
-			operation := returnClientOperationByTagValue(requestTag)
-			if operation == nil {
-				return fmt.Errorf("unknown 'client' operation: %s", requestTag)
-			}
+// loadOrStore
+//
+// returns the transport cached for the key, caching the given transport when there is none. The least
+// recently used transport is evicted once the cache is full.
+func (c *transportCache) loadOrStore(key transportKey, transport http.RoundTripper) http.RoundTripper {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.order.MoveToFront(element)
+		return element.Value.(*transportEntry).transport
+	}
 
-			fieldName := fieldDesc.Name
+	c.entries[key] = c.order.PushFront(&transportEntry{key: key, transport: transport})
 
-			if jsonAlias != "" {
-				fieldName = jsonAlias
-			}
+	for c.order.Len() > maxCachedTransports {
+		oldest := c.order.Back()
+		entry := c.order.Remove(oldest).(*transportEntry)
+		delete(c.entries, entry.key)
 
-			if alias != "" {
+		// requests in flight on the evicted transport complete, only its idle connections are closed
+		if closer, ok := entry.transport.(interface{ CloseIdleConnections() }); ok {
+			closer.CloseIdleConnections()
+		}
+	}
+
+	return transport
+}
+
+// httpClientFor
+//
+// returns the client used to send requests with the given config. The returned client is never
+// http.DefaultClient when a TLS configuration, local address, forced HTTP/2 or redirect policy is present, so
+// no global state is modified.
+func httpClientFor(cfg *ClientConfig) (*http.Client, error) {
+	client := cfg.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	if cfg.TLSConfig == nil && cfg.LocalAddr == nil && !cfg.ForceHTTP2 && cfg.RedirectPolicy == nil {
+		return client, nil
+	}
+
+	perCall := *client
+
+	if cfg.TLSConfig != nil || cfg.LocalAddr != nil || cfg.ForceHTTP2 {
+		transport, err := transportFor(client, cfg)
+		if err != nil {
+			return nil, err
+		}
+		perCall.Transport = transport
+	}
+
+	if cfg.RedirectPolicy != nil {
+		perCall.CheckRedirect = cfg.RedirectPolicy
+	}
+
+	return &perCall, nil
+}
+
+// transportFor
+//
+// returns the transport for the TLS configuration, local address and protocol of the config. When HTTP/2 is
+// forced an HTTP/2 transport is used, otherwise the *http.Transport of the client is cloned with the TLS
+// configuration, negotiating the protocol through ALPN, and a dialer bound to the local address. A client
+// transport other than *http.Transport is replaced by a clone of http.DefaultTransport for a TLS
+// configuration, but fails a local address.
+func transportFor(client *http.Client, cfg *ClientConfig) (http.RoundTripper, error) {
+	var dialer *net.Dialer
+	var localAddr string
+
+	if cfg.LocalAddr != nil {
+		localAddr = cfg.LocalAddr.String()
+		dialer = &net.Dialer{
+			LocalAddr: &net.TCPAddr{IP: cfg.LocalAddr},
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}
+	}
+
+	if cfg.ForceHTTP2 {
+		key := transportKey{tlsConfig: cfg.TLSConfig, localAddr: localAddr, forceHTTP2: true}
+		if transport, ok := transports.load(key); ok {
+			return transport, nil
+		}
+
+		transport := &http2.Transport{TLSClientConfig: cfg.TLSConfig}
+		if dialer != nil {
+			transport.DialTLSContext = func(
+					ctx context.Context, network, addr string, tlsConfig *tls.Config,
+			) (net.Conn, error) {
+				return (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, network, addr)
+			}
+		}
+
+		return transports.loadOrStore(key, transport), nil
+	}
+
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	baseTransport, ok := base.(*http.Transport)
+	if !ok {
+		if dialer != nil {
+			return nil, fmt.Errorf("local address %s requires an *http.Transport, the client uses %T", localAddr, base)
+		}
+		baseTransport = http.DefaultTransport.(*http.Transport)
+	}
+
+	key := transportKey{tlsConfig: cfg.TLSConfig, base: baseTransport, localAddr: localAddr}
+	if transport, ok := transports.load(key); ok {
+		return transport, nil
+	}
+
+	transport := baseTransport.Clone()
+	if cfg.TLSConfig != nil {
+		// the transport adds the protocols it negotiates to its configuration, so the given one is not shared
+		transport.TLSClientConfig = cfg.TLSConfig.Clone()
+		transport.ForceAttemptHTTP2 = true
+	}
+	if dialer != nil {
+		transport.DialContext = dialer.DialContext
+	}
+
+	return transports.loadOrStore(key, transport), nil
+}
+
+// sendClientRequest
+//
+// sends the request using the transport settings of the given config
+func sendClientRequest(r *http.Request, cfg *ClientConfig) (*http.Response, error) {
+	client, err := httpClientFor(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp *http.Response
+
+	r, cancel := withRequestTimeout(r, cfg.Timeout)
+
+	send := RoundTripFunc(client.Do)
+	if cfg.RateLimiter != nil {
+		send = cfg.RateLimiter.wrap(send)
+	}
+
+	roundTrip := send
+	if cfg.RetryPolicy != nil {
+		roundTrip = func(r *http.Request) (*http.Response, error) {
+			return sendWithRetry(send, r, *cfg.RetryPolicy, cfg.RetryBudget)
+		}
+	}
+
+	resp, err = chainMiddleware(roundTrip, cfg.Middleware)(r)
+	if err != nil {
+		// the context is read before it is released, only a context already done timed out or canceled the call
+		ctxErr := r.Context().Err()
+		cancel()
+		if errors.Is(ctxErr, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("request %s %s timed out: %w: %w", r.Method, r.URL, ctxErr, err)
+		} else if ctxErr != nil {
+			return nil, fmt.Errorf("request %s %s canceled: %w: %w", r.Method, r.URL, ctxErr, err)
+		}
+		return nil, err
+	}
+
+	// the timeout keeps bounding the body until it is closed
+	if cfg.Timeout > 0 {
+		resp.Body = &cancelReadCloser{ReadCloser: resp.Body, cancel: cancel}
+	}
+
+	if cfg.DownloadProgress != nil {
+		resp.Body = newProgressReadCloser(resp.Body, resp.ContentLength, cfg.DownloadProgress)
+	}
+
+	decompressResponse(resp)
+
+	return resp, nil
+}
+
+// withRequestTimeout
+//
+// derives the context of the request with the timeout, returning the request unchanged and a no-op cancel
+// function when the timeout is zero
+func withRequestTimeout(r *http.Request, timeout time.Duration) (*http.Request, context.CancelFunc) {
+	if timeout <= 0 {
+		return r, func() {}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+
+	return r.WithContext(ctx), cancel
+}
+
+// cancelReadCloser
+//
+// releases the context of the request once the response body is closed
+type cancelReadCloser struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelReadCloser) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+
+	return err
+}
+
+func doGeneratedRequest[ResponseType any](r *http.Request, responseObj *ResponseType, cfg *ClientConfig) error {
+	// a nil response object receives nothing, even though its pointer type may implement the interfaces
+	var temp interface{}
+	if responseObj != nil {
+		temp = responseObj
+	}
+
+	return doGeneratedRequestInto(r, temp, cfg)
+}
+
+// doGeneratedRequestInto
+//
+// sends the request and receives the response into the response object, a pointer or nil
+func doGeneratedRequestInto(r *http.Request, responseObj interface{}, cfg *ClientConfig) error {
+	resp, err := sendClientRequest(r, cfg)
+	if err != nil {
+		return err
+	}
+
+	temp := responseObj
+
+	if statusCoder, ok := temp.(response.CodedResponse); ok {
+		statusCoder.NewCode(resp.StatusCode)
+	}
+
+	if headerReceiver, ok := temp.(response.HeaderReceiver); ok {
+		headerReceiver.CaptureHeaders(resp.Header)
+	}
+
+	receiveCorrelationID(resp, temp, cfg)
+
+	if validator, ok := cfg.StatusValidators[resp.StatusCode]; ok && validator != nil {
+		err = validateResponseBody(resp, validator)
+		if err != nil {
+			return fmt.Errorf("response validation failed for %s %s: %w", r.Method, r.URL, err)
+		}
+	}
+
+	if cfg.SchemaValidator != nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		err = validateResponseBody(resp, cfg.SchemaValidator)
+		if err != nil {
+			return fmt.Errorf("response schema validation failed for %s %s: %w", r.Method, r.URL, err)
+		}
+	}
+
+	if absentErr, ok := absentNotFound(resp, temp, cfg); ok {
+		if absentErr != nil {
+			return absentErr
+		}
+
+		return runResponseHooks(r, responseObj, cfg)
+	}
+
+	if cfg.ResponseWriter != nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		written, err := copyResponseBody(resp, cfg.ResponseWriter)
+
+		if cfg.ResponseWritten != nil {
+			*cfg.ResponseWritten = written
+		}
+
+		if err != nil {
+			return fmt.Errorf(
+				"unable to write response body for %s %s after %d bytes due to %w", r.Method, r.URL, written, err,
+			)
+		}
+
+		return runResponseHooks(r, responseObj, cfg)
+	}
+
+	if streamReceiver, ok := temp.(response.StreamReceiver); ok {
+		err = streamReceiver.ReceiveStream(resp)
+		if err != nil {
+			return fmt.Errorf("unable to receive response stream for %s %s due to %s", r.Method, r.URL, err)
+		}
+
+		return runResponseHooks(r, responseObj, cfg)
+	}
+
+	if streamTo, ok := temp.(response.StreamTo); ok && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		written, err := copyResponseBody(resp, streamTo.StreamWriter())
+
+		streamTo.BodyWritten(written)
+
+		if err != nil {
+			return fmt.Errorf(
+				"unable to stream response body for %s %s after %d bytes due to %w", r.Method, r.URL, written, err,
+			)
+		}
+
+		return runResponseHooks(r, responseObj, cfg)
+	}
+
+	if codedCaptureReader, ok := temp.(response.CodedCaptureReader); ok {
+		err = codedCaptureReader.CaptureWithCode(resp.StatusCode, resp.Body)
+		if err != nil {
+			return fmt.Errorf("unable to capture response body for %s %s due to %s", r.Method, r.URL, err)
+		}
+
+		return runResponseHooks(r, responseObj, cfg)
+	}
+
+	if captureReader, ok := temp.(response.CaptureReader); ok {
+		// an error body is given to the error of the response rather than captured as if it were success
+		if erredResponse, ok := temp.(response.ErredResponse); ok && !isAcceptableStatus(temp, resp.StatusCode) {
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return fmt.Errorf("unable to parse response body for %s %s due to %s", r.Method, r.URL, err)
+			}
+
+			erredResponse.NewError(resp.StatusCode, "from response: %s", body)
+
+			return runResponseHooks(r, responseObj, cfg)
+		}
+
+		err = captureReader.Capture(resp.Body)
+		if err != nil {
+			return fmt.Errorf("unable to capture response body for %s %s due to %s", r.Method, r.URL, err)
+		}
+
+		return runResponseHooks(r, responseObj, cfg)
+	}
+
+	defer resp.Body.Close()
+
+	body, release, err := readBody(resp.Body, cfg.BufferPool)
+	if err != nil {
+		return fmt.Errorf("unable to parse response body for %s %s due to %s", r.Method, r.URL, err)
+	}
+
+	// the pooled body is reused only once decoding has completed
+	defer func() { release() }()
+
+	// the status used to route error handling, the logical status of the body when one is reported
+	status := resp.StatusCode
+
+	if bodyStatusCoder, ok := temp.(response.BodyStatusCoder); ok && responseObj != nil {
+		// user code may keep the body bytes, so they are never returned to the pool
+		release = func() {}
+
+		if code, ok := bodyStatusCoder.StatusFromBody(body); ok {
+			status = code
+			if statusCoder, ok := temp.(response.CodedResponse); ok {
+				statusCoder.NewCode(status)
+			}
+		}
+	}
+
+	if status < 200 || status > 299 {
+		if cfg.ErrorDecoder != nil || cfg.FieldErrorsDecoder != nil {
+			release = func() {}
+		}
+
+		if typedErr, ok := decodeErrorResponse(resp, status, body, cfg); ok {
+			return typedErr
+		}
+	}
+
+	// if the response object is nil, only non-2xx indicates error
+	if responseObj == nil {
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			errorObj := struct {
+				response.ErrorResponse
+			}{}
+			errorObj.NewError(resp.StatusCode, http.StatusText(resp.StatusCode), "body", body)
+
+			return errorObj
+		}
+
+		return nil
+	}
+
+	if erredResponse, ok := temp.(response.ErredResponse); ok {
+		if !isAcceptableStatus(temp, status) {
+			erredResponse.NewError(status, "from response: %s", body)
+		}
+	}
+
+	if rawBodyReceiver, ok := temp.(response.RawBodyReceiver); ok {
+		// the receiver may keep the body bytes, so they are never returned to the pool
+		release = func() {}
+
+		err = rawBodyReceiver.ReceiveBody(body)
+		if err != nil {
+			return fmt.Errorf("unable to receive response body for %s %s due to %s", r.Method, r.URL, err)
+		}
+
+		return runResponseHooks(r, responseObj, cfg)
+	}
+
+	// a 204, or any other empty body without an error status, carries no document to decode
+	if len(body) == 0 && resp.StatusCode < 400 {
+		return runResponseHooks(r, responseObj, cfg)
+	}
+
+	if unmarshalAble, ok := temp.(json.Unmarshaler); ok {
+		err = unmarshalAble.UnmarshalJSON(body)
+		if err != nil {
+			return newDecodeError(r, resp, body, err)
+		}
+
+		return runResponseHooks(r, responseObj, cfg)
+	}
+
+	// the body of a redirect that was not followed is meant for a user agent and is not decoded
+	if resp.StatusCode >= 300 && resp.StatusCode < 400 {
+		return runResponseHooks(r, responseObj, cfg)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" && cfg.SniffContentType {
+		contentType = sniffContentType(body)
+	}
+
+	decoder := decoderForContentType(contentType)
+	for _, statusDecoder := range cfg.statusDecoders {
+		if statusDecoder.codes.Contains(resp.StatusCode) {
+			decoder = statusDecoder.decoder
+			break
+		}
+	}
+	if codec, ok := decoder.(Codec); ok {
+		decoder = withJSONFactories(codec, cfg)
+	}
+
+	if !isBuiltinDecoder(decoder) {
+		release = func() {}
+	}
+
+	err = decoder.Unmarshal(body, responseObj)
+	if err != nil {
+		return newDecodeError(r, resp, body, err)
+	}
+
+	return runResponseHooks(r, responseObj, cfg)
+}
+
+// copyResponseBody
+//
+// copies the response body into the writer, closing the body, and returns the number of bytes written,
+// including those written before the copy failed. A cancelled context interrupts the copy through the body.
+func copyResponseBody(resp *http.Response, w io.Writer) (int64, error) {
+	defer resp.Body.Close()
+
+	if w == nil {
+		return 0, errors.New("no writer to stream the response body to")
+	}
+
+	return io.Copy(w, resp.Body)
+}
+
+// isAcceptableStatus
+//
+// reports whether the status indicates success for the response object, one of its declared codes when it
+// implements response.AcceptableCoder and 200 otherwise
+func isAcceptableStatus(responseObj interface{}, status int) bool {
+	acceptableCoder, ok := responseObj.(response.AcceptableCoder)
+	if !ok {
+		return status == http.StatusOK
+	}
+
+	for _, code := range acceptableCoder.AcceptableCodes() {
+		if code == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// runResponseHooks
+//
+// passes the decoded response object through each configured hook in order, stopping at the first error
+func runResponseHooks(r *http.Request, responseObj interface{}, cfg *ClientConfig) error {
+	for _, hook := range cfg.ResponseHooks {
+		if err := hook(responseObj); err != nil {
+			return fmt.Errorf("response hook failed for %s %s: %w", r.Method, r.URL, err)
+		}
+	}
+
+	return nil
+}
+
+// validateResponseBody
+//
+// buffers the response body so that it may be given to the validator and replaces the body with
+// the buffered copy so the remainder of the response processing is unaffected
+func validateResponseBody(resp *http.Response, validator func([]byte) error) error {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	return validator(body)
+}
+
+// assignmentState
+//
+// carries the configuration and the state accumulated while assigning the fields of a single request
+type assignmentState struct {
+	cfg *ClientConfig
+	// queryFields maps each resolved query parameter name to the field that first produced it
+	queryFields map[string]string
+	// multipartParts accumulates the fields tagged 'multipart' or 'files' to be written as a single body
+	multipartParts []multipartPart
+	// formFields accumulates the fields tagged 'form', written together once every field is assigned
+	formFields []formField
+	// codec encodes the field tagged 'form', the request object may choose it through BodyContentTyper
+	codec Codec
+	// contentType is the Content-Type given by a header field, taking precedence over that of the body
+	contentType string
+}
+
+// formField
+//
+// a field tagged 'form' along with the codec and compression its body is written with
+type formField struct {
+	fieldName   string
+	value       reflect.Value
+	codec       Codec
+	compression string
+	fieldOpts   clientFieldOptions
+	// position is the number of multipart parts assigned before the field, preserving the field order
+	position int
+}
+
+func newAssignmentState(cfg *ClientConfig) *assignmentState {
+	return &assignmentState{cfg: cfg, queryFields: make(map[string]string), codec: defaultCodec()}
+}
+
+// checkDuplicateQueryKey
+//
+// records the query parameter name resolved for the field and applies the configured
+// DuplicateQueryKeyMode when the name was already produced by another field. Returns true
+// when the values under the name must be merged after the field is written.
+func (s *assignmentState) checkDuplicateQueryKey(fieldName, structFieldName string) (merge bool, err error) {
+	firstField, exists := s.queryFields[fieldName]
+	if !exists {
+		s.queryFields[fieldName] = structFieldName
+		return false, nil
+	}
+
+	switch s.cfg.DuplicateQueryKeys {
+	case DuplicateQueryKeyError:
+		return false, fmt.Errorf(
+			"duplicate query parameter '%s' resolved from fields %s and %s", fieldName, firstField, structFieldName,
+		)
+	case DuplicateQueryKeyMerge:
+		return true, nil
+	default:
+		if s.cfg.Logger != nil {
+			_ = level.Warn(s.cfg.Logger).Log(
+				"msg", "duplicate query parameter", "key", fieldName, "fields", firstField+","+structFieldName,
+			)
+		}
+		return false, nil
+	}
+}
+
+// omitsEmptyField
+//
+// reports whether an optional query, header or cookie field is left out for holding an empty value, as
+// encoding/json decides for omitempty: false, 0, a nil pointer or interface and an empty string, slice,
+// array or map. A non-nil pointer is never empty.
+func omitsEmptyField(
+		requestPart RequestPart, fieldDesc reflect.StructField, fieldVal reflect.Value, fieldOpts clientFieldOptions,
+) bool {
+	switch requestPart {
+	case RequestPartQuery, RequestPartHeader, RequestPartCookie:
+	default:
+		return false
+	}
+
+	if !fieldOpts.omitEmpty {
+		return false
+	}
+
+	switch fieldDesc.Type.Kind() {
+	case reflect.Ptr:
+		// assignRequest dereferences non-nil pointers, only a value still of the field type is the field itself
+		return fieldVal.Type() == fieldDesc.Type && fieldVal.IsNil()
+	case reflect.Interface:
+		return fieldVal.IsNil()
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return fieldVal.Len() == 0
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return fieldVal.IsZero()
+	default:
+		return false
+	}
+}
+
+// isEmbeddedStructPointer
+//
+// reports whether the field embeds a pointer, of any depth, to a struct
+func isEmbeddedStructPointer(fieldDesc reflect.StructField) bool {
+	if !fieldDesc.Anonymous {
+		return false
+	}
+
+	fieldType := fieldDesc.Type
+	for fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+
+	return fieldDesc.Type.Kind() == reflect.Ptr && fieldType.Kind() == reflect.Struct
+}
+
+func assignRequest(r *http.Request, value reflect.Value, state *assignmentState) error {
+	baseVal := value
+	baseValType := value.Type()
+	baseValKind := baseValType.Kind()
+
+	// ensure that the first value is always a kind of struct
+	if baseValKind != reflect.Struct {
+		if baseVal.CanInterface() {
+			objName := helpers.GetFriendlyRequestName(baseVal.Interface())
+			return fmt.Errorf("request object '%s' must be a Struct type", objName)
+		} else {
+			return fmt.Errorf("request object must be a Struct type for path %s", r.URL.RawPath)
+		}
+	}
+
+	// every field error is collected, unless failing fast on the first
+	var errs []error
+
+	// iterate over all the fields in the struct
+	for i := 0; i < baseValType.NumField(); i++ {
+		var err error
+
+		fieldDesc := baseValType.Field(i)
+
+		fieldVal := baseVal.Field(i)
+
+		// if it is a pointer we need to init and get the element that is the concrete val
+		if fieldDesc.Type.Kind() == reflect.Ptr {
+			// traverse pointer set
+			for ; !fieldVal.IsZero() && fieldVal.Type().Kind() == reflect.Ptr; fieldVal = fieldVal.Elem() {
+			}
+		}
+
+		requestTag, alias, jsonAlias, encode, fieldOpts := readClientTag(fieldDesc)
+		fieldOpts.strictRequired = state.cfg.StrictRequired
+		fieldOpts.escape = state.cfg.Escaper
+
+		urlEncode, _ := strconv.ParseBool(encode)
+
+		requestPart, required, _ := ParseRequestPart(requestTag)
+		required = required || requiredForMethod(fieldDesc, r.Method)
+
+		if requestTag == "" && isAuthorizationType(fieldDesc.Type) {
+			writeRequestAuthorization(r, fieldVal)
+		} else if requestTag == "" && (fieldDesc.Type.Kind() == reflect.Struct || isEmbeddedStructPointer(fieldDesc)) {
+			// recurse if embedded structure, a nil embedded pointer has no fields to write
+			if fieldVal.Kind() == reflect.Struct {
+				err = assignRequest(r, fieldVal, state)
+			}
+		} else if requestPart == RequestPartForm {
+			fieldName := fieldDesc.Name
+
+			if jsonAlias != "" {
+				fieldName = jsonAlias
+			}
+
+			if alias != "" {
 				fieldName = alias
 			}
 
-			err = operation(r, fieldName, fieldVal, strings.HasSuffix(requestTag, "!"), urlEncode)
-			if err != nil {
-				return err
-			}
-		} else {
+			compression := fieldOpts.compress
+			if compression == "" {
+				compression = state.cfg.RequestCompression
+			}
+
+			codec := state.codec
+			if fieldOpts.contentType != "" {
+				codec, err = codecForContentType(fieldOpts.contentType)
+				if err == nil {
+					codec = withJSONFactories(codec, state.cfg)
+				} else {
+					err = fmt.Errorf("client generation failed, %s, of client field %s", err, fieldName)
+				}
+			}
+
+			if err == nil {
+				state.formFields = append(
+					state.formFields, formField{
+						fieldName:   fieldName,
+						value:       baseVal.Field(i),
+						codec:       codec,
+						compression: compression,
+						fieldOpts:   fieldOpts,
+						position:    len(state.multipartParts),
+					},
+				)
+			}
+		} else if requestPart == RequestPartRaw {
+			err = writeRequestRawBody(r, fieldDesc.Name, baseVal.Field(i), required)
+		} else if requestPart == RequestPartMultipart {
+			fieldName := fieldDesc.Name
+
+			if jsonAlias != "" {
+				fieldName = jsonAlias
+			}
+
+			if alias != "" {
+				fieldName = alias
+			}
+
+			part, ok := readMultipartPart(fieldName, baseVal.Field(i), urlEncode, fieldOpts)
+			if ok && part.file == nil && part.value == "" && fieldOpts.strictRequired {
+				ok = false
+			}
+			if !ok {
+				if required {
+					err = fmt.Errorf("required multipart field not found or not set: %s", fieldName)
+				}
+			} else {
+				state.multipartParts = append(state.multipartParts, part)
+			}
+		} else if requestPart == RequestPartFiles {
+			fieldName := fieldDesc.Name
+
+			if jsonAlias != "" {
+				fieldName = jsonAlias
+			}
+
+			if alias != "" {
+				fieldName = alias
+			}
+
+			var parts []multipartPart
+
+			parts, err = readMultipartFiles(fieldName, fieldVal, urlEncode, fieldOpts)
+			if err == nil && len(parts) == 0 && required {
+				err = fmt.Errorf("required multipart files not found or not set: %s", fieldName)
+			}
+
+			state.multipartParts = append(state.multipartParts, parts...)
+		} else if requestTag != "" && returnClientOperationByTagValue(requestPart) == nil {
+			err = fmt.Errorf("unknown 'client' operation: %s", requestTag)
+		} else if requestTag != "" && !required && omitsEmptyField(requestPart, fieldDesc, fieldVal, fieldOpts) {
+			continue
+		} else if requestTag != "" {
+			operation := returnClientOperationByTagValue(requestPart)
+
+			fieldName := fieldDesc.Name
+
+			if jsonAlias != "" {
+				fieldName = jsonAlias
+			}
+
+			if alias != "" {
+				fieldName = alias
+			}
+
+			var mergeQuery bool
+
+			if requestPart == RequestPartQuery {
+				mergeQuery, err = state.checkDuplicateQueryKey(fieldName, fieldDesc.Name)
+			}
+
+			if err == nil {
+				err = operation(r, fieldName, fieldVal, required, urlEncode, fieldOpts)
+			}
+
+			isContentType := http.CanonicalHeaderKey(fieldName) == contentTypeHeader
+			if err == nil && requestPart == RequestPartHeader && isContentType {
+				state.contentType = r.Header.Get(contentTypeHeader)
+			}
+
+			if err == nil && mergeQuery {
+				reqQuery := r.URL.Query()
+				reqQuery[fieldName] = []string{strings.Join(reqQuery[fieldName], ",")}
+				r.URL.RawQuery = reqQuery.Encode()
+			}
+		}
+
+		if err != nil {
+			if state.cfg.FailFast {
+				return err
+			}
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+var basicAuthType = reflect.TypeOf(request.BasicAuth{})
+var bearerTokenType = reflect.TypeOf(request.BearerToken(""))
+
+// isAuthorizationType
+//
+// reports whether the field type is request.BasicAuth or request.BearerToken, or a pointer to either
+func isAuthorizationType(fieldType reflect.Type) bool {
+	for fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+
+	return fieldType == basicAuthType || fieldType == bearerTokenType
+}
+
+// writeRequestAuthorization
+//
+// sets the Authorization header from a request.BasicAuth or request.BearerToken value, leaving the
+// header untouched when the value is empty or a nil pointer
+func writeRequestAuthorization(r *http.Request, fieldVal reflect.Value) {
+	if fieldVal.Kind() == reflect.Ptr || !fieldVal.CanInterface() {
+		return
+	}
+
+	switch auth := fieldVal.Interface().(type) {
+	case request.BasicAuth:
+		if auth.Username != "" || auth.Password != "" {
+			r.SetBasicAuth(auth.Username, auth.Password)
+		}
+	case request.BearerToken:
+		if auth != "" {
+			r.Header.Set("Authorization", "Bearer "+string(auth))
+		}
+	}
+}
+
+// clientFieldOptions
+//
+// modifiers read from the struct tags of a request field that alter how its value is written
+type clientFieldOptions struct {
+	// delimiter joins slice elements, delimiterMulti writes each element separately
+	delimiter string
+	// contentType overrides the Content-Type of a multipart file part or of a form body
+	contentType string
+	// timeFormat is the layout of time.Time values, or one of timeFormatUnix and timeFormatUnixMilli
+	timeFormat string
+	// compress is the Content-Encoding applied to a marshaled request body
+	compress string
+	// strictRequired rejects required fields that are present but write an empty value
+	strictRequired bool
+	// mapStyle names the query parameters of map entries, mapStyleDeepObject unless mapStyleForm
+	mapStyle string
+	// nullMode decides whether a nil query field is omitted or sent as null with nullModeExplicit
+	nullMode string
+	// rawPath substitutes a path value without escaping it
+	rawPath bool
+	// omitEmpty leaves out an optional query, header or cookie field holding an empty value
+	omitEmpty bool
+	// format writes integers in a named base or through a fmt verb
+	format string
+	// objectStyle names the query parameters of struct fields, objectStyleDeepObject unless objectStyleDotted,
+	// or sends the struct as JSON with objectStyleJSON
+	objectStyle string
+	// escape replaces the query and path escaping of the value when the config has an Escaper
+	escape func(string) string
+}
+
+const nullModeExplicit = "explicit"
+
+const (
+	mapStyleDeepObject = "deepObject"
+	mapStyleForm       = "form"
+)
+
+const (
+	objectStyleDeepObject = "deepObject"
+	objectStyleDotted     = "dotted"
+	objectStyleJSON       = "json"
+)
+
+// isMissingRequired
+//
+// reports whether a required field fails its requirement. A required field must be present, so only nil
+// values fail, unless strictRequired also rejects values that convert to an empty string. Zero numbers and
+// false booleans are always present.
+func isMissingRequired(fieldValue reflect.Value, convertedValue *string, fieldOpts clientFieldOptions) bool {
+	if convertedValue == nil || isNilValue(fieldValue) {
+		return true
+	}
+
+	return fieldOpts.strictRequired && *convertedValue == ""
+}
+
+// isMissingRequiredValues
+//
+// isMissingRequired for a field written as multiple values
+func isMissingRequiredValues(fieldValue reflect.Value, values []string, fieldOpts clientFieldOptions) bool {
+	if isNilValue(fieldValue) {
+		return true
+	}
+
+	return fieldOpts.strictRequired && len(values) == 0
+}
+
+func isNilValue(value reflect.Value) bool {
+	if !value.IsValid() {
+		return true
+	}
+
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+		return value.IsNil()
+	default:
+		return false
+	}
+}
+
+const delimiterMulti = "multi"
+
+const (
+	timeFormatUnix      = "unix"
+	timeFormatUnixMilli = "unixMilli"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// formatTime
+//
+// formats the time using the 'timeFormat' tag of the field, RFC3339 when the tag is absent
+func formatTime(t time.Time, fieldOpts clientFieldOptions) string {
+	switch fieldOpts.timeFormat {
+	case "":
+		return t.Format(time.RFC3339)
+	case timeFormatUnix:
+		return strconv.FormatInt(t.Unix(), 10)
+	case timeFormatUnixMilli:
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	default:
+		return t.Format(fieldOpts.timeFormat)
+	}
+}
+
+// readDelimiterTag
+//
+// resolves the 'delimiter' tag, accepting the names comma, space, pipe and multi or a literal delimiter. Without
+// one, the OpenAPI 'explode' tag of a swaggest field decides, explode:"true" repeating the key as multi does.
+func readDelimiterTag(field reflect.StructField) string {
+	tag, ok := field.Tag.Lookup("delimiter")
+	if !ok || tag == "" {
+		if explode, _ := strconv.ParseBool(field.Tag.Get("explode")); explode {
+			return delimiterMulti
+		}
+
+		return ","
+	}
+
+	switch strings.ToLower(tag) {
+	case "comma":
+		return ","
+	case "space":
+		return " "
+	case "pipe":
+		return "|"
+	case delimiterMulti:
+		return delimiterMulti
+	default:
+		return tag
+	}
+}
+
+func readClientTag(field reflect.StructField) (
+		requestPart, alias, jsonAlias, encode string, fieldOpts clientFieldOptions,
+) {
+	var ok bool
+	var tag string
+
+	if tag, ok = field.Tag.Lookup("urlEncode"); ok {
+		encode = tag
+	}
+
+	fieldOpts.delimiter = readDelimiterTag(field)
+	fieldOpts.contentType = field.Tag.Get("contentType")
+	fieldOpts.timeFormat = field.Tag.Get("timeFormat")
+	fieldOpts.compress = field.Tag.Get("compress")
+	fieldOpts.mapStyle = field.Tag.Get("mapStyle")
+	fieldOpts.nullMode = field.Tag.Get("nullMode")
+	fieldOpts.rawPath, _ = strconv.ParseBool(field.Tag.Get("rawPath"))
+	fieldOpts.format = field.Tag.Get("format")
+	fieldOpts.objectStyle = field.Tag.Get("objectStyle")
+
+	// the omitempty option of the json tag applies unless the request tag decides
+	if tag, ok = field.Tag.Lookup("json"); ok {
+		fieldOpts.omitEmpty = slices.Contains(strings.Split(tag, ",")[1:], "omitempty")
+	}
+
+	if requestPart, alias, jsonAlias, ok = fromSwaggestTag(field); ok {
+		return requestPart, alias, jsonAlias, encode, fieldOpts
+	}
+	if tag, ok = field.Tag.Lookup("request"); ok {
+		var modifiers string
+		requestPart, modifiers, _ = strings.Cut(tag, ",")
+		for _, modifier := range strings.Split(modifiers, ",") {
+			switch modifier {
+			case "omitempty":
+				fieldOpts.omitEmpty = true
+			case "keepempty":
+				fieldOpts.omitEmpty = false
+			}
+		}
+	}
+	if tag, ok = field.Tag.Lookup("alias"); ok {
+		alias = tag
+	}
+	if tag, ok = field.Tag.Lookup("json"); ok {
+		if tag == "-," {
+			jsonAlias = "-"
+		} else {
+			jsonAlias = strings.Split(tag, ",")[0]
+			if jsonAlias == "-" {
+				jsonAlias = ""
+			}
+		}
+	}
+
+	return
+}
+
+func convertBaseValueToString(src reflect.Value, urlEncode bool, fieldOpts clientFieldOptions) *string {
+	if !src.IsValid() {
+		return nil
+	}
+
+	srcType := src.Type()
+
+	if srcType.Kind() == reflect.Ptr {
+		src = src.Elem()
+		return convertBaseValueToString(src, urlEncode, fieldOpts)
+	}
+
+	if formatted, ok := formatInteger(src, fieldOpts.format); ok {
+		if urlEncode {
+			formatted = fieldOpts.queryEscape(formatted)
+		}
+		return &formatted
+	}
+
+	kind := src.Type().Kind()
+
+	var result string
+
+	switch kind {
+	case reflect.String:
+		result = src.String()
+	case reflect.Int:
+		result = strconv.FormatInt(src.Int(), 10)
+	case reflect.Bool:
+		result = strconv.FormatBool(src.Bool())
+	case reflect.Slice:
+		result = convertSliceToStringValue(src, urlEncode, fieldOpts)
+		return &result
+	case reflect.Float64:
+		result = strconv.FormatFloat(src.Float(), 'f', -1, 64)
+	case reflect.Uint:
+		result = strconv.FormatUint(src.Uint(), 10)
+	case reflect.Uint64:
+		result = strconv.FormatUint(src.Uint(), 10)
+	case reflect.Float32:
+		result = strconv.FormatFloat(src.Float(), 'f', -1, 32)
+	case reflect.Int8:
+		result = strconv.FormatInt(src.Int(), 10)
+	case reflect.Uint8:
+		result = strconv.FormatUint(src.Uint(), 10)
+	case reflect.Int64:
+		result = strconv.FormatInt(src.Int(), 10)
+	case reflect.Int32:
+		result = strconv.FormatInt(src.Int(), 10)
+	case reflect.Int16:
+		result = strconv.FormatInt(src.Int(), 10)
+	case reflect.Uint16:
+		result = strconv.FormatUint(src.Uint(), 10)
+	case reflect.Uint32:
+		result = strconv.FormatUint(src.Uint(), 10)
+	case reflect.Complex64:
+		result = strconv.FormatComplex(src.Complex(), 'f', -1, 64)
+	case reflect.Complex128:
+		result = strconv.FormatComplex(src.Complex(), 'f', -1, 128)
+	case reflect.Map:
+		if src.CanInterface() {
+			body, err := json.Marshal(src.Interface())
+			if err != nil {
+				result = "{ \"error\": \"JSON parse error\" }"
+			} else {
+				result = string(body)
+			}
+		} else {
+			result = "null"
+		}
+	case reflect.Struct:
+		if srcType == timeType && src.CanInterface() {
+			result = formatTime(src.Interface().(time.Time), fieldOpts)
+		} else if src.CanInterface() {
+			body, err := json.Marshal(src.Interface())
+			if err != nil {
+				result = "{ \"error\": \"JSON parse error\" }"
+			}
+
+			result = string(body)
+		} else {
+			result = "null"
+		}
+	default:
+		result = "?"
+	}
+
+	if urlEncode {
+		result = fieldOpts.queryEscape(result)
+	}
+
+	return &result
+}
+
+// the named bases of the format tag
+const (
+	formatHex    = "hex"
+	formatOctal  = "octal"
+	formatBinary = "binary"
+)
+
+// formatInteger
+//
+// writes an integer in the base named by the format, or through the format as a fmt verb such as "%04d"
+// or "%X". The boolean result is false when the value is not an integer or the format is empty or unknown.
+func formatInteger(src reflect.Value, format string) (string, bool) {
+	if format == "" {
+		return "", false
+	}
+
+	var signed bool
+
+	switch src.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		signed = true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+	default:
+		return "", false
+	}
+
+	base := 0
+
+	switch format {
+	case formatHex:
+		base = 16
+	case formatOctal:
+		base = 8
+	case formatBinary:
+		base = 2
+	default:
+		if !strings.Contains(format, "%") {
+			return "", false
+		}
+		if signed {
+			return fmt.Sprintf(format, src.Int()), true
+		}
+		return fmt.Sprintf(format, src.Uint()), true
+	}
+
+	if signed {
+		return strconv.FormatInt(src.Int(), base), true
+	}
+
+	return strconv.FormatUint(src.Uint(), base), true
+}
+
+func convertSliceToStringValue(value reflect.Value, urlEncode bool, fieldOpts clientFieldOptions) string {
+	var accumulatedStrArr = make([]string, 0, value.Len())
+	for i := 0; i < value.Len(); i++ {
+		var currentStr *string
+
+		currentStr = convertBaseValueToString(value.Index(i), urlEncode, fieldOpts)
+		if currentStr == nil {
+			continue
+		}
+
+		if urlEncode {
+			*currentStr = fieldOpts.queryEscape(*currentStr)
+		}
+
+		accumulatedStrArr = append(accumulatedStrArr, *currentStr)
+	}
+
+	delimiter := fieldOpts.delimiter
+	if delimiter == "" || delimiter == delimiterMulti {
+		delimiter = ","
+	}
+
+	return strings.Join(accumulatedStrArr, delimiter)
+}
+
+// convertMultiValues
+//
+// converts each element of a slice value into its own string, the boolean result is false when the value is
+// not a slice and should be converted as a single value
+func convertMultiValues(src reflect.Value, urlEncode bool, fieldOpts clientFieldOptions) ([]string, bool) {
+	for src.IsValid() && src.Kind() == reflect.Ptr {
+		src = src.Elem()
+	}
+
+	if !src.IsValid() || src.Kind() != reflect.Slice || fieldOpts.delimiter != delimiterMulti {
+		return nil, false
+	}
+
+	values := make([]string, 0, src.Len())
+	for i := 0; i < src.Len(); i++ {
+		if converted := convertBaseValueToString(src.Index(i), urlEncode, fieldOpts); converted != nil {
+			values = append(values, *converted)
+		}
+	}
+
+	return values, true
+}
+
+type typicalClientRequestWriter func(
+		r *http.Request, fieldName string, fieldValue reflect.Value, isRequired bool,
+		urlEncode bool, fieldOpts clientFieldOptions,
+) error
+
+func returnClientOperationByTagValue(requestPart RequestPart) typicalClientRequestWriter {
+	switch requestPart {
+	case RequestPartCookie:
+		return writeRequestCookie
+	case RequestPartHeader:
+		return writeRequestHeader
+	case RequestPartQuery:
+		return writeRequestQueryParam
+	case RequestPartPath:
+		return writeRequestPath
+	case RequestPartQueryRest:
+		return writeRequestQueryRest
+	default:
+		return nil
+	}
+}
+
+func writeRequestCookie(
+		r *http.Request, fieldName string, fieldValue reflect.Value, isRequired bool,
+		urlEncode bool, fieldOpts clientFieldOptions,
+) error {
+	if fieldValue.IsValid() && fieldValue.Kind() == reflect.Slice {
+		return writeRequestCookies(r, fieldName, fieldValue, isRequired, urlEncode, fieldOpts)
+	}
+
+	var convertedValue = convertBaseValueToString(fieldValue, urlEncode, fieldOpts)
+
+	if isRequired {
+		if isMissingRequired(fieldValue, convertedValue, fieldOpts) {
+			return fmt.Errorf("required cookie not found or not set: %s", fieldName)
+		}
+	}
+
+	// a nil optional field is omitted rather than sent empty
+	if convertedValue == nil {
+		return nil
+	}
+
+	r.AddCookie(&http.Cookie{Name: fieldName, Value: *convertedValue})
+
+	return nil
+}
+
+var cookieType = reflect.TypeOf(http.Cookie{})
+
+// writeRequestCookies
+//
+// adds a cookie for each element of the slice. Elements of type *http.Cookie or http.Cookie are added
+// under their own names; any other element is added under the field name.
+func writeRequestCookies(
+		r *http.Request, fieldName string, fieldValue reflect.Value, isRequired bool,
+		urlEncode bool, fieldOpts clientFieldOptions,
+) error {
+	added := 0
+
+	for i := 0; i < fieldValue.Len(); i++ {
+		element := fieldValue.Index(i)
+
+		if element.Type() == cookieType || (element.Kind() == reflect.Ptr && element.Type().Elem() == cookieType) {
+			if element.Kind() == reflect.Ptr {
+				if element.IsNil() {
+					continue
+				}
+				element = element.Elem()
+			}
+			cookie := element.Interface().(http.Cookie)
+			r.AddCookie(&cookie)
+			added++
+			continue
+		}
+
+		convertedValue := convertBaseValueToString(element, urlEncode, fieldOpts)
+		if convertedValue == nil {
+			continue
+		}
+
+		r.AddCookie(&http.Cookie{Name: fieldName, Value: *convertedValue})
+		added++
+	}
+
+	if isRequired && (fieldValue.IsNil() || (fieldOpts.strictRequired && added == 0)) {
+		return fmt.Errorf("required cookie not found or not set: %s", fieldName)
+	}
+
+	return nil
+}
+
+func writeRequestHeader(
+		r *http.Request, fieldName string, fieldValue reflect.Value, isRequired bool,
+		urlEncode bool, fieldOpts clientFieldOptions,
+) error {
+	if values, ok := convertMultiValues(fieldValue, urlEncode, fieldOpts); ok {
+		if isRequired && isMissingRequiredValues(fieldValue, values, fieldOpts) {
+			return fmt.Errorf("required header not found or not set: %s", fieldName)
+		}
+		for _, value := range values {
+			r.Header.Add(fieldName, value)
+		}
+		return nil
+	}
+
+	var convertedValue = convertBaseValueToString(fieldValue, urlEncode, fieldOpts)
+
+	if isRequired {
+		if isMissingRequired(fieldValue, convertedValue, fieldOpts) {
+			return fmt.Errorf("required header not found or not set: %s", fieldName)
+		}
+	}
+
+	// a nil optional field is omitted rather than sent empty, a request carries a single Content-Type
+	if convertedValue != nil && http.CanonicalHeaderKey(fieldName) == contentTypeHeader {
+		if *convertedValue != "" {
+			r.Header.Set(fieldName, *convertedValue)
+		}
+	} else if convertedValue != nil {
+		r.Header.Add(fieldName, *convertedValue)
+	}
+
+	return nil
+}
+
+func writeRequestQueryParam(
+		r *http.Request, fieldName string, fieldValue reflect.Value, isRequired bool, urlEncode bool,
+		fieldOpts clientFieldOptions,
+) error {
+	if fieldValue.IsValid() && fieldValue.Kind() == reflect.Map {
+		return writeRequestQueryMap(r, fieldName, fieldValue, isRequired, fieldOpts)
+	}
+
+	if isQueryObject(fieldValue, fieldOpts) {
+		return writeRequestQueryObject(r, fieldName, fieldValue, isRequired, fieldOpts)
+	}
+
+	if values, ok := convertMultiValues(fieldValue, false, fieldOpts); ok {
+		if isRequired && isMissingRequiredValues(fieldValue, values, fieldOpts) {
+			return fmt.Errorf("required header not found or not set: %s", fieldName)
+		}
+		reqQuery := r.URL.Query()
+		for _, value := range values {
+			reqQuery.Add(fieldName, value)
+		}
+		r.URL.RawQuery = reqQuery.Encode()
+		return nil
+	}
+
+	var convertedValue = convertBaseValueToString(fieldValue, false, fieldOpts)
+
+	if isRequired {
+		if isMissingRequired(fieldValue, convertedValue, fieldOpts) {
+			return fmt.Errorf("required header not found or not set: %s", fieldName)
+		}
+	}
+
+	if convertedValue != nil {
+		reqQuery := r.URL.Query()
+		reqQuery.Add(fieldName, *convertedValue)
+		r.URL.RawQuery = reqQuery.Encode()
+	} else if fieldOpts.nullMode == nullModeExplicit {
+		// an explicit null is sent for a nil field, in place of omitting the parameter
+		reqQuery := r.URL.Query()
+		reqQuery.Add(fieldName, "null")
+		r.URL.RawQuery = reqQuery.Encode()
+	}
+
+	return nil
+}
+
+// writeRequestQueryMap
+//
+// expands the map into one query parameter per entry, in sorted key order. In the default deepObject
+// style each entry is named fieldName[key], in the form style each entry is named by its key alone.
+// Values that are maps, structs or slices of those are JSON encoded.
+func writeRequestQueryMap(
+		r *http.Request, fieldName string, fieldValue reflect.Value, isRequired bool, fieldOpts clientFieldOptions,
+) error {
+	if isRequired && (fieldValue.IsNil() || (fieldOpts.strictRequired && fieldValue.Len() == 0)) {
+		return fmt.Errorf("required query parameter not found or not set: %s", fieldName)
+	}
+
+	entries := make(map[string]string, fieldValue.Len())
+	keys := make([]string, 0, fieldValue.Len())
+
+	iter := fieldValue.MapRange()
+	for iter.Next() {
+		key := convertBaseValueToString(iter.Key(), false, fieldOpts)
+		value := convertBaseValueToString(iter.Value(), false, fieldOpts)
+		if key == nil || value == nil {
+			continue
+		}
+		entries[*key] = *value
+		keys = append(keys, *key)
+	}
+
+	sort.Strings(keys)
+
+	reqQuery := r.URL.Query()
+	for _, key := range keys {
+		name := fieldName + "[" + key + "]"
+		if fieldOpts.mapStyle == mapStyleForm {
+			name = key
+		}
+		reqQuery.Add(name, entries[key])
+	}
+	r.URL.RawQuery = reqQuery.Encode()
+
+	return nil
+}
+
+// isQueryObject
+//
+// reports whether the value is a struct expanded into query parameters, any struct other than time.Time
+// unless its objectStyle is json
+func isQueryObject(fieldValue reflect.Value, fieldOpts clientFieldOptions) bool {
+	return fieldValue.IsValid() && fieldValue.Kind() == reflect.Struct && fieldValue.Type() != timeType &&
+		fieldOpts.objectStyle != objectStyleJSON
+}
+
+// writeRequestQueryObject
+//
+// expands the struct into one query parameter per field, in field order, named fieldName[child] in the
+// default deepObject style or fieldName.child in the dotted style
+func writeRequestQueryObject(
+		r *http.Request, fieldName string, fieldValue reflect.Value, isRequired bool, fieldOpts clientFieldOptions,
+) error {
+	reqQuery := r.URL.Query()
+
+	written := addQueryObject(reqQuery, fieldName, fieldValue, fieldOpts.objectStyle)
+
+	if isRequired && fieldOpts.strictRequired && written == 0 {
+		return fmt.Errorf("required query parameter not found or not set: %s", fieldName)
+	}
+
+	r.URL.RawQuery = reqQuery.Encode()
+
+	return nil
+}
+
+// addQueryObject
+//
+// adds the exported fields of the struct to the query under the prefix, following the tags of each field,
+// and returns the number of parameters added. Nested structs are expanded in the style of their own
+// objectStyle tag, or the style of the parent, and the fields of embedded structs are promoted. Nil fields
+// are left out, unless nullMode is explicit, as are fields tagged json:"-" and empty fields with omitempty.
+func addQueryObject(reqQuery url.Values, prefix string, value reflect.Value, style string) int {
+	written := 0
+	valueType := value.Type()
+
+	for i := 0; i < valueType.NumField(); i++ {
+		fieldDesc := valueType.Field(i)
+		if !fieldDesc.IsExported() || fieldDesc.Tag.Get("json") == "-" {
+			continue
+		}
+
+		_, alias, jsonAlias, _, fieldOpts := readClientTag(fieldDesc)
+
+		fieldVal := value.Field(i)
+		if omitsEmptyField(RequestPartQuery, fieldDesc, fieldVal, fieldOpts) {
+			continue
+		}
+		for fieldVal.Kind() == reflect.Ptr && !fieldVal.IsNil() {
+			fieldVal = fieldVal.Elem()
+		}
+
+		if fieldOpts.objectStyle == "" {
+			fieldOpts.objectStyle = style
+		}
+
+		if fieldDesc.Anonymous && alias == "" && jsonAlias == "" && isQueryObject(fieldVal, fieldOpts) {
+			written += addQueryObject(reqQuery, prefix, fieldVal, fieldOpts.objectStyle)
+			continue
+		}
+
+		name := fieldDesc.Name
+		if jsonAlias != "" {
+			name = jsonAlias
+		}
+		if alias != "" {
+			name = alias
+		}
+
+		if style == objectStyleDotted {
+			name = prefix + "." + name
+		} else {
+			name = prefix + "[" + name + "]"
+		}
+
+		if isQueryObject(fieldVal, fieldOpts) {
+			written += addQueryObject(reqQuery, name, fieldVal, fieldOpts.objectStyle)
+			continue
+		}
+
+		if values, ok := convertMultiValues(fieldVal, false, fieldOpts); ok {
+			for _, converted := range values {
+				reqQuery.Add(name, converted)
+			}
+			written += len(values)
+		} else if converted := convertBaseValueToString(fieldVal, false, fieldOpts); converted != nil {
+			reqQuery.Add(name, *converted)
+			written++
+		} else if fieldOpts.nullMode == nullModeExplicit {
+			reqQuery.Add(name, "null")
+			written++
+		}
+	}
+
+	return written
+}
+
+// writeRequestQueryRest
+//
+// adds every entry of the map, keyed by the parameter name, to the query in sorted name order. A value that
+// is a slice adds one parameter per element, in order. The field name itself is not sent.
+func writeRequestQueryRest(
+		r *http.Request, fieldName string, fieldValue reflect.Value, isRequired bool, urlEncode bool,
+		fieldOpts clientFieldOptions,
+) error {
+	// a nil pointer to the map is left as a pointer by the field traversal
+	if fieldValue.Kind() != reflect.Map && fieldValue.Kind() != reflect.Ptr {
+		return fmt.Errorf("query rest field %s must be a map, got %s", fieldName, fieldValue.Type())
+	}
+
+	if fieldValue.IsNil() {
+		if isRequired {
+			return fmt.Errorf("required query parameters not found or not set: %s", fieldName)
+		}
+		return nil
+	}
+
+	entries := make(map[string][]string, fieldValue.Len())
+	names := make([]string, 0, fieldValue.Len())
+
+	iter := fieldValue.MapRange()
+	for iter.Next() {
+		name := convertBaseValueToString(iter.Key(), false, fieldOpts)
+		if name == nil {
 			continue
 		}
+
+		var values []string
+
+		value := iter.Value()
+		for value.Kind() == reflect.Interface && !value.IsNil() {
+			value = value.Elem()
+		}
+
+		if value.Kind() == reflect.Slice || value.Kind() == reflect.Array {
+			for i := 0; i < value.Len(); i++ {
+				if converted := convertBaseValueToString(value.Index(i), false, fieldOpts); converted != nil {
+					values = append(values, *converted)
+				}
+			}
+		} else if converted := convertBaseValueToString(value, false, fieldOpts); converted != nil {
+			values = append(values, *converted)
+		}
+
+		if _, seen := entries[*name]; !seen {
+			names = append(names, *name)
+		}
+		entries[*name] = append(entries[*name], values...)
+	}
+
+	if isRequired && fieldOpts.strictRequired && len(names) == 0 {
+		return fmt.Errorf("required query parameters not found or not set: %s", fieldName)
+	}
+
+	sort.Strings(names)
+
+	reqQuery := r.URL.Query()
+	for _, name := range names {
+		for _, value := range entries[name] {
+			reqQuery.Add(name, value)
+		}
 	}
+	r.URL.RawQuery = reqQuery.Encode()
 
 	return nil
 }
 
-func readClientTag(field reflect.StructField) (requestPart, alias, jsonAlias, encode string) {
-	var ok bool
-	var tag string
+// applyRequestOptions
+//
+// applies the options that operate on the *http.Request itself, regardless of how it was built
+func applyRequestOptions(r *http.Request, cfg *ClientConfig) error {
+	mergeQueryValues(r, cfg.QueryValues)
+
+	for key, values := range cfg.Headers {
+		r.Header.Del(key)
+		for _, value := range values {
+			r.Header.Add(key, value)
+		}
+	}
 
-	if tag, ok = field.Tag.Lookup("urlEncode"); ok {
-		encode = tag
+	if cfg.Accept != "" {
+		r.Header.Set("Accept", cfg.Accept)
 	}
-	if requestPart, alias, jsonAlias, ok = fromSwaggestTag(field); ok {
-		return requestPart, alias, jsonAlias, encode
+
+	if cfg.Close {
+		r.Close = true
 	}
-	if tag, ok = field.Tag.Lookup("request"); ok {
-		requestPart = tag
+
+	if cfg.Correlate {
+		applyCorrelationID(r, cfg)
 	}
-	if tag, ok = field.Tag.Lookup("alias"); ok {
-		alias = tag
+
+	if cfg.DateHeader {
+		r.Header.Set("Date", cfg.Clock().UTC().Format(http.TimeFormat))
 	}
-	if tag, ok = field.Tag.Lookup("json"); ok {
-		if tag == "-," {
-			jsonAlias = "-"
-		} else {
-			jsonAlias = strings.Split(tag, ",")[0]
-			if jsonAlias == "-" {
-				jsonAlias = ""
-			}
+
+	if cfg.DeadlineHeader != "" {
+		applyDeadlineHeader(r, cfg)
+	}
+
+	if cfg.ContentChecksum != "" {
+		if err := applyContentChecksum(r, cfg.ContentChecksum); err != nil {
+			return err
 		}
 	}
 
-	return
+	if cfg.Escaper != nil && r.URL.RawQuery != "" {
+		r.URL.RawQuery = encodeQuery(r.URL.Query(), cfg.Escaper)
+	}
+
+	if cfg.UploadProgress != nil {
+		trackUploadProgress(r, cfg.UploadProgress)
+	}
+
+	return nil
 }
 
-func convertBaseValueToString(src reflect.Value, urlEncode bool) *string {
-	if !src.IsValid() {
-		return nil
+// mergeQueryValues
+//
+// adds the given values to the query of the request, following the values already present
+func mergeQueryValues(r *http.Request, values url.Values) {
+	if len(values) == 0 {
+		return
 	}
 
-	srcType := src.Type()
+	reqQuery := r.URL.Query()
+	for key, vals := range values {
+		for _, val := range vals {
+			reqQuery.Add(key, val)
+		}
+	}
+	r.URL.RawQuery = reqQuery.Encode()
+}
 
-	if srcType.Kind() == reflect.Ptr {
-		src = src.Elem()
-		return convertBaseValueToString(src, urlEncode)
+func writeRequestBody(
+		r *http.Request, fieldName string, fieldValue reflect.Value, codec Codec, compression string,
+) error {
+	if fieldValue.CanInterface() {
+		if file, ok := fieldValue.Interface().(*os.File); ok {
+			return writeRequestFileBody(r, fieldName, file)
+		}
 	}
 
-	kind := src.Type().Kind()
+	r.Header.Set("Content-Type", codec.ContentType())
 
-	var result string
+	if fieldValue.CanInterface() {
+		jsBody, err := codec.Marshal(fieldValue.Interface())
+		if err != nil {
+			return fmt.Errorf("client generation failed, %s, of client field %s", err, fieldName)
+		}
 
-	switch kind {
-	case reflect.String:
-		result = src.String()
-	case reflect.Int:
-		result = strconv.FormatInt(src.Int(), 10)
-	case reflect.Bool:
-		result = strconv.FormatBool(src.Bool())
-	case reflect.Slice:
-		result = convertSliceToStringValue(src, urlEncode)
-		return &result
-	case reflect.Float64:
-		result = strconv.FormatFloat(src.Float(), 'f', -1, 64)
-	case reflect.Uint:
-		result = strconv.FormatUint(src.Uint(), 10)
-	case reflect.Uint64:
-		result = strconv.FormatUint(src.Uint(), 10)
-	case reflect.Float32:
-		result = strconv.FormatFloat(src.Float(), 'f', -1, 32)
-	case reflect.Int8:
-		result = strconv.FormatInt(src.Int(), 10)
-	case reflect.Uint8:
-		result = strconv.FormatUint(src.Uint(), 10)
-	case reflect.Int64:
-		result = strconv.FormatInt(src.Int(), 10)
-	case reflect.Int32:
-		result = strconv.FormatInt(src.Int(), 10)
-	case reflect.Int16:
-		result = strconv.FormatInt(src.Int(), 10)
-	case reflect.Uint16:
-		result = strconv.FormatUint(src.Uint(), 10)
-	case reflect.Uint32:
-		result = strconv.FormatUint(src.Uint(), 10)
-	case reflect.Complex64:
-		result = strconv.FormatComplex(src.Complex(), 'f', -1, 64)
-	case reflect.Complex128:
-		result = strconv.FormatComplex(src.Complex(), 'f', -1, 128)
-	case reflect.Struct:
-		if src.CanInterface() {
-			body, err := json.Marshal(src.Interface())
+		if compression != "" {
+			jsBody, err = compressBody(compression, jsBody)
 			if err != nil {
-				result = "{ \"error\": \"JSON parse error\" }"
+				return fmt.Errorf("client generation failed, %s, of client field %s", err, fieldName)
 			}
-
-			result = string(body)
-		} else {
-			result = "null"
+			r.Header.Set("Content-Encoding", compression)
 		}
-	default:
-		result = "?"
-	}
 
-	if urlEncode {
-		result = url.QueryEscape(result)
+		r.Body = io.NopCloser(bytes.NewReader(jsBody))
+		r.ContentLength = int64(len(jsBody))
+		r.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(jsBody)), nil
+		}
+	} else {
+		return fmt.Errorf("client generation failed, unable to get body of client field %s", fieldName)
 	}
 
-	return &result
+	return nil
 }
 
-func convertSliceToStringValue(value reflect.Value, urlEncode bool) string {
-	var accumulatedStrArr = make([]string, 0, value.Len())
-	for i := 0; i < value.Len(); i++ {
-		var currentStr *string
+// formURLEncodedContentType is the Content-Type of the body merged from several form fields
+const formURLEncodedContentType = "application/x-www-form-urlencoded"
 
-		currentStr = convertBaseValueToString(value.Index(i), urlEncode)
-		if currentStr == nil {
-			continue
-		}
+// writeFormFields
+//
+// writes the fields tagged 'form' as the request body. A single form field is marshaled as the whole body by
+// writeRequestBody. Several form fields are merged into one application/x-www-form-urlencoded body, rather
+// than each replacing the body written by the previous one. Form fields alongside 'multipart' or 'files'
+// fields are instead written as parts of the multipart/form-data body, in the order of their fields.
+func writeFormFields(r *http.Request, state *assignmentState) error {
+	if len(state.formFields) == 1 && len(state.multipartParts) == 0 {
+		field := state.formFields[0]
+		return writeRequestBody(r, field.fieldName, field.value, field.codec, field.compression)
+	}
 
-		if urlEncode {
-			*currentStr = url.QueryEscape(*currentStr)
+	if len(state.formFields) > 1 && len(state.multipartParts) == 0 {
+		values := url.Values{}
+		for _, field := range state.formFields {
+			fieldValues, err := readFormValues(field)
+			if err != nil {
+				return err
+			}
+			values[field.fieldName] = append(values[field.fieldName], fieldValues...)
 		}
 
-		accumulatedStrArr = append(accumulatedStrArr, *currentStr)
+		setRequestBytes(r, []byte(values.Encode()))
+		r.Header.Set(contentTypeHeader, formURLEncodedContentType)
+
+		return nil
+	}
+
+	// inserting from the last field keeps the positions of the preceding fields valid
+	for i := len(state.formFields) - 1; i >= 0; i-- {
+		part, err := readFormPart(state.formFields[i])
+		if err != nil {
+			return err
+		}
+		state.multipartParts = slices.Insert(state.multipartParts, state.formFields[i].position, part)
 	}
 
-	return strings.Join(accumulatedStrArr, ",")
+	return nil
 }
 
-type typicalClientRequestWriter func(
-		r *http.Request, fieldName string, fieldValue reflect.Value, isRequired bool,
-		urlEncode bool,
-) error
+// readFormValues
+//
+// converts a field tagged 'form' into its values in a urlencoded form body: one value per element of a slice
+// or array, a struct or map marshaled by the codec of the field, and any other value written as a query value
+// would be. A nil field, slice or map has no values. Files and compressed fields cannot be merged and result
+// in an error.
+func readFormValues(field formField) ([]string, error) {
+	if !field.value.CanInterface() {
+		return nil, fmt.Errorf("client generation failed, unable to get body of client field %s", field.fieldName)
+	}
 
-func returnClientOperationByTagValue(tagName string) typicalClientRequestWriter {
-	switch tagName {
-	case "cookie", "cookie!":
-		return writeRequestCookie
-	case "header", "header!":
-		return writeRequestHeader
-	case "query", "query!":
-		return writeRequestQueryParam
-	case "path", "path!":
-		return writeRequestPath
-	default:
-		return nil
+	if _, ok := field.value.Interface().(*os.File); ok {
+		return nil, fmt.Errorf(
+			"client generation failed, the file of client field %s cannot be merged into a form body",
+			field.fieldName,
+		)
+	}
+
+	if field.compression != "" {
+		return nil, fmt.Errorf(
+			"client generation failed, compression %s of client field %s cannot be merged into a form body",
+			field.compression, field.fieldName,
+		)
 	}
+
+	value := field.value
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil, nil
+		}
+		value = value.Elem()
+	}
+
+	if (value.Kind() == reflect.Map || value.Kind() == reflect.Slice) && value.IsNil() {
+		return nil, nil
+	}
+
+	switch {
+	case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8:
+		return []string{string(value.Bytes())}, nil
+	case value.Kind() == reflect.Slice || value.Kind() == reflect.Array:
+		values := make([]string, 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			if converted := convertBaseValueToString(value.Index(i), false, field.fieldOpts); converted != nil {
+				values = append(values, *converted)
+			}
+		}
+		return values, nil
+	case value.Kind() == reflect.Map || (value.Kind() == reflect.Struct && value.Type() != timeType):
+		body, err := field.codec.Marshal(value.Interface())
+		if err != nil {
+			return nil, fmt.Errorf("client generation failed, %s, of client field %s", err, field.fieldName)
+		}
+		return []string{string(body)}, nil
+	}
+
+	converted := convertBaseValueToString(value, false, field.fieldOpts)
+	if converted == nil {
+		return nil, nil
+	}
+
+	return []string{*converted}, nil
 }
 
-func writeRequestCookie(
-		r *http.Request, fieldName string, fieldValue reflect.Value, isRequired bool,
-		urlEncode bool,
-) error {
-	var convertedValue = convertBaseValueToString(fieldValue, urlEncode)
+// writeRequestRawBody
+//
+// sends the []byte, string or io.Reader value of the field as the request body, without marshaling it. No
+// Content-Type is set, leaving it to a header field or WithHeader. A nil field sends no body.
+func writeRequestRawBody(r *http.Request, fieldName string, fieldValue reflect.Value, isRequired bool) error {
+	if !fieldValue.CanInterface() {
+		return fmt.Errorf("client generation failed, unable to get body of client field %s", fieldName)
+	}
 
-	if isRequired {
-		if convertedValue == nil || *convertedValue == "" {
-			return fmt.Errorf("required cookie not found or not set: %s", fieldName)
+	switch fieldValue.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice:
+		if fieldValue.IsNil() {
+			if isRequired {
+				return fmt.Errorf("required raw body not found or not set: %s", fieldName)
+			}
+			return nil
 		}
 	}
 
-	var cookie = new(http.Cookie)
-	cookie.Name = fieldName
+	var raw []byte
 
-	if convertedValue != nil {
-		cookie.Value = *convertedValue
-	} else {
-		cookie.Value = ""
+	switch body := fieldValue.Interface().(type) {
+	case []byte:
+		raw = body
+	case string:
+		raw = []byte(body)
+	case io.Reader:
+		return writeRequestReaderBody(r, fieldName, body)
+	default:
+		return fmt.Errorf(
+			"client generation failed, raw body of client field %s must be a []byte, string or io.Reader, got %T",
+			fieldName, body,
+		)
 	}
 
-	r.AddCookie(cookie)
+	setRequestBytes(r, raw)
 
 	return nil
 }
 
-func writeRequestHeader(
-		r *http.Request, fieldName string, fieldValue reflect.Value, isRequired bool,
-		urlEncode bool,
-) error {
-	var convertedValue = convertBaseValueToString(fieldValue, urlEncode)
+// setRequestBytes
+//
+// sets the bytes as the request body, replayable through GetBody
+func setRequestBytes(r *http.Request, raw []byte) {
+	r.Body = io.NopCloser(bytes.NewReader(raw))
+	r.ContentLength = int64(len(raw))
+	r.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(raw)), nil
+	}
+}
 
-	if isRequired {
-		if convertedValue == nil || *convertedValue == "" {
-			return fmt.Errorf("required header not found or not set: %s", fieldName)
+// writeRequestReaderBody
+//
+// streams the reader as the request body. In-memory readers and seekable readers, such as files, get their
+// Content-Length and may be replayed through GetBody, a seekable reader from the offset it was given at.
+// Any other reader is sent chunked and only once. The reader is never closed by the transport; it remains
+// owned by the caller.
+func writeRequestReaderBody(r *http.Request, fieldName string, reader io.Reader) error {
+	switch reader.(type) {
+	case *bytes.Buffer, *bytes.Reader, *strings.Reader:
+		// the length and replay of in-memory readers are known to http.NewRequest
+		sized, err := http.NewRequest(r.Method, r.URL.String(), reader)
+		if err != nil {
+			return fmt.Errorf("client generation failed, %s, of client field %s", err, fieldName)
 		}
+
+		r.Body, r.ContentLength, r.GetBody = sized.Body, sized.ContentLength, sized.GetBody
+
+		return nil
 	}
 
-	if convertedValue != nil {
-		r.Header.Add(fieldName, *convertedValue)
-	} else {
-		r.Header.Add(fieldName, "")
+	r.Body = io.NopCloser(reader)
+	r.ContentLength = -1
+	r.GetBody = nil
+
+	seeker, ok := reader.(io.Seeker)
+	if !ok {
+		return nil
+	}
+
+	offset, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		// not every io.Seeker is able to seek, such as a pipe opened as a file
+		return nil
+	}
+
+	if end, err := seeker.Seek(0, io.SeekEnd); err == nil {
+		r.ContentLength = end - offset
+	}
+
+	if _, err = seeker.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("client generation failed, %s, unable to rewind body of client field %s", err, fieldName)
+	}
+
+	r.GetBody = func() (io.ReadCloser, error) {
+		if _, err := seeker.Seek(offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+		return io.NopCloser(reader), nil
 	}
 
 	return nil
 }
 
-func writeRequestQueryParam(
-		r *http.Request, fieldName string, fieldValue reflect.Value, isRequired bool, urlEncode bool,
-) error {
-	var convertedValue = convertBaseValueToString(fieldValue, false)
+// writeSelectedBody
+//
+// replaces the body of the request with the body chosen by the selector for the Accept header the
+// request is sent with
+func writeSelectedBody(r *http.Request, selector BodyEncodingSelector, cfg *ClientConfig) error {
+	accept := cfg.Accept
+	if accept == "" {
+		accept = r.Header.Get("Accept")
+	}
 
-	if isRequired {
-		if convertedValue == nil || *convertedValue == "" {
-			return fmt.Errorf("required header not found or not set: %s", fieldName)
-		}
+	contentType, bodyFunc := selector.SelectBodyEncoding(accept)
+	if contentType == "" || bodyFunc == nil {
+		return nil
 	}
 
-	if convertedValue != nil {
-		reqQuery := r.URL.Query()
-		reqQuery.Add(fieldName, *convertedValue)
-		r.URL.RawQuery = reqQuery.Encode()
-	} else {
-		reqQuery := r.URL.Query()
-		reqQuery.Add(fieldName, "")
-		r.URL.RawQuery = reqQuery.Encode()
+	body, err := bodyFunc()
+	if err != nil {
+		return err
+	}
+
+	r.Header.Set("Content-Type", contentType)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.ContentLength = int64(len(body))
+	r.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
 	}
 
 	return nil
 }
 
-func writeRequestBody(r *http.Request, fieldName string, fieldValue reflect.Value) error {
-	r.Header.Set("Content-Type", "application/json")
+// writeRequestFileBody
+//
+// streams the file as the request body. The file is rewound to the start so that GetBody may
+// replay it and the Content-Length is taken from the file size. When the file size is unavailable
+// the body is sent chunked. The file is never closed by the transport; it remains owned by the caller.
+func writeRequestFileBody(r *http.Request, fieldName string, file *os.File) error {
+	if file == nil {
+		return nil
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("client generation failed, %s, unable to rewind file of client field %s", err, fieldName)
+	}
 
-	if fieldValue.CanInterface() {
-		jsBody, err := json.Marshal(fieldValue.Interface())
-		if err != nil {
-			return fmt.Errorf("client generation failed, %s, of client field %s", err, fieldName)
+	r.Header.Set("Content-Type", "application/octet-stream")
+	r.Body = io.NopCloser(file)
+	r.GetBody = func() (io.ReadCloser, error) {
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return nil, err
 		}
+		return io.NopCloser(file), nil
+	}
 
-		r.Body = io.NopCloser(bytes.NewReader(jsBody))
+	if info, err := file.Stat(); err == nil && info.Mode().IsRegular() {
+		r.ContentLength = info.Size()
 	} else {
-		return fmt.Errorf("client generation failed, unable to get body of client field %s", fieldName)
+		r.ContentLength = -1
 	}
 
 	return nil
@@ -574,12 +2774,13 @@ func writeRequestBody(r *http.Request, fieldName string, fieldValue reflect.Valu
 
 func writeRequestPath(
 		r *http.Request, fieldName string, fieldValue reflect.Value, isRequired bool,
-		urlEncode bool,
+		urlEncode bool, fieldOpts clientFieldOptions,
 ) error {
-	var convertedValue = convertBaseValueToString(fieldValue, urlEncode)
+	// path values are always path escaped, the query escaping of urlEncode does not apply to them
+	var convertedValue = convertBaseValueToString(fieldValue, false, fieldOpts)
 
 	if isRequired {
-		if convertedValue == nil || *convertedValue == "" {
+		if isMissingRequired(fieldValue, convertedValue, fieldOpts) {
 			return fmt.Errorf("required path variable not found or not set: %s", fieldName)
 		}
 	}
@@ -595,13 +2796,31 @@ func writeRequestPath(
 		)
 	}
 
+	var value, escapedValue string
+
 	if convertedValue != nil {
-		path = strings.Replace(path, replaceableString, *convertedValue, -1)
-	} else {
-		path = strings.Replace(r.URL.Path, replaceableString, "", -1)
+		value = *convertedValue
+		escapedValue = fieldOpts.pathEscape(value)
+
+		// a raw value is substituted as given, its escapes and slashes reaching the server untouched
+		if fieldOpts.rawPath {
+			escapedValue = value
+			if unescaped, err := url.PathUnescape(value); err == nil {
+				value = unescaped
+			}
+		}
+	}
+
+	rawPath := r.URL.RawPath
+	if rawPath == "" {
+		rawPath = r.URL.EscapedPath()
 	}
 
-	r.URL.Path = path
+	rawPath = strings.Replace(rawPath, replaceableString, escapedValue, -1)
+	rawPath = strings.Replace(rawPath, "%7B"+fieldName+"%7D", escapedValue, -1)
+
+	r.URL.Path = strings.Replace(path, replaceableString, value, -1)
+	r.URL.RawPath = rawPath
 
 	return nil
 }
@@ -0,0 +1,984 @@
+package gkBoot
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yomiji/gkBoot/logging"
+)
+
+// DuplicateQueryKeyMode
+//
+// Determines the handling of two or more request fields that resolve to the same query parameter name,
+// for example through their aliases.
+type DuplicateQueryKeyMode int
+
+const (
+	// DuplicateQueryKeyAllow adds the value of every field under the shared name. A warning is written to
+	// the client logger, when one is configured.
+	DuplicateQueryKeyAllow DuplicateQueryKeyMode = iota
+	// DuplicateQueryKeyError fails the request generation.
+	DuplicateQueryKeyError
+	// DuplicateQueryKeyMerge joins the values of every field into a single comma separated value.
+	DuplicateQueryKeyMerge
+)
+
+// RedirectPolicy
+//
+// Decides whether a redirect is followed, with the semantics of http.Client.CheckRedirect: req is the upcoming
+// request and via holds the requests made so far, oldest first. Returning http.ErrUseLastResponse stops
+// following and hands the 3xx response to the caller, any other error fails the request. Any function of
+// this signature may be given to WithRedirectPolicy.
+type RedirectPolicy func(req *http.Request, via []*http.Request) error
+
+var (
+	// FollowRedirects follows up to 10 redirects, as http.DefaultClient does, even when the configured
+	// HTTPClient would not.
+	FollowRedirects RedirectPolicy = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return nil
+	}
+	// NoFollowRedirects returns the 3xx response as is, so that its status and headers, such as Location,
+	// reach the response object.
+	NoFollowRedirects RedirectPolicy = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+)
+
+// Clock
+//
+// Returns the current time, see WithClock.
+type Clock func() time.Time
+
+// StatusRange
+//
+// An inclusive range of response status codes
+type StatusRange struct {
+	From int
+	To   int
+}
+
+var (
+	// Status2xx holds the successful status codes
+	Status2xx = StatusRange{From: 200, To: 299}
+	// Status4xx holds the client error status codes
+	Status4xx = StatusRange{From: 400, To: 499}
+	// Status5xx holds the server error status codes
+	Status5xx = StatusRange{From: 500, To: 599}
+	// StatusErrors holds both the client and server error status codes
+	StatusErrors = StatusRange{From: 400, To: 599}
+)
+
+// Contains
+//
+// Reports whether the status code lies within the range
+func (s StatusRange) Contains(status int) bool {
+	return status >= s.From && status <= s.To
+}
+
+// statusDecoder
+//
+// a decoder chosen for the responses with a status in its range
+type statusDecoder struct {
+	codes   StatusRange
+	decoder Decoder
+}
+
+// ClientConfig
+//
+// Used by gkBoot.GenerateClientRequestWithOptions, gkBoot.DoRequestWithOptions and
+// gkBoot.DoGeneratedRequestWithOptions to alter the way a client request is generated and sent and the way
+// its response is processed. Each option has a default value.
+type ClientConfig struct {
+	// HTTPClient
+	//
+	//  Default value: http.DefaultClient
+	//
+	// The client used to send the request.
+	HTTPClient *http.Client
+	// TLSConfig
+	//
+	//  Default value: DefaultTLSConfig()
+	//
+	// The TLS configuration used by the transport when sending the request. When set, the request is sent
+	// by a copy of HTTPClient whose transport is a clone of its *http.Transport (or of http.DefaultTransport)
+	// using this configuration, negotiating HTTP/2 or HTTP/1.1 with the server through ALPN; HTTPClient
+	// itself is never modified.
+	TLSConfig *tls.Config
+	// ForceHTTP2
+	//
+	//  Default value: false
+	//
+	// Sends the request over TLS with an HTTP/2 transport, using the TLSConfig when set, for servers that
+	// require HTTP/2 without negotiating it. Servers only speaking HTTP/1.1 cannot be reached this way.
+	ForceHTTP2 bool
+	// StatusValidators
+	//
+	//  Default value: nil
+	//
+	// Response body validators keyed by the status code of the response. The validator matching the
+	// status of the response receives the raw body before any decoding takes place. A non-nil result
+	// aborts the request and is returned to the caller. Responses with a status not present in the map
+	// are not validated.
+	StatusValidators map[int]func([]byte) error
+	// QueryValues
+	//
+	//  Default value: nil
+	//
+	// Additional query parameters merged into the query of the generated request after the request
+	// object fields have been assigned. Values for a key already present are appended after the existing
+	// values.
+	QueryValues url.Values
+	// DuplicateQueryKeys
+	//
+	//  Default value: DuplicateQueryKeyAllow
+	//
+	// The handling of request fields that resolve to the same query parameter name.
+	DuplicateQueryKeys DuplicateQueryKeyMode
+	// Logger
+	//
+	//  Default value: nil
+	//
+	// Receives warnings about questionable requests, such as duplicate query parameter names. Nothing
+	// is logged when nil.
+	Logger logging.Logger
+	// ResponseHooks
+	//
+	//  Default value: []
+	//
+	// Functions invoked in order with the response object after it has been successfully decoded and
+	// before the request returns. Hooks may modify the response object. The first hook returning a
+	// non-nil error aborts the request with that error.
+	ResponseHooks []func(responseObj any) error
+	// Accept
+	//
+	//  Default value: ""
+	//
+	// Overrides the Accept header of the request. When empty, generated requests list the media types of
+	// the registered decoders (see RegisterDecoder) in order of preference.
+	Accept string
+	// UploadProgress
+	//
+	//  Default value: nil
+	//
+	// Receives the number of request body bytes consumed by the transport so far and the total number of
+	// bytes to send, taken from the Content-Length of the request. The total is -1 when unknown.
+	UploadProgress func(bytesSent, total int64)
+	// DownloadProgress
+	//
+	//  Default value: nil
+	//
+	// Receives the number of response body bytes read so far and the total number of bytes expected, taken
+	// from the Content-Length of the response. The total is -1 when unknown. Progress is reported however
+	// the body is consumed, whether decoded or given to a response.CaptureReader.
+	DownloadProgress func(bytesRead, total int64)
+	// ResponseWriter
+	//
+	//  Default value: nil
+	//
+	// Receives the body of a 2xx response, decompressed, in place of decoding it into the response object.
+	// The number of bytes written is stored in ResponseWritten when it is not nil. Bodies of other statuses
+	// are handled as usual.
+	ResponseWriter io.Writer
+	// ResponseWritten
+	//
+	//  Default value: nil
+	//
+	// Receives the number of bytes written to the ResponseWriter.
+	ResponseWritten *int64
+	// Headers
+	//
+	//  Default value: nil
+	//
+	// Headers set on the request after the request object fields have been assigned, replacing any
+	// value a field assigned for the same name.
+	Headers http.Header
+	// SchemaValidator
+	//
+	//  Default value: nil
+	//
+	// Validates the raw body of every 2xx response before any decoding takes place, usually against a
+	// JSON Schema bound by WithJSONSchema. A non-nil result aborts the request and is returned to the
+	// caller wrapped in the request details.
+	SchemaValidator func(body []byte) error
+	// ErrorDecoder
+	//
+	//  Default value: nil
+	//
+	// Decodes the body of a non-2xx response into a typed error, usually installed by WithErrorType. When the
+	// decoder reports false, for example because the body is empty or not JSON, the response is handled
+	// as it would be without a decoder.
+	ErrorDecoder func(resp *http.Response, body []byte) (error, bool)
+	// RetryPolicy
+	//
+	//  Default value: nil
+	//
+	// Sends failed requests again according to the policy. Requests are sent once when nil.
+	RetryPolicy *RetryPolicy
+	// RetryBudget
+	//
+	//  Default value: nil
+	//
+	// Caps the retries of the RetryPolicy across every request sharing the budget. When the budget is
+	// exhausted the result of the last attempt is returned without retrying.
+	RetryBudget *RetryBudget
+	// RateLimiter
+	//
+	//  Default value: nil
+	//
+	// Pauses every request sharing the limiter, including retries, while a response has reported the rate
+	// limit of the server exhausted.
+	RateLimiter *RateLimiter
+	// RequestCompression
+	//
+	//  Default value: ""
+	//
+	// The Content-Encoding used to compress marshaled request bodies, currently only "gzip". A 'compress' tag on
+	// the body field takes precedence. Bodies are sent uncompressed when empty.
+	RequestCompression string
+	// Close
+	//
+	//  Default value: false
+	//
+	// Sets the Close flag of the request, sending Connection: close and closing the connection once the
+	// response has been read.
+	Close bool
+	// SniffContentType
+	//
+	//  Default value: false
+	//
+	// Chooses the decoder of a response sent without a Content-Type by inspecting the start of the body:
+	// JSON for a leading '{' or '[' and the registered XML decoder for a leading '<'. Responses without a
+	// Content-Type are decoded as JSON when false.
+	SniffContentType bool
+	// Correlate
+	//
+	//  Default value: false
+	//
+	// Sends a correlation ID with the request in the CorrelationHeader and gives the ID echoed by the
+	// server to a response object implementing response.CorrelationReceiver. The echoed header is also
+	// visible to a response.HeaderReceiver.
+	Correlate bool
+	// CorrelationID
+	//
+	//  Default value: ""
+	//
+	// The correlation ID sent when Correlate is set. A random UUID is generated for every request when empty.
+	CorrelationID string
+	// CorrelationHeader
+	//
+	//  Default value: "X-Correlation-ID"
+	//
+	// The header carrying the correlation ID.
+	CorrelationHeader string
+	// StrictRequired
+	//
+	//  Default value: false
+	//
+	// A required field (tagged with a trailing '!') fails only when it is missing, that is a nil pointer,
+	// slice, map or interface. Zero numbers, false booleans and empty strings are present values. When
+	// set, fields writing an empty value, such as an empty string or an empty slice, fail as well.
+	StrictRequired bool
+	// FailFast
+	//
+	//  Default value: false
+	//
+	// Request generation collects every field error, together with the error of Validate, into a single
+	// error joined by errors.Join. When set, generation stops at the first error instead.
+	FailFast bool
+	// JSONEncoder
+	//
+	//  Default value: nil
+	//
+	// Creates the encoder used for JSON request bodies, for example to disable HTML escaping. The codec
+	// registered for application/json is used when nil.
+	JSONEncoder func(w io.Writer) *json.Encoder
+	// JSONDecoder
+	//
+	//  Default value: nil
+	//
+	// Creates the decoder used for JSON response bodies, for example to enable DisallowUnknownFields or
+	// UseNumber. The codec registered for application/json is used when nil.
+	JSONDecoder func(r io.Reader) *json.Decoder
+	// BodyNameMapper
+	//
+	//  Default value: nil
+	//
+	// Renames the fields of JSON request bodies as they are marshaled, receiving the json name of each field,
+	// or its field name, and returning the name sent. The fields of nested structs are renamed as well, while
+	// map keys and values implementing json.Marshaler are sent unchanged. Names are sent as tagged when nil.
+	BodyNameMapper func(name string) string
+	// BufferPool
+	//
+	//  Default value: nil
+	//
+	// Supplies the buffers response bodies are read into before decoding, returning each buffer once
+	// decoding completes. Bodies given to a response.StreamReceiver or response.CaptureReader are never
+	// buffered and bodies given to a response.RawBodyReceiver are never returned to the pool. A new buffer
+	// is allocated for every response when nil.
+	BufferPool *BufferPool
+	// Timeout
+	//
+	//  Default value: 0
+	//
+	// Bounds the whole call, from sending the request through every retry to reading the response body. The
+	// deadline is derived from the context of the request, so the earlier of the two deadlines wins. The call
+	// is bounded by the context alone when zero.
+	Timeout time.Duration
+	// RedirectPolicy
+	//
+	//  Default value: nil
+	//
+	// Decides whether redirects are followed. When set, the request is sent by a copy of HTTPClient using the
+	// policy as its CheckRedirect; HTTPClient itself is never modified. The CheckRedirect of HTTPClient
+	// applies when nil.
+	RedirectPolicy RedirectPolicy
+	// LocalAddr
+	//
+	//  Default value: nil
+	//
+	// The local IP outbound connections are bound to, for hosts with more than one address. When set, the
+	// request is sent by a copy of HTTPClient whose transport is a clone of its *http.Transport (or of
+	// http.DefaultTransport) dialing from the address; neither HTTPClient nor its transport is modified. A
+	// custom dialer of the transport is replaced and a transport other than *http.Transport fails the
+	// request. Connections are dialed by the operating system default when nil.
+	LocalAddr net.IP
+	// Middleware
+	//
+	//  Default value: []
+	//
+	// Wraps the sending of the request in order, the first being the outermost. The chain runs once per call,
+	// around all attempts of the RetryPolicy, and receives the request after every other option has been
+	// applied.
+	Middleware []Middleware
+	// FieldErrorsDecoder
+	//
+	//  Default value: nil
+	//
+	// Decodes the body of a 422 Unprocessable Entity response into the field errors it reports, which are
+	// returned as a *ValidationError. Takes precedence over the ErrorDecoder for 422 responses. When the
+	// decoder fails, the response is handled as it would be without a decoder.
+	FieldErrorsDecoder func(body []byte) ([]FieldError, error)
+	// statusDecoders are chosen by the status of the response in place of the decoder registered for its
+	// Content-Type, only set with WithStatusDecoder. The first decoder whose range contains the status is used.
+	statusDecoders []statusDecoder
+	// statusErrors are returned, wrapped in a *StatusError, for the responses with a status in their range, only
+	// set with WithStatusError. The first error whose range contains the status is used. Takes precedence over
+	// the ErrorDecoder, whose result is kept as the Cause of the StatusError.
+	statusErrors []statusError
+	// NotFoundAsAbsent
+	//
+	//  Default value: false
+	//
+	// A 404 Not Found is a signal rather than an error, set with WithNotFoundAsAbsent. A response object
+	// implementing response.Optional is marked absent and no error is returned, any other response object
+	// results in a *StatusError wrapping ErrNotFound. Takes precedence over the handling of the response body.
+	NotFoundAsAbsent bool
+	// ContentChecksum
+	//
+	//  Default value: ""
+	//
+	// The digest of the request body sent with the request, ChecksumMD5 in the Content-MD5 header or
+	// ChecksumSHA256 in the x-amz-content-sha256 header. The digest covers the body as sent, after any
+	// compression. No digest is sent when empty.
+	ContentChecksum ChecksumAlgorithm
+	// DateHeader
+	//
+	//  Default value: false
+	//
+	// Sends the current time of the Clock in the Date header, formatted as an HTTP-date, as required by
+	// signing schemes covering the date of the request.
+	DateHeader bool
+	// DeadlineHeader
+	//
+	//  Default value: ""
+	//
+	// The header carrying the time left until the deadline of the request context, formatted as a gRPC
+	// timeout such as 5S or 100m, see FormatGrpcTimeout. A shorter Timeout is sent in its place. Nothing is
+	// sent when empty or when there is neither a deadline nor a Timeout.
+	DeadlineHeader string
+	// Escaper
+	//
+	//  Default value: nil
+	//
+	// Escapes the names and values of the query parameters, the path values and the values of fields tagged
+	// with urlEncode, for servers expecting an escaping other than that of the standard library, such as
+	// EscapeRFC3986. The query is escaped by url.Values.Encode and the path values by url.PathEscape when nil.
+	Escaper func(value string) string
+	// Clock
+	//
+	//  Default value: time.Now
+	//
+	// The source of the current time of the client, replaced to control the time seen by the client in tests.
+	Clock Clock
+}
+
+// JSONSchemaValidator
+//
+// Validates the body against the JSON Schema document, returning an error describing each violation. The
+// validator is supplied by the caller so that any JSON Schema implementation may be used.
+type JSONSchemaValidator func(schema, body []byte) error
+
+// ClientOption
+//
+// Option type used when sending client requests.
+type ClientOption func(config *ClientConfig)
+
+func newClientConfig(opts ...ClientOption) *ClientConfig {
+	cfg := &ClientConfig{TLSConfig: DefaultTLSConfig()}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	if cfg.Clock == nil {
+		cfg.Clock = time.Now
+	}
+
+	return cfg
+}
+
+// WithHTTPClient
+//
+// Set the client used to send the request, in place of http.DefaultClient
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(config *ClientConfig) {
+		config.HTTPClient = client
+	}
+}
+
+// WithClientTLS
+//
+// Set the TLS configuration used by the transport when sending the request, in place of the one set by
+// SetDefaultTLSConfig. The transport built for the configuration is cached by its pointer, so the same
+// configuration should be reused across calls, or owned by a Client created with NewClient.
+func WithClientTLS(tlsConfig *tls.Config) ClientOption {
+	return func(config *ClientConfig) {
+		config.TLSConfig = tlsConfig
+	}
+}
+
+var (
+	defaultTLSLock sync.RWMutex
+	// defaultTLSConfig is the TLS configuration of every request not given one by WithClientTLS
+	defaultTLSConfig *tls.Config
+)
+
+// SetDefaultTLSConfig
+//
+// Sets the TLS configuration used by every request not given one by WithClientTLS or DoGeneratedRequest, such
+// as one trusting a private certificate authority. The configuration must not be modified once set. A nil
+// configuration restores the TLS configuration of the transport. Safe for concurrent use, requests already
+// being sent keep the configuration they started with.
+func SetDefaultTLSConfig(tlsConfig *tls.Config) {
+	defaultTLSLock.Lock()
+	defer defaultTLSLock.Unlock()
+
+	defaultTLSConfig = tlsConfig
+}
+
+// DefaultTLSConfig
+//
+// Returns the TLS configuration set by SetDefaultTLSConfig, nil when none is set.
+func DefaultTLSConfig() *tls.Config {
+	defaultTLSLock.RLock()
+	defer defaultTLSLock.RUnlock()
+
+	return defaultTLSConfig
+}
+
+// WithForceHTTP2
+//
+// Send the request with an HTTP/2 transport rather than negotiating the protocol with the server, see
+// WithClientTLS
+func WithForceHTTP2() ClientOption {
+	return func(config *ClientConfig) {
+		config.ForceHTTP2 = true
+	}
+}
+
+// WithStatusValidator
+//
+// Validate the raw response body using the validator keyed by the status code of the response. Validation
+// happens before the body is decoded, so a request may, for example, validate successful bodies while
+// leaving error bodies untouched. Every invocation merges the given validators into those already set.
+func WithStatusValidator(validators map[int]func([]byte) error) ClientOption {
+	return func(config *ClientConfig) {
+		if config.StatusValidators == nil {
+			config.StatusValidators = make(map[int]func([]byte) error, len(validators))
+		}
+		for code, validator := range validators {
+			config.StatusValidators[code] = validator
+		}
+	}
+}
+
+// WithQueryValues
+//
+// Merge the given query parameters into the query of the generated request. Every invocation adds the given
+// values to those already set.
+func WithQueryValues(values url.Values) ClientOption {
+	return func(config *ClientConfig) {
+		if config.QueryValues == nil {
+			config.QueryValues = make(url.Values, len(values))
+		}
+		for key, vals := range values {
+			config.QueryValues[key] = append(config.QueryValues[key], vals...)
+		}
+	}
+}
+
+// WithDuplicateQueryKeys
+//
+// Set the handling of request fields that resolve to the same query parameter name
+func WithDuplicateQueryKeys(mode DuplicateQueryKeyMode) ClientOption {
+	return func(config *ClientConfig) {
+		config.DuplicateQueryKeys = mode
+	}
+}
+
+// WithClientLogger
+//
+// Set a logger that receives warnings about questionable requests
+func WithClientLogger(logger logging.Logger) ClientOption {
+	return func(config *ClientConfig) {
+		config.Logger = logger
+	}
+}
+
+// WithResponseHook
+//
+// Appends the given hook to the end of the response hook chain. Hooks run after the response object has been
+// successfully decoded, so they may be used to normalize responses across many requests.
+func WithResponseHook(hook func(responseObj any) error) ClientOption {
+	return func(config *ClientConfig) {
+		config.ResponseHooks = append(config.ResponseHooks, hook)
+	}
+}
+
+// WithAccept
+//
+// Override the Accept header of the request, which by default lists the media types of the registered decoders
+func WithAccept(accept string) ClientOption {
+	return func(config *ClientConfig) {
+		config.Accept = accept
+	}
+}
+
+// WithUploadProgress
+//
+// Report the progress of sending the request body to the given callback as the body is consumed by the transport
+func WithUploadProgress(progress func(bytesSent, total int64)) ClientOption {
+	return func(config *ClientConfig) {
+		config.UploadProgress = progress
+	}
+}
+
+// WithResponseWriter
+//
+// Copy the body of a 2xx response into w rather than decoding it, for example to download it to a file.
+// When written is not nil, it receives the number of bytes written.
+func WithResponseWriter(w io.Writer, written *int64) ClientOption {
+	return func(config *ClientConfig) {
+		config.ResponseWriter = w
+		config.ResponseWritten = written
+	}
+}
+
+// WithDownloadProgress
+//
+// Report the progress of reading the response body to the given callback as the body is consumed
+func WithDownloadProgress(progress func(bytesRead, total int64)) ClientOption {
+	return func(config *ClientConfig) {
+		config.DownloadProgress = progress
+	}
+}
+
+// WithHeader
+//
+// Set the header on the request, replacing any value assigned by the request object. Every invocation adds
+// to the headers already set.
+func WithHeader(key, value string) ClientOption {
+	return func(config *ClientConfig) {
+		if config.Headers == nil {
+			config.Headers = make(http.Header)
+		}
+		config.Headers.Add(key, value)
+	}
+}
+
+// WithJSONSchema
+//
+// Validate the body of every 2xx response against the given JSON Schema using the validator, before the body
+// is decoded. Useful for contract testing, where a response that decodes loosely must still be rejected.
+func WithJSONSchema(schema []byte, validator JSONSchemaValidator) ClientOption {
+	return func(config *ClientConfig) {
+		config.SchemaValidator = func(body []byte) error {
+			return validator(schema, body)
+		}
+	}
+}
+
+// WithErrorType
+//
+// Decode the JSON body of non-2xx responses into a new ErrorType and return it as the error of the request, so
+// callers may retrieve it with errors.As. Empty bodies, non-JSON content types and bodies that fail to decode
+// are handled as they would be without this option.
+func WithErrorType[ErrorType error]() ClientOption {
+	return func(config *ClientConfig) {
+		config.ErrorDecoder = decodeTypedError[ErrorType]
+	}
+}
+
+func decodeTypedError[ErrorType error](resp *http.Response, body []byte) (error, bool) {
+	if len(bytes.TrimSpace(body)) == 0 || !isJSONContentType(resp.Header.Get("Content-Type")) {
+		return nil, false
+	}
+
+	target := new(ErrorType)
+	if err := json.Unmarshal(body, target); err != nil {
+		return nil, false
+	}
+
+	return *target, true
+}
+
+// isJSONContentType
+//
+// reports whether the media type is application/json or a +json suffixed type, an absent type counts as JSON
+func isJSONContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// WithRetryPolicy
+//
+// Retry failed requests according to the given policy
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(config *ClientConfig) {
+		config.RetryPolicy = &policy
+	}
+}
+
+// WithGzipRequestBody
+//
+// Gzip compress marshaled request bodies, setting the Content-Encoding header and the compressed Content-Length
+func WithGzipRequestBody() ClientOption {
+	return func(config *ClientConfig) {
+		config.RequestCompression = compressionGzip
+	}
+}
+
+// WithClose
+//
+// Close the connection after the request by sending Connection: close, useful for servers that mishandle
+// keep-alive. WithClose(false) leaves the request as generated.
+func WithClose(closeConnection bool) ClientOption {
+	return func(config *ClientConfig) {
+		config.Close = closeConnection
+	}
+}
+
+// WithContentSniffing
+//
+// Choose the decoder of responses missing a Content-Type by inspecting the body, for servers that omit the header
+func WithContentSniffing() ClientOption {
+	return func(config *ClientConfig) {
+		config.SniffContentType = true
+	}
+}
+
+// WithCorrelationID
+//
+// Send the correlation ID with the request and read back the ID echoed by the server, see
+// response.Correlated. A random UUID is generated for every request when the ID is empty.
+func WithCorrelationID(id string) ClientOption {
+	return func(config *ClientConfig) {
+		config.Correlate = true
+		config.CorrelationID = id
+	}
+}
+
+// WithCorrelationHeader
+//
+// Set the header carrying the correlation ID, in place of X-Correlation-ID
+func WithCorrelationHeader(header string) ClientOption {
+	return func(config *ClientConfig) {
+		config.CorrelationHeader = header
+	}
+}
+
+// WithStrictRequired
+//
+// Reject required fields that write an empty value, such as an empty string, in addition to missing ones
+func WithStrictRequired() ClientOption {
+	return func(config *ClientConfig) {
+		config.StrictRequired = true
+	}
+}
+
+// WithFailFast
+//
+// Stop request generation at the first validation or field error, rather than reporting all of them
+func WithFailFast() ClientOption {
+	return func(config *ClientConfig) {
+		config.FailFast = true
+	}
+}
+
+// WithRateLimiter
+//
+// Share the given limiter between requests, so that a 429 or an exhausted rate limit reported to one of them
+// pauses the others until the limit resets
+func WithRateLimiter(limiter *RateLimiter) ClientOption {
+	return func(config *ClientConfig) {
+		config.RateLimiter = limiter
+	}
+}
+
+// WithIdempotentRetries
+//
+// Retry failed requests with the DefaultRetryPolicy, which only retries idempotent methods, and a POST or
+// PATCH carrying an idempotency key (see WithIdempotencyKey), so that a retry never duplicates a side effect
+func WithIdempotentRetries() ClientOption {
+	return func(config *ClientConfig) {
+		policy := DefaultRetryPolicy
+		policy.Retryable = DefaultRetryable
+		config.RetryPolicy = &policy
+	}
+}
+
+// WithIdempotencyKey
+//
+// Send the key in the Idempotency-Key header, allowing a POST or PATCH to be retried by DefaultRetryable
+func WithIdempotencyKey(key string) ClientOption {
+	return WithHeader(IdempotencyKeyHeader, key)
+}
+
+// WithRetryBudget
+//
+// Share the given budget between requests so that their combined retries are capped. Only takes effect
+// together with a RetryPolicy.
+func WithRetryBudget(budget *RetryBudget) ClientOption {
+	return func(config *ClientConfig) {
+		config.RetryBudget = budget
+	}
+}
+
+// WithJSONEncoder
+//
+// Encode JSON request bodies with encoders created by the given factory
+func WithJSONEncoder(factory func(w io.Writer) *json.Encoder) ClientOption {
+	return func(config *ClientConfig) {
+		config.JSONEncoder = factory
+	}
+}
+
+// WithJSONDecoder
+//
+// Decode JSON response bodies with decoders created by the given factory
+func WithJSONDecoder(factory func(r io.Reader) *json.Decoder) ClientOption {
+	return func(config *ClientConfig) {
+		config.JSONDecoder = factory
+	}
+}
+
+// WithBodyNameMapper
+//
+// Rename the fields of JSON request bodies with the given mapper, such as to send the capitalized names a
+// partner expects without a parallel set of request structs
+func WithBodyNameMapper(mapper func(name string) string) ClientOption {
+	return func(config *ClientConfig) {
+		config.BodyNameMapper = mapper
+	}
+}
+
+// WithStrictJSON
+//
+// Fail the request when a JSON response body holds a field unknown to the response object. The error names
+// the unexpected field.
+func WithStrictJSON() ClientOption {
+	return WithJSONDecoder(
+		func(r io.Reader) *json.Decoder {
+			decoder := json.NewDecoder(r)
+			decoder.DisallowUnknownFields()
+			return decoder
+		},
+	)
+}
+
+// WithBufferPool
+//
+// Read response bodies into buffers reused from the given pool
+func WithBufferPool(pool *BufferPool) ClientOption {
+	return func(config *ClientConfig) {
+		config.BufferPool = pool
+	}
+}
+
+// WithTimeout
+//
+// Bound the time the call may take, including retries and reading the response body. A request exceeding the
+// timeout fails with an error wrapping context.DeadlineExceeded.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(config *ClientConfig) {
+		config.Timeout = timeout
+	}
+}
+
+// WithRedirectPolicy
+//
+// Decide whether redirects are followed with the given policy, FollowRedirects, NoFollowRedirects or a custom
+// function
+func WithRedirectPolicy(policy RedirectPolicy) ClientOption {
+	return func(config *ClientConfig) {
+		config.RedirectPolicy = policy
+	}
+}
+
+// WithLocalAddr
+//
+// Bind outbound connections to the given local IP, for example to select the source address on a host with
+// several network interfaces
+func WithLocalAddr(ip net.IP) ClientOption {
+	return func(config *ClientConfig) {
+		config.LocalAddr = ip
+	}
+}
+
+// WithMiddleware
+//
+// Appends the given middleware to the end of the middleware chain
+func WithMiddleware(middleware ...Middleware) ClientOption {
+	return func(config *ClientConfig) {
+		config.Middleware = append(config.Middleware, middleware...)
+	}
+}
+
+// WithFieldErrorsDecoder
+//
+// Decode 422 Unprocessable Entity responses into a *ValidationError holding the field errors produced by
+// the decoder, so server validation failures may be handled programmatically
+func WithFieldErrorsDecoder(decoder func(body []byte) ([]FieldError, error)) ClientOption {
+	return func(config *ClientConfig) {
+		config.FieldErrorsDecoder = decoder
+	}
+}
+
+// WithStatusDecoder
+//
+// Decode the bodies of responses with a status within the range by the given decoder, regardless of their
+// Content-Type, for APIs whose error bodies use another format than their successful ones:
+//
+//	gkBoot.WithStatusDecoder(gkBoot.StatusErrors, gkBoot.TextDecoder{})
+//
+// Every invocation adds a decoder after those already set, the first matching range taking precedence.
+func WithStatusDecoder(codes StatusRange, decoder Decoder) ClientOption {
+	return func(config *ClientConfig) {
+		config.statusDecoders = append(config.statusDecoders, statusDecoder{codes: codes, decoder: decoder})
+	}
+}
+
+// WithStatusError
+//
+// Return the given error, wrapped in a *StatusError, for the responses with a status within the range, so
+// callers may match sentinel errors with errors.Is:
+//
+//	gkBoot.WithStatusError(gkBoot.StatusRange{From: 401, To: 401}, gkBoot.ErrUnauthorized)
+//	gkBoot.WithStatusError(gkBoot.StatusRange{From: 429, To: 429}, gkBoot.ErrRateLimited)
+//
+// Every invocation adds a mapping after those already set, the first matching range taking precedence.
+func WithStatusError(codes StatusRange, err error) ClientOption {
+	return func(config *ClientConfig) {
+		config.statusErrors = append(config.statusErrors, statusError{codes: codes, err: err})
+	}
+}
+
+// WithNotFoundAsAbsent
+//
+// Treat a 404 Not Found as the absence of the resource rather than a failure, simplifying get-or-nil call
+// sites. A response object implementing response.Optional, such as one embedding response.Presence, is marked
+// absent and no error is returned; otherwise the error wraps ErrNotFound:
+//
+//	var user GetUserResponse
+//	err := gkBoot.DoRequestWithOptions(baseUrl, GetUserRequest{ID: 7}, &user, gkBoot.WithNotFoundAsAbsent())
+//	if errors.Is(err, gkBoot.ErrNotFound) {
+//	  return nil, nil
+//	}
+func WithNotFoundAsAbsent() ClientOption {
+	return func(config *ClientConfig) {
+		config.NotFoundAsAbsent = true
+	}
+}
+
+// WithContentChecksum
+//
+// Send the digest of the request body computed with the given algorithm, as required by integrity-verified
+// uploads of object storage APIs
+func WithContentChecksum(algorithm ChecksumAlgorithm) ClientOption {
+	return func(config *ClientConfig) {
+		config.ContentChecksum = algorithm
+	}
+}
+
+// WithDateHeader
+//
+// Send the current time in the Date header of the request, see WithClock
+func WithDateHeader() ClientOption {
+	return func(config *ClientConfig) {
+		config.DateHeader = true
+	}
+}
+
+// WithDeadlineHeader
+//
+// Send the time left until the deadline of the request context in the given header, Grpc-Timeout when empty,
+// as accepted by gRPC-gateway endpoints
+func WithDeadlineHeader(header string) ClientOption {
+	return func(config *ClientConfig) {
+		if header == "" {
+			header = GrpcTimeoutHeader
+		}
+		config.DeadlineHeader = header
+	}
+}
+
+// WithEscaper
+//
+// Escape the query parameters and path values of the request with the given function, in place of the
+// escaping of the standard library, which turns a space of a query value into '+':
+//
+//	gkBoot.WithEscaper(gkBoot.EscapeRFC3986)
+func WithEscaper(escape func(value string) string) ClientOption {
+	return func(config *ClientConfig) {
+		config.Escaper = escape
+	}
+}
+
+// WithClock
+//
+// Set the source of the current time of the client, in place of time.Now
+func WithClock(clock Clock) ClientOption {
+	return func(config *ClientConfig) {
+		config.Clock = clock
+	}
+}
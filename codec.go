@@ -0,0 +1,338 @@
+package gkBoot
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Decoder
+//
+// Decodes response bodies of the media type given by ContentType into response objects. Decoders are
+// registered with RegisterDecoder.
+type Decoder interface {
+	ContentType() string
+	Unmarshal(data []byte, v any) error
+}
+
+// Codec
+//
+// Both encodes request bodies into and decodes response bodies from the media type given by ContentType.
+// Codecs are registered with RegisterCodec.
+type Codec interface {
+	Decoder
+	Marshal(v any) ([]byte, error)
+}
+
+// BodyContentTyper
+//
+// When implemented by a request object, the body of the request is encoded by the codec registered for the
+// returned media type instead of as JSON. A media type with the +json suffix, such as
+// application/merge-patch+json, needs no codec of its own; it is encoded as JSON under the returned type.
+type BodyContentTyper interface {
+	BodyContentType() string
+}
+
+// BodyEncodingSelector
+//
+// When implemented by a request object, the body of the request is chosen once the request is generated.
+// SelectBodyEncoding receives the Accept header of the request and returns the Content-Type of the body
+// along with a function producing it. Returning an empty content type keeps the body generated from the
+// fields of the request.
+type BodyEncodingSelector interface {
+	SelectBodyEncoding(accept string) (contentType string, body func() ([]byte, error))
+}
+
+const jsonContentType = "application/json"
+
+const contentTypeHeader = "Content-Type"
+
+// TextDecoder
+//
+// Decodes text/plain bodies into a *string, a *[]byte or an encoding.TextUnmarshaler, for example to read the
+// plain-text error bodies of an otherwise JSON API through WithStatusDecoder.
+type TextDecoder struct{}
+
+func (t TextDecoder) ContentType() string {
+	return "text/plain"
+}
+
+func (t TextDecoder) Unmarshal(data []byte, v any) error {
+	switch target := v.(type) {
+	case encoding.TextUnmarshaler:
+		return target.UnmarshalText(data)
+	case *string:
+		*target = string(data)
+	case *[]byte:
+		*target = append((*target)[:0], data...)
+	default:
+		return fmt.Errorf("text decoder unable to decode into %T", v)
+	}
+
+	return nil
+}
+
+type jsonCodec struct{}
+
+func (j jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (j jsonCodec) ContentType() string {
+	return jsonContentType
+}
+
+func (j jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+var (
+	decoderLock sync.RWMutex
+	// decoders are held in order of preference, the default JSON decoder is always present first
+	decoders = []Decoder{jsonCodec{}}
+)
+
+// RegisterDecoder
+//
+// Registers the decoder for its media type. Decoders are preferred in the order they are registered, after
+// the default JSON decoder. Registering a decoder for a media type already present replaces that decoder
+// while keeping its position.
+func RegisterDecoder(decoder Decoder) {
+	decoderLock.Lock()
+	defer decoderLock.Unlock()
+
+	for i, existing := range decoders {
+		if strings.EqualFold(existing.ContentType(), decoder.ContentType()) {
+			decoders[i] = decoder
+			return
+		}
+	}
+
+	decoders = append(decoders, decoder)
+}
+
+// RegisterCodec
+//
+// Registers the codec for the given media type, making it available to encode the bodies of requests
+// declaring the type through BodyContentTyper and to decode responses of the type. The codec takes part in
+// the Accept header and is preferred in registration order, exactly as a decoder given to RegisterDecoder.
+func RegisterCodec(contentType string, codec Codec) {
+	if !strings.EqualFold(codec.ContentType(), contentType) {
+		codec = keyedCodec{Codec: codec, contentType: contentType}
+	}
+
+	RegisterDecoder(codec)
+}
+
+// keyedCodec
+//
+// registers a codec under a media type other than its own
+type keyedCodec struct {
+	Codec
+	contentType string
+}
+
+func (k keyedCodec) ContentType() string {
+	return k.contentType
+}
+
+// DeregisterDecoder
+//
+// Removes the decoder or codec registered for the given media type.
+func DeregisterDecoder(contentType string) {
+	decoderLock.Lock()
+	defer decoderLock.Unlock()
+
+	for i, existing := range decoders {
+		if strings.EqualFold(existing.ContentType(), contentType) {
+			decoders = append(decoders[:i:i], decoders[i+1:]...)
+			return
+		}
+	}
+}
+
+// decoderForContentType
+//
+// finds the registered decoder for the media type of the Content-Type header value, the JSON decoder
+// when the type is absent or has no registered decoder
+func decoderForContentType(contentType string) Decoder {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = jsonContentType
+	}
+
+	decoderLock.RLock()
+	defer decoderLock.RUnlock()
+
+	var jsonDecoder Decoder = jsonCodec{}
+
+	for _, decoder := range decoders {
+		if strings.EqualFold(decoder.ContentType(), mediaType) {
+			return decoder
+		}
+		if strings.EqualFold(decoder.ContentType(), jsonContentType) {
+			jsonDecoder = decoder
+		}
+	}
+
+	return jsonDecoder
+}
+
+// defaultCodec
+//
+// the codec registered for JSON, which encodes request bodies unless a request chooses another codec.
+// Registering a codec for application/json replaces the default JSON handling globally.
+func defaultCodec() Codec {
+	if codec, err := codecForContentType(jsonContentType); err == nil {
+		return codec
+	}
+
+	return jsonCodec{}
+}
+
+// configuredJSONCodec
+//
+// a JSON codec using the encoder and decoder factories and the body name mapper of a ClientConfig, falling
+// back to the wrapped codec for the factory that is not set
+type configuredJSONCodec struct {
+	Codec
+	encoder    func(w io.Writer) *json.Encoder
+	decoder    func(r io.Reader) *json.Decoder
+	nameMapper func(name string) string
+}
+
+// withJSONFactories
+//
+// wraps a JSON codec, of application/json or a +json type, with the factories and the body name mapper of
+// the config, returning any other codec untouched
+func withJSONFactories(codec Codec, cfg *ClientConfig) Codec {
+	if cfg.JSONEncoder == nil && cfg.JSONDecoder == nil && cfg.BodyNameMapper == nil {
+		return codec
+	}
+	if !isJSONContentType(codec.ContentType()) {
+		return codec
+	}
+
+	return configuredJSONCodec{
+		Codec: codec, encoder: cfg.JSONEncoder, decoder: cfg.JSONDecoder, nameMapper: cfg.BodyNameMapper,
+	}
+}
+
+func (c configuredJSONCodec) Marshal(v any) ([]byte, error) {
+	if c.nameMapper != nil {
+		v = mapBodyNames(v, c.nameMapper)
+	}
+
+	if c.encoder == nil {
+		return c.Codec.Marshal(v)
+	}
+
+	var buf bytes.Buffer
+	if err := c.encoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+
+	// json.Encoder terminates each value with a newline that json.Marshal does not produce
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+func (c configuredJSONCodec) Unmarshal(data []byte, v any) error {
+	if c.decoder == nil {
+		return c.Codec.Unmarshal(data, v)
+	}
+
+	err := c.decoder(bytes.NewReader(data)).Decode(v)
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
+
+// codecForContentType
+//
+// finds the registered codec for the media type, JSON being always available
+func codecForContentType(contentType string) (Codec, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, fmt.Errorf("invalid body content type '%s': %w", contentType, err)
+	}
+
+	decoderLock.RLock()
+	defer decoderLock.RUnlock()
+
+	for _, decoder := range decoders {
+		if codec, ok := decoder.(Codec); ok && strings.EqualFold(codec.ContentType(), mediaType) {
+			return codec, nil
+		}
+	}
+
+	// a structured syntax suffix, such as application/merge-patch+json, is marshaled as plain JSON
+	if isJSONContentType(mediaType) {
+		return keyedCodec{Codec: jsonCodec{}, contentType: mediaType}, nil
+	}
+
+	return nil, fmt.Errorf("no codec registered for body content type '%s'", mediaType)
+}
+
+// sniffContentType
+//
+// guesses the media type of a body sent without a Content-Type from its first character: JSON for an
+// object or array, the first registered XML type for markup. The result is empty when no guess is made.
+func sniffContentType(body []byte) string {
+	trimmed := bytes.TrimLeft(bytes.TrimPrefix(body, []byte("\xef\xbb\xbf")), " \t\r\n")
+	if len(trimmed) == 0 {
+		return ""
+	}
+
+	switch trimmed[0] {
+	case '{', '[':
+		return "application/json"
+	case '<':
+		decoderLock.RLock()
+		defer decoderLock.RUnlock()
+
+		for _, decoder := range decoders {
+			mediaType := strings.ToLower(decoder.ContentType())
+			if mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml") {
+				return mediaType
+			}
+		}
+		return "application/xml"
+	default:
+		return ""
+	}
+}
+
+// registeredAccept
+//
+// builds an Accept header value listing the media type of every registered decoder, weighted by
+// registration order so that the preferred type comes first
+func registeredAccept() string {
+	decoderLock.RLock()
+	defer decoderLock.RUnlock()
+
+	mediaTypes := make([]string, 0, len(decoders))
+	for i, decoder := range decoders {
+		if i == 0 {
+			mediaTypes = append(mediaTypes, decoder.ContentType())
+			continue
+		}
+
+		// q-values step down by a tenth, never reaching zero which would mean "not acceptable"
+		q := 10 - i
+		if q < 1 {
+			q = 1
+		}
+		mediaTypes = append(mediaTypes, decoder.ContentType()+";q=0."+strconv.Itoa(q))
+	}
+
+	return strings.Join(mediaTypes, ", ")
+}
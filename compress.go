@@ -0,0 +1,143 @@
+package gkBoot
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// compressionGzip is the only supported value of the 'compress' tag and of ClientConfig.RequestCompression
+const compressionGzip = "gzip"
+
+var gzipWriters = sync.Pool{
+	New: func() any {
+		return gzip.NewWriter(nil)
+	},
+}
+
+// compressBody
+//
+// compresses the marshaled body with the given encoding, using a pooled writer
+func compressBody(encoding string, body []byte) ([]byte, error) {
+	if encoding != compressionGzip {
+		return nil, fmt.Errorf("unsupported request body compression '%s'", encoding)
+	}
+
+	var buf bytes.Buffer
+
+	writer := gzipWriters.Get().(*gzip.Writer)
+	defer gzipWriters.Put(writer)
+
+	writer.Reset(&buf)
+
+	if _, err := writer.Write(body); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// decompressResponse
+//
+// replaces the body of a response with a Content-Encoding of gzip or deflate by a decompressing reader,
+// as the transport does for responses it requested compressed itself, for error statuses as for any other
+// so that error bodies are read decompressed. Responses the transport already decompressed and responses
+// with any other encoding are left untouched.
+func decompressResponse(resp *http.Response) {
+	if resp.Uncompressed || resp.Body == nil || resp.Body == http.NoBody {
+		return
+	}
+
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+
+	var open func(io.Reader) (io.ReadCloser, error)
+	switch encoding {
+	case "gzip", "x-gzip":
+		plainErrors := resp.StatusCode >= 400
+		open = func(reader io.Reader) (io.ReadCloser, error) {
+			return openGzip(reader, plainErrors)
+		}
+	case "deflate":
+		open = openDeflate
+	default:
+		return
+	}
+
+	resp.Body = &decompressingBody{body: resp.Body, open: open}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+}
+
+// gzipMagic begins every gzip stream
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// openGzip
+//
+// reads a gzip body. When plainErrors is set, a body not beginning with the gzip magic number is read as is,
+// as the error pages of proxies are often labeled gzip while sent as plain text
+func openGzip(reader io.Reader, plainErrors bool) (io.ReadCloser, error) {
+	buffered := bufio.NewReader(reader)
+
+	if plainErrors {
+		if magic, err := buffered.Peek(len(gzipMagic)); err != nil || !bytes.Equal(magic, gzipMagic) {
+			return io.NopCloser(buffered), nil
+		}
+	}
+
+	return gzip.NewReader(buffered)
+}
+
+// openDeflate
+//
+// reads a deflate body, which is meant to be zlib wrapped but is sent as raw deflate by some servers
+func openDeflate(reader io.Reader) (io.ReadCloser, error) {
+	buffered := bufio.NewReader(reader)
+
+	header, err := buffered.Peek(2)
+	if err == nil && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(buffered)
+	}
+
+	return flate.NewReader(buffered), nil
+}
+
+// decompressingBody
+//
+// opens the decompressor on the first read, so that an empty body never fails to decompress unless read
+type decompressingBody struct {
+	body   io.ReadCloser
+	open   func(io.Reader) (io.ReadCloser, error)
+	reader io.ReadCloser
+	err    error
+}
+
+func (d *decompressingBody) Read(b []byte) (int, error) {
+	if d.reader == nil && d.err == nil {
+		d.reader, d.err = d.open(d.body)
+	}
+	if d.err != nil {
+		return 0, d.err
+	}
+
+	return d.reader.Read(b)
+}
+
+func (d *decompressingBody) Close() error {
+	if d.reader != nil {
+		_ = d.reader.Close()
+	}
+
+	return d.body.Close()
+}
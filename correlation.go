@@ -0,0 +1,68 @@
+package gkBoot
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+
+	"github.com/yomiji/gkBoot/response"
+)
+
+// DefaultCorrelationHeader is the header carrying the correlation ID when no other header is configured
+const DefaultCorrelationHeader = "X-Correlation-ID"
+
+// newCorrelationID
+//
+// generates a random (version 4) UUID
+func newCorrelationID() string {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+
+	id[6] = (id[6] & 0x0f) | 0x40
+	id[8] = (id[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:])
+}
+
+// correlationHeader
+//
+// the header carrying the correlation ID for the given config
+func correlationHeader(cfg *ClientConfig) string {
+	if cfg.CorrelationHeader != "" {
+		return cfg.CorrelationHeader
+	}
+
+	return DefaultCorrelationHeader
+}
+
+// applyCorrelationID
+//
+// sets the correlation ID header on the request, generating an ID when none was given. A header already
+// assigned by the request object is kept.
+func applyCorrelationID(r *http.Request, cfg *ClientConfig) {
+	header := correlationHeader(cfg)
+	if r.Header.Get(header) != "" {
+		return
+	}
+
+	id := cfg.CorrelationID
+	if id == "" {
+		id = newCorrelationID()
+	}
+
+	r.Header.Set(header, id)
+}
+
+// receiveCorrelationID
+//
+// gives the correlation ID echoed by the server to a response object implementing
+// response.CorrelationReceiver
+func receiveCorrelationID(resp *http.Response, responseObj any, cfg *ClientConfig) {
+	if !cfg.Correlate {
+		return
+	}
+
+	if receiver, ok := responseObj.(response.CorrelationReceiver); ok {
+		receiver.NewCorrelationID(resp.Header.Get(correlationHeader(cfg)))
+	}
+}
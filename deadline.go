@@ -0,0 +1,77 @@
+package gkBoot
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// GrpcTimeoutHeader is the header carrying the deadline of a gRPC call, sent by WithDeadlineHeader when no
+// other header is given
+const GrpcTimeoutHeader = "Grpc-Timeout"
+
+// grpcTimeoutDigits is the greatest number of digits of a gRPC timeout value
+const grpcTimeoutDigits = 8
+
+// grpcTimeoutUnits lists the units of a gRPC timeout from the finest to the coarsest
+var grpcTimeoutUnits = []struct {
+	unit   time.Duration
+	suffix string
+}{
+	{time.Nanosecond, "n"},
+	{time.Microsecond, "u"},
+	{time.Millisecond, "m"},
+	{time.Second, "S"},
+	{time.Minute, "M"},
+	{time.Hour, "H"},
+}
+
+// FormatGrpcTimeout
+//
+// Formats the duration as a gRPC timeout, such as 5S or 100m, using the coarsest unit that holds it exactly
+// in the eight digits allowed. Otherwise the finest unit that fits is used, rounding up so the deadline is
+// never shortened. A negative duration is formatted as 0H.
+func FormatGrpcTimeout(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+
+	limit := int64(1)
+	for i := 0; i < grpcTimeoutDigits; i++ {
+		limit *= 10
+	}
+
+	for i := len(grpcTimeoutUnits) - 1; i >= 0; i-- {
+		unit := grpcTimeoutUnits[i]
+		if d%unit.unit == 0 && int64(d/unit.unit) < limit {
+			return strconv.FormatInt(int64(d/unit.unit), 10) + unit.suffix
+		}
+	}
+
+	for _, unit := range grpcTimeoutUnits {
+		value := (int64(d) + int64(unit.unit) - 1) / int64(unit.unit)
+		if value < limit {
+			return strconv.FormatInt(value, 10) + unit.suffix
+		}
+	}
+
+	// unreachable, as the longest duration is below 10^8 hours
+	return strconv.FormatInt(limit-1, 10) + "H"
+}
+
+// applyDeadlineHeader
+//
+// sets the DeadlineHeader of the config to the time left until the deadline of the request context, or until
+// the Timeout of the config when it expires first. Nothing is set without a deadline or a timeout.
+func applyDeadlineHeader(r *http.Request, cfg *ClientConfig) {
+	remaining := cfg.Timeout
+	if deadline, ok := r.Context().Deadline(); ok {
+		if left := deadline.Sub(cfg.Clock()); remaining <= 0 || left < remaining {
+			remaining = left
+		}
+	} else if remaining <= 0 {
+		return
+	}
+
+	r.Header.Set(cfg.DeadlineHeader, FormatGrpcTimeout(remaining))
+}
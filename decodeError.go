@@ -0,0 +1,55 @@
+package gkBoot
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// decodeErrorSnippet is the number of body bytes quoted by the message of a DecodeError
+const decodeErrorSnippet = 256
+
+// DecodeError
+//
+// Returned when the body of a response could not be decoded into the response object. The status, headers
+// and complete body of the response are kept, so that a change of the upstream format can be diagnosed with
+// errors.As, while the message quotes the start of the body. Unwrap exposes the error of the decoder.
+type DecodeError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	Err        error
+}
+
+func (d *DecodeError) Error() string {
+	snippet := d.Body
+	truncated := ""
+	if len(snippet) > decodeErrorSnippet {
+		snippet = snippet[:decodeErrorSnippet]
+		truncated = "..."
+	}
+
+	return fmt.Sprintf(
+		"unable to decode response body for %s %s due to %s, status %d, body: %q%s", d.Method, d.URL, d.Err,
+		d.StatusCode, snippet, truncated,
+	)
+}
+
+func (d *DecodeError) Unwrap() error {
+	return d.Err
+}
+
+// newDecodeError
+//
+// records the response that failed to decode, copying the body as it may belong to the buffer pool
+func newDecodeError(r *http.Request, resp *http.Response, body []byte, err error) *DecodeError {
+	return &DecodeError{
+		Method:     r.Method,
+		URL:        r.URL.String(),
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		Body:       append([]byte(nil), body...),
+		Err:        err,
+	}
+}
@@ -37,6 +37,9 @@ import (
 //		  Body    CustomBodyStruct `request:"form"`                       // json request body as an object (json.Unmarshal)
 //		}
 //
+// A field tagged with requiredFor:"POST,PUT" is required only for requests of a listed method, as if its
+// 'request' tag carried a trailing '!'.
+//
 // Note that this function will look for the corresponding field values using the following naming hierarchy:
 //
 //	alias -> json -> field name (exported)
@@ -99,7 +102,9 @@ type jsonBody interface {
 
 // JSONBody
 //
-// When embedded into a request, flags the request as a JSON body to allow for automatic decoding.
+// When embedded into a request, flags the request as a JSON body to allow for automatic decoding. Client
+// requests embedding JSONBody are marshaled in full as the request body, unless the request implements
+// request.BodyProvider.
 type JSONBody struct{}
 
 func (J JSONBody) isJsonBody() {}
@@ -122,7 +127,7 @@ func decodeStructBody(ctx context.Context, r *http.Request, workingValuePtr refl
 	}
 	body := reflect.New(baseVal.Type()).Interface()
 	// set req body size limiter if sent to us
-	limit := helpers.GetRequestBodyLimit(ctx)
+	limit := helpers.GetRequestBodyLimit64(ctx)
 	if limit != nil {
 		err := readFormBody(r, body, *limit)
 		if err != nil {
@@ -191,14 +196,14 @@ func assignValues(ctx context.Context, r *http.Request, workingValuePtr reflect.
 		if requestTag == "" && (fieldDesc.Type.Kind() == reflect.Struct || (fieldDesc.Anonymous && fieldVal.CanSet())) {
 			// recurse if embedded structure
 			return assignValues(ctx, r, fieldVal)
-		} else if requestTag == "form" {
+		} else if requestTag == string(RequestPartForm) {
 			// begin to set form values using the interface type via json
 			if !fieldVal.CanSet() {
 				return fmt.Errorf("field '%s' must be exported if using 'request'", fieldDesc.Name)
 			}
 			body := reflect.New(fieldVal.Type()).Interface()
 			// set req body size limiter if sent to us
-			limit := helpers.GetRequestBodyLimit(ctx)
+			limit := helpers.GetRequestBodyLimit64(ctx)
 			if limit != nil {
 				err = readFormBody(r, body, *limit)
 				if err != nil {
@@ -228,7 +233,8 @@ func assignValues(ctx context.Context, r *http.Request, workingValuePtr reflect.
 			if alias != "" {
 				fieldName = alias
 			}
-			val, err := operation(r, fieldName, destType, strings.HasSuffix(requestTag, "!"))
+			required := strings.HasSuffix(requestTag, "!") || requiredForMethod(fieldDesc, r.Method)
+			val, err := operation(r, fieldName, destType, required)
 			if err != nil {
 				return err
 			}
@@ -248,7 +254,8 @@ func readTag(field reflect.StructField) (requestPart, alias, jsonAlias string) {
 		return
 	}
 	if tag, ok = field.Tag.Lookup("request"); ok {
-		requestPart = tag
+		// modifiers following the part, such as omitempty, only affect the client
+		requestPart, _, _ = strings.Cut(tag, ",")
 	}
 	if tag, ok = field.Tag.Lookup("alias"); ok {
 		alias = tag
@@ -267,7 +274,10 @@ func readTag(field reflect.StructField) (requestPart, alias, jsonAlias string) {
 }
 
 func fromSwaggestTag(field reflect.StructField) (requestPart, alias, jsonAlias string, ok bool) {
-	swaggestTags := []string{"path", "query", "formData", "cookie", "header"}
+	swaggestTags := []string{
+		string(RequestPartPath), string(RequestPartQuery), "formData", string(RequestPartCookie),
+		string(RequestPartHeader),
+	}
 	var required bool
 	if r, k := field.Tag.Lookup("required"); k {
 		if r != "" {
@@ -282,7 +292,7 @@ func fromSwaggestTag(field reflect.StructField) (requestPart, alias, jsonAlias s
 		if tag, ok = field.Tag.Lookup(structTag); ok {
 			switch structTag {
 			case "formData":
-				requestPart = "form"
+				requestPart = string(RequestPartForm)
 			default:
 				if required {
 					requestPart = structTag + "!"
@@ -301,14 +311,16 @@ func fromSwaggestTag(field reflect.StructField) (requestPart, alias, jsonAlias s
 }
 
 func returnOperationByTagValue(tagName string) typicalRequestType {
-	switch tagName {
-	case "cookie", "cookie!":
+	requestPart, _, _ := ParseRequestPart(tagName)
+
+	switch requestPart {
+	case RequestPartCookie:
 		return readRequestCookie
-	case "header", "header!":
+	case RequestPartHeader:
 		return readRequestHeader
-	case "query", "query!":
+	case RequestPartQuery:
 		return readRequestQuery
-	case "path", "path!":
+	case RequestPartPath:
 		return readPathParam
 	default:
 		return nil
@@ -384,9 +396,9 @@ func readPathParam(r *http.Request, fieldName string, destType reflect.Type, isR
 	return convertStringToValue(pathStringValue, destType, false)
 }
 
-func readFormBody(r *http.Request, body interface{}, limit int) error {
+func readFormBody(r *http.Request, body interface{}, limit int64) error {
 	if limit > 0 {
-		reader := io.LimitReader(r.Body, int64(limit))
+		reader := io.LimitReader(r.Body, limit)
 		bytes, err := io.ReadAll(bufio.NewReader(reader))
 		if err != nil {
 			return err
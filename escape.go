@@ -0,0 +1,82 @@
+package gkBoot
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// EscapeRFC3986
+//
+// Escapes every byte of the value other than the unreserved characters of RFC 3986 (letters, digits and
+// '-', '.', '_', '~'), so a space becomes %20 rather than the '+' of url.QueryEscape. Suits APIs comparing
+// escaped values exactly, such as those signing their query strings, see WithEscaper.
+func EscapeRFC3986(value string) string {
+	const hex = "0123456789ABCDEF"
+
+	var builder strings.Builder
+	builder.Grow(len(value))
+
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+				c == '-' || c == '.' || c == '_' || c == '~' {
+			builder.WriteByte(c)
+			continue
+		}
+
+		builder.WriteByte('%')
+		builder.WriteByte(hex[c>>4])
+		builder.WriteByte(hex[c&0x0f])
+	}
+
+	return builder.String()
+}
+
+// encodeQuery
+//
+// encodes the values as url.Values.Encode does, sorted by name, escaping names and values with escape
+func encodeQuery(values url.Values, escape func(string) string) string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var builder strings.Builder
+	for _, name := range names {
+		escapedName := escape(name)
+		for _, value := range values[name] {
+			if builder.Len() > 0 {
+				builder.WriteByte('&')
+			}
+			builder.WriteString(escapedName)
+			builder.WriteByte('=')
+			builder.WriteString(escape(value))
+		}
+	}
+
+	return builder.String()
+}
+
+// queryEscape
+//
+// escapes a value written with the urlEncode tag, by the Escaper of the config when one is set
+func (o clientFieldOptions) queryEscape(value string) string {
+	if o.escape != nil {
+		return o.escape(value)
+	}
+
+	return url.QueryEscape(value)
+}
+
+// pathEscape
+//
+// escapes a path value, by the Escaper of the config when one is set
+func (o clientFieldOptions) pathEscape(value string) string {
+	if o.escape != nil {
+		return o.escape(value)
+	}
+
+	return url.PathEscape(value)
+}
@@ -0,0 +1,85 @@
+package gkBoot
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// FieldError
+//
+// A validation failure of a single field reported by the server, see WithFieldErrorsDecoder
+type FieldError struct {
+	// Field names the offending field, in whatever notation the server uses (for example "address.zip")
+	Field string
+	// Message describes the failure
+	Message string
+	// Code is the machine readable reason for the failure, when the server reports one
+	Code string
+}
+
+func (f FieldError) Error() string {
+	if f.Field == "" {
+		return f.Message
+	}
+
+	return f.Field + ": " + f.Message
+}
+
+// ValidationError
+//
+// Returned for a 422 Unprocessable Entity response when a field errors decoder is configured through
+// WithFieldErrorsDecoder. Unwrap exposes every FieldError, so a single field may be matched with errors.As or
+// errors.Is, while Fields holds the list in the order reported by the server.
+type ValidationError struct {
+	StatusCode int
+	Fields     []FieldError
+}
+
+func (v *ValidationError) Error() string {
+	messages := make([]string, 0, len(v.Fields))
+	for _, field := range v.Fields {
+		messages = append(messages, field.Error())
+	}
+
+	return strconv.Itoa(v.StatusCode) + " " + http.StatusText(v.StatusCode) + ": " + strings.Join(messages, "; ")
+}
+
+func (v *ValidationError) Unwrap() []error {
+	errs := make([]error, 0, len(v.Fields))
+	for _, field := range v.Fields {
+		errs = append(errs, field)
+	}
+
+	return errs
+}
+
+// decodeErrorResponse
+//
+// decodes the body of a response with a non-2xx status into the error configured for it, a ValidationError
+// for a 422 when a field errors decoder is set, then a StatusError when the status is mapped to an error,
+// wrapping the result of the ErrorDecoder, otherwise the result of the ErrorDecoder. The boolean result is
+// false when none applies and the response is handled as it would be without them.
+func decodeErrorResponse(resp *http.Response, status int, body []byte, cfg *ClientConfig) (error, bool) {
+	if status == http.StatusUnprocessableEntity && cfg.FieldErrorsDecoder != nil {
+		if fields, err := cfg.FieldErrorsDecoder(body); err == nil {
+			return &ValidationError{StatusCode: status, Fields: fields}, true
+		}
+	}
+
+	if statusErr, ok := mapStatusError(resp, status, body, cfg); ok {
+		if cfg.ErrorDecoder != nil {
+			if cause, ok := cfg.ErrorDecoder(resp, body); ok {
+				statusErr.Cause = cause
+			}
+		}
+
+		return statusErr, true
+	}
+
+	if cfg.ErrorDecoder != nil {
+		return cfg.ErrorDecoder(resp, body)
+	}
+
+	return nil, false
+}
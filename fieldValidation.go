@@ -0,0 +1,256 @@
+package gkBoot
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ErrInvalidPattern is wrapped by the error reporting a pattern tag that is not a valid regular expression,
+// telling a mistake of the request struct from a value that does not match
+var ErrInvalidPattern = errors.New("invalid pattern tag")
+
+// patterns caches the compiled regular expression of each pattern tag
+var patterns sync.Map
+
+// validateTaggedFields
+//
+// checks every field of the request object against its validation tags, descending into embedded and untagged
+// struct fields:
+//
+//   - enum, a comma separated list of the values allowed for a string or integer
+//   - min and max (or the swaggest minimum and maximum), the bounds of a number
+//   - minLength and maxLength, the bounds of the number of characters of a string
+//   - pattern, a regular expression a string must match
+//
+// Each element of a slice or array is checked in turn. A field holding its zero value, such as an empty string
+// or a nil pointer, is left to the required checks. Every violation is reported in a single error.
+func validateTaggedFields(value reflect.Value) error {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var errs []error
+
+	for i := 0; i < value.NumField(); i++ {
+		fieldDesc := value.Type().Field(i)
+		if !fieldDesc.IsExported() && !fieldDesc.Anonymous {
+			continue
+		}
+
+		constraints := readFieldConstraints(fieldDesc)
+		if constraints == nil {
+			if _, tagged := fieldDesc.Tag.Lookup("request"); !tagged && indirectKind(fieldDesc.Type) == reflect.Struct {
+				errs = append(errs, validateTaggedFields(value.Field(i)))
+			}
+			continue
+		}
+
+		if value.Field(i).IsZero() {
+			continue
+		}
+
+		for _, element := range constrainedValues(value.Field(i)) {
+			for _, constraint := range constraints {
+				errs = append(errs, constraint(fieldDesc.Name, element))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// fieldConstraint
+//
+// checks a single string or number value of the named field, returning the violation
+type fieldConstraint func(fieldName string, value reflect.Value) error
+
+// readFieldConstraints
+//
+// the constraints declared by the validation tags of the field, nil when it has none
+func readFieldConstraints(field reflect.StructField) []fieldConstraint {
+	var constraints []fieldConstraint
+
+	if tag, ok := field.Tag.Lookup("enum"); ok {
+		constraints = append(constraints, enumConstraint(tag))
+	}
+
+	for _, bound := range []struct {
+		tags    []string
+		name    string
+		minimum bool
+		length  bool
+	}{
+		{tags: []string{"min", "minimum"}, name: "minimum", minimum: true},
+		{tags: []string{"max", "maximum"}, name: "maximum"},
+		{tags: []string{"minLength"}, name: "minLength", minimum: true, length: true},
+		{tags: []string{"maxLength"}, name: "maxLength", length: true},
+	} {
+		for _, tagName := range bound.tags {
+			if tag, ok := field.Tag.Lookup(tagName); ok {
+				constraints = append(
+					constraints, boundConstraint(tagName, tag, bound.name, bound.minimum, bound.length),
+				)
+				break
+			}
+		}
+	}
+
+	if tag, ok := field.Tag.Lookup("pattern"); ok {
+		constraints = append(constraints, patternConstraint(tag))
+	}
+
+	return constraints
+}
+
+// enumConstraint
+//
+// allows the values listed by an 'enum' tag
+func enumConstraint(tag string) fieldConstraint {
+	allowed := strings.Split(tag, ",")
+	for i := range allowed {
+		allowed[i] = strings.TrimSpace(allowed[i])
+	}
+
+	return func(fieldName string, value reflect.Value) error {
+		var formatted string
+
+		switch value.Kind() {
+		case reflect.String:
+			formatted = value.String()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			formatted = strconv.FormatInt(value.Int(), 10)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			formatted = strconv.FormatUint(value.Uint(), 10)
+		default:
+			return nil
+		}
+
+		if !slices.Contains(allowed, formatted) {
+			return fmt.Errorf("'%s' value %q is not one of the allowed values [%s]", fieldName, formatted, tag)
+		}
+
+		return nil
+	}
+}
+
+// boundConstraint
+//
+// bounds a number, or the length of a string when length is set, from below when minimum is set and from
+// above otherwise
+func boundConstraint(tagName, tag, name string, minimum, length bool) fieldConstraint {
+	limit, err := strconv.ParseFloat(strings.TrimSpace(tag), 64)
+
+	return func(fieldName string, value reflect.Value) error {
+		if err != nil {
+			return fmt.Errorf("'%s' has an invalid %s tag %q: %w", fieldName, tagName, tag, err)
+		}
+
+		var measured float64
+		var measure string
+
+		switch kind := value.Kind(); {
+		case length && kind == reflect.String:
+			measured = float64(utf8.RuneCountInString(value.String()))
+			measure = "length"
+		case length:
+			return nil
+		case kind >= reflect.Int && kind <= reflect.Int64:
+			measured = float64(value.Int())
+			measure = "value"
+		case kind >= reflect.Uint && kind <= reflect.Uintptr:
+			measured = float64(value.Uint())
+			measure = "value"
+		case kind == reflect.Float32 || kind == reflect.Float64:
+			measured = value.Float()
+			measure = "value"
+		default:
+			return nil
+		}
+
+		if minimum && measured < limit {
+			return fmt.Errorf("'%s' %s %v is below the %s of %s", fieldName, measure, measured, name, tag)
+		}
+		if !minimum && measured > limit {
+			return fmt.Errorf("'%s' %s %v exceeds the %s of %s", fieldName, measure, measured, name, tag)
+		}
+
+		return nil
+	}
+}
+
+// patternConstraint
+//
+// requires a string to match the regular expression of a 'pattern' tag, compiled once for every request
+func patternConstraint(tag string) fieldConstraint {
+	return func(fieldName string, value reflect.Value) error {
+		if value.Kind() != reflect.String {
+			return nil
+		}
+
+		pattern, err := compilePattern(tag)
+		if err != nil {
+			return fmt.Errorf("'%s' has an %w %q: %w", fieldName, ErrInvalidPattern, tag, err)
+		}
+
+		if !pattern.MatchString(value.String()) {
+			return fmt.Errorf("'%s' value %q does not match the pattern %s", fieldName, value.String(), tag)
+		}
+
+		return nil
+	}
+}
+
+// compilePattern
+//
+// returns the cached regular expression of the pattern, compiling it on first use
+func compilePattern(tag string) (*regexp.Regexp, error) {
+	if cached, ok := patterns.Load(tag); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+
+	pattern, err := regexp.Compile(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, _ := patterns.LoadOrStore(tag, pattern)
+
+	return stored.(*regexp.Regexp), nil
+}
+
+// constrainedValues
+//
+// the string or number value, or each element of a slice or array of them, checked by the constraints
+func constrainedValues(value reflect.Value) []reflect.Value {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return []reflect.Value{value}
+	}
+
+	var values []reflect.Value
+	for i := 0; i < value.Len(); i++ {
+		values = append(values, constrainedValues(value.Index(i))...)
+	}
+
+	return values
+}
@@ -3,6 +3,7 @@ package helpers
 import (
 	"context"
 	"fmt"
+	"math"
 	"reflect"
 
 	"github.com/yomiji/gkBoot/request"
@@ -10,7 +11,7 @@ import (
 )
 
 type contextRequestBodyLimitKey int
-type contextRequestBodyLimitValue int
+type contextRequestBodyLimitValue int64
 
 type contextHeadersKey int
 type contextHeadersValue map[string]interface{}
@@ -47,11 +48,30 @@ func GetFriendlyRequestName(req interface{}) string {
 // GetRequestBodyLimit
 //
 // Gets the request body size limit that was assigned. If this value is not present, the result
-// will be nil.
+// will be nil. On platforms where int is 32 bits, a limit beyond the range of int is reported as
+// the largest int; use GetRequestBodyLimit64 to retrieve it exactly.
 func GetRequestBodyLimit(ctx context.Context) *int {
+	limit := GetRequestBodyLimit64(ctx)
+	if limit == nil {
+		return nil
+	}
+
+	limitVal := int(*limit)
+	if int64(limitVal) != *limit {
+		limitVal = math.MaxInt
+	}
+
+	return &limitVal
+}
+
+// GetRequestBodyLimit64
+//
+// Gets the request body size limit that was assigned as an int64, so that limits beyond 2GB are
+// preserved on 32-bit platforms. If this value is not present, the result will be nil.
+func GetRequestBodyLimit64(ctx context.Context) *int64 {
 	limitObj := ctx.Value(requestBodyLimitKey)
 	if limit, ok := limitObj.(contextRequestBodyLimitValue); ok {
-		limitVal := int(limit)
+		limitVal := int64(limit)
 		return &limitVal
 	}
 
@@ -59,6 +79,14 @@ func GetRequestBodyLimit(ctx context.Context) *int {
 }
 
 func SetRequestBodyLimit(ctx *context.Context, limit int) {
+	SetRequestBodyLimit64(ctx, int64(limit))
+}
+
+// SetRequestBodyLimit64
+//
+// Sets the request body size limit as an int64, so that limits beyond 2GB may be expressed on
+// 32-bit platforms.
+func SetRequestBodyLimit64(ctx *context.Context, limit int64) {
 	if ctx == nil {
 		return
 	}
@@ -109,3 +137,14 @@ func GetAdditionalLogs(resp interface{}) map[string]interface{} {
 	}
 	return nil
 }
+
+// GetAdditionalLogsOrdered
+//
+// If a response implements response.OrderedExtendedLog, then this function will retrieve the
+// attached logs in the order they were logged and return.
+func GetAdditionalLogsOrdered(resp interface{}) []response.KV {
+	if v, ok := resp.(response.OrderedExtendedLog); ok {
+		return v.GetOrdered()
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package gkBoot
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/yomiji/gkBoot/request"
+	"github.com/yomiji/gkBoot/response"
+)
+
+// recordSeparator begins every JSON text of an RFC 7464 sequence
+const recordSeparator = 0x1E
+
+const jsonSeqContentType = "application/json-seq"
+
+// JSONSeqRecord
+//
+// A single record of a JSON text sequence received by DoRequestJSONSeq. Err is set, and Value left zero, for a
+// record that failed to decode or when reading the stream failed.
+type JSONSeqRecord[T any] struct {
+	Value T
+	Err   error
+}
+
+// DoRequestJSONSeq
+//
+// Generates the client request from the given request object and sends it as DoRequestWithContext does,
+// reading the response as an RFC 7464 JSON text sequence (application/json-seq), where every JSON text is
+// preceded by the ASCII record separator. Each text is decoded into a new T and delivered on the returned
+// channel as soon as it has been read, which suits log and metrics endpoints that stream without end:
+//
+//	records, err := gkBoot.DoRequestJSONSeq[TailRequest, LogLine](ctx, baseUrl, TailRequest{})
+//	if err != nil {
+//	  // the request failed or the status was not 2xx
+//	}
+//	for record := range records {
+//	  // record.Value or record.Err
+//	}
+//
+// A text that fails to decode is delivered as a record carrying the error and the stream continues with the
+// next text. The channel is closed once the stream ends, reading it fails or the context is done; the
+// response body is closed at that point. Callers stopping early must cancel the context so the stream is
+// released. A non-2xx status fails the request before any record is read, with the typed error configured by
+// WithErrorType, WithStatusError or WithFieldErrorsDecoder when one applies.
+func DoRequestJSONSeq[RequestType request.HttpRequest, T any](
+		ctx context.Context, baseUrl string, clientRequest RequestType, opts ...ClientOption,
+) (<-chan JSONSeqRecord[T], error) {
+	cfg := newClientConfig(opts...)
+
+	r, err := generateClientRequest(ctx, baseUrl, clientRequest, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Header.Set("Accept", jsonSeqContentType)
+
+	err = applyRequestOptions(r, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := sendClientRequest(r, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+
+		body, readErr := io.ReadAll(resp.Body)
+		if readErr != nil {
+			return nil, fmt.Errorf("unable to parse response body for %s %s due to %s", r.Method, r.URL, readErr)
+		}
+
+		if typedErr, ok := decodeErrorResponse(resp, resp.StatusCode, body, cfg); ok {
+			return nil, typedErr
+		}
+
+		errorObj := struct {
+			response.ErrorResponse
+		}{}
+		errorObj.NewError(resp.StatusCode, "%s: %s", http.StatusText(resp.StatusCode), body)
+
+		return nil, errorObj
+	}
+
+	records := make(chan JSONSeqRecord[T])
+
+	go readJSONSeq(ctx, r, resp.Body, withJSONFactories(defaultCodec(), cfg), records)
+
+	return records, nil
+}
+
+// readJSONSeq
+//
+// splits the body on the record separator, decoding every non-empty text into a record, until the body ends
+// or the context is done
+func readJSONSeq[T any](
+		ctx context.Context, r *http.Request, body io.ReadCloser, codec Codec, records chan<- JSONSeqRecord[T],
+) {
+	defer close(records)
+	defer body.Close()
+
+	send := func(record JSONSeqRecord[T]) bool {
+		select {
+		case records <- record:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	reader := bufio.NewReader(body)
+
+	for {
+		text, err := reader.ReadBytes(recordSeparator)
+		text = bytes.TrimSpace(bytes.TrimSuffix(text, []byte{recordSeparator}))
+
+		if len(text) > 0 {
+			var record JSONSeqRecord[T]
+			if decodeErr := codec.Unmarshal(text, &record.Value); decodeErr != nil {
+				record = JSONSeqRecord[T]{}
+				record.Err = fmt.Errorf(
+					"unable to decode json sequence record for %s %s due to %w", r.Method, r.URL, decodeErr,
+				)
+			}
+
+			if !send(record) {
+				return
+			}
+		}
+
+		if err != nil {
+			if !errors.Is(err, io.EOF) && ctx.Err() == nil {
+				send(
+					JSONSeqRecord[T]{
+						Err: fmt.Errorf("unable to read json sequence for %s %s due to %w", r.Method, r.URL, err),
+					},
+				)
+			}
+			return
+		}
+	}
+}
@@ -71,7 +71,13 @@ func (l loggingWrappedService) Execute(ctx context.Context, req interface{}) (in
 		}
 
 		ctxHeaders := helpers.GetCtxHeadersFromContext(ctx)
-		additionalLogs := helpers.GetAdditionalLogs(derefResponse)
+		// responses keeping their logs in order are logged in that order, others fall back to the map
+		var additionalLogs interface{}
+		if ordered := helpers.GetAdditionalLogsOrdered(derefResponse); ordered != nil {
+			additionalLogs = ordered
+		} else if all := helpers.GetAdditionalLogs(derefResponse); all != nil {
+			additionalLogs = all
+		}
 		var httpRequestLog []interface{}
 		if httpRequest, ok := req.(request.HttpRequest); req != nil && ok {
 			httpRequestLog = []interface{}{
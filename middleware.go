@@ -0,0 +1,70 @@
+package gkBoot
+
+import (
+	"net/http"
+
+	"github.com/yomiji/gkBoot/request"
+)
+
+// RoundTripFunc
+//
+// Sends a single request and returns its response, the signature of http.Client.Do
+type RoundTripFunc func(r *http.Request) (*http.Response, error)
+
+// Middleware
+//
+// Wraps the sending of a request, receiving the final *http.Request once every field and option has been
+// applied. A middleware may alter the request before calling next, inspect or replace the response after it,
+// or return without calling next to abort the request. Used for cross-cutting concerns such as tracing,
+// logging and request signing.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// BeforeSend
+//
+// Adapts a function altering the request into a Middleware. A non-nil error aborts the request before it
+// is sent and is returned to the caller.
+func BeforeSend(intercept func(r *http.Request) error) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(r *http.Request) (*http.Response, error) {
+			if err := intercept(r); err != nil {
+				return nil, err
+			}
+
+			return next(r)
+		}
+	}
+}
+
+// DoRequestWithMiddleware
+//
+// Generates the client request from the given request object and sends it through the middleware, applying
+// each ClientOption as DoRequestWithOptions does. The first middleware is the outermost, so it sees the
+// request first and the response last:
+//
+//	sign := gkBoot.BeforeSend(func(r *http.Request) error {
+//	  r.Header.Set("X-Signature", hmacOf(r))
+//	  return nil
+//	})
+//	err := gkBoot.DoRequestWithMiddleware(baseUrl, req, &resp, []gkBoot.Middleware{trace, sign})
+func DoRequestWithMiddleware[RequestType request.HttpRequest, ResponseType any](
+		baseUrl string,
+		clientRequest RequestType,
+		responseObj *ResponseType,
+		middleware []Middleware,
+		opts ...ClientOption,
+) error {
+	return DoRequestWithOptions[RequestType, ResponseType](
+		baseUrl, clientRequest, responseObj, append(opts, WithMiddleware(middleware...))...,
+	)
+}
+
+// chainMiddleware
+//
+// wraps the round trip in the middleware, the first being the outermost
+func chainMiddleware(roundTrip RoundTripFunc, middleware []Middleware) RoundTripFunc {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		roundTrip = middleware[i](roundTrip)
+	}
+
+	return roundTrip
+}
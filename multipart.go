@@ -0,0 +1,456 @@
+package gkBoot
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// FileUpload
+//
+// When used as the type of a field tagged 'multipart', the field is sent as a file part of a
+// multipart/form-data body. Fields of type io.Reader and *os.File are sent as file parts as well.
+//
+//	type UploadRequest struct {
+//	  Description string            `request:"multipart"`
+//	  Avatar      gkBoot.FileUpload `request:"multipart!" alias:"avatar"`
+//	}
+type FileUpload struct {
+	// Filename
+	//
+	// The file name given in the Content-Disposition of the part. Defaults to the field name.
+	Filename string
+	// ContentType
+	//
+	// The Content-Type of the part. Defaults to the 'contentType' tag of the field, then to the type
+	// registered for the extension of Filename, then to application/octet-stream.
+	ContentType string
+	// Reader
+	//
+	// The contents of the file, streamed into the request body.
+	Reader io.Reader
+}
+
+// multipartPart
+//
+// a single part of a multipart/form-data body, either a plain field value or a file. A value marshaled
+// from a field tagged 'form' carries the Content-Type of its codec.
+type multipartPart struct {
+	fieldName   string
+	value       string
+	contentType string
+	file        *FileUpload
+}
+
+var fileUploadType = reflect.TypeOf(FileUpload{})
+var readerType = reflect.TypeOf((*io.Reader)(nil)).Elem()
+
+// readMultipartPart
+//
+// converts the field into a part, the boolean result is false when the field holds no value
+func readMultipartPart(
+		fieldName string, fieldValue reflect.Value, urlEncode bool, fieldOpts clientFieldOptions,
+) (multipartPart, bool) {
+	part := multipartPart{fieldName: fieldName}
+
+	// traverse pointers to a file upload, leave other pointers for type checks below
+	for fieldValue.Kind() == reflect.Ptr && fieldValue.Type().Elem() == fileUploadType {
+		if fieldValue.IsNil() {
+			return part, false
+		}
+		fieldValue = fieldValue.Elem()
+	}
+
+	if fieldValue.Type() == fileUploadType {
+		upload := fieldValue.Interface().(FileUpload)
+		if upload.Reader == nil {
+			return part, false
+		}
+		part.file = &upload
+		part.file.ContentType = resolvePartContentType(upload.ContentType, upload.Filename, fieldOpts)
+		return part, true
+	}
+
+	if fieldValue.Type().Implements(readerType) {
+		if kind := fieldValue.Kind(); (kind == reflect.Ptr || kind == reflect.Interface) && fieldValue.IsNil() {
+			return part, false
+		}
+		reader := fieldValue.Interface().(io.Reader)
+		upload := &FileUpload{Filename: fieldName, Reader: reader}
+		if file, ok := reader.(*os.File); ok {
+			upload.Filename = filepath.Base(file.Name())
+		}
+		upload.ContentType = resolvePartContentType("", upload.Filename, fieldOpts)
+		part.file = upload
+		return part, true
+	}
+
+	converted := convertBaseValueToString(fieldValue, urlEncode, fieldOpts)
+	if converted == nil {
+		return part, false
+	}
+
+	part.value = *converted
+
+	return part, true
+}
+
+// writeMultipartBody
+//
+// sets the request body to a multipart/form-data stream of the given parts. The body is written as the
+// transport reads it rather than buffered. When every file part is seekable, GetBody rewinds the files
+// and replays the body. A file part that cannot seek, such as a pipe, is sent once.
+func writeMultipartBody(r *http.Request, parts []multipartPart) error {
+	offsets := make(map[int]int64, len(parts))
+	replayable := true
+
+	for i, part := range parts {
+		if part.file == nil {
+			continue
+		}
+		seeker, ok := part.file.Reader.(io.Seeker)
+		if !ok {
+			replayable = false
+			continue
+		}
+
+		// not every io.Seeker is able to seek, such as a pipe opened as a file
+		offset, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			replayable = false
+			continue
+		}
+		offsets[i] = offset
+	}
+
+	body := newMultipartBody(parts)
+
+	r.Header.Set("Content-Type", body.writer.FormDataContentType())
+	r.Body = body
+	r.ContentLength = -1
+	r.GetBody = nil
+
+	if replayable {
+		boundary := body.writer.Boundary()
+		r.GetBody = func() (io.ReadCloser, error) {
+			for i, offset := range offsets {
+				if _, err := parts[i].file.Reader.(io.Seeker).Seek(offset, io.SeekStart); err != nil {
+					return nil, err
+				}
+			}
+			replay := newMultipartBody(parts)
+			if err := replay.writer.SetBoundary(boundary); err != nil {
+				return nil, err
+			}
+			return replay, nil
+		}
+	}
+
+	return nil
+}
+
+// multipartBody
+//
+// streams the parts through a pipe. The writing goroutine is only started once the body is first
+// read, so a request that is never sent does not leave a goroutine behind.
+type multipartBody struct {
+	parts  []multipartPart
+	reader *io.PipeReader
+	pipe   *io.PipeWriter
+	writer *multipart.Writer
+	start  sync.Once
+}
+
+func newMultipartBody(parts []multipartPart) *multipartBody {
+	pr, pw := io.Pipe()
+
+	return &multipartBody{parts: parts, reader: pr, pipe: pw, writer: multipart.NewWriter(pw)}
+}
+
+func (m *multipartBody) Read(b []byte) (int, error) {
+	m.start.Do(func() { go m.write() })
+
+	return m.reader.Read(b)
+}
+
+func (m *multipartBody) Close() error {
+	return m.reader.Close()
+}
+
+func (m *multipartBody) write() {
+	for _, part := range m.parts {
+		if err := writeMultipartPart(m.writer, part); err != nil {
+			m.pipe.CloseWithError(err)
+			return
+		}
+	}
+
+	m.pipe.CloseWithError(m.writer.Close())
+}
+
+// the values of the file tag, marking the fields of a files element
+const (
+	fileTagName        = "name"
+	fileTagContentType = "contentType"
+	fileTagContents    = "contents"
+)
+
+// readMultipartFiles
+//
+// converts each element of the slice or array into a file part named fieldName, in order. An element is a
+// FileUpload, an io.Reader or a struct whose fields tagged 'file' give the name, Content-Type and contents of
+// the file, while its fields tagged 'multipart' are written as plain parts following the file. Nil elements
+// are skipped.
+func readMultipartFiles(
+		fieldName string, fieldValue reflect.Value, urlEncode bool, fieldOpts clientFieldOptions,
+) ([]multipartPart, error) {
+	if isNilValue(fieldValue) {
+		return nil, nil
+	}
+	if fieldValue.Kind() != reflect.Slice && fieldValue.Kind() != reflect.Array {
+		return nil, fmt.Errorf("multipart files field %s must be a slice, got %s", fieldName, fieldValue.Type())
+	}
+
+	var parts []multipartPart
+
+	for i := 0; i < fieldValue.Len(); i++ {
+		element := fieldValue.Index(i)
+		if element.Kind() == reflect.Interface && !element.IsNil() {
+			element = element.Elem()
+		}
+		if isNilValue(element) {
+			continue
+		}
+
+		// readers, such as *os.File, and file uploads are parts of their own
+		if element.Type().Implements(readerType) || indirectType(element.Type()) == fileUploadType {
+			if part, ok := readMultipartPart(fieldName, element, urlEncode, fieldOpts); ok {
+				parts = append(parts, part)
+			}
+			continue
+		}
+
+		for element.Kind() == reflect.Ptr && !element.IsNil() {
+			element = element.Elem()
+		}
+		if element.Kind() == reflect.Ptr {
+			continue
+		}
+		if element.Kind() != reflect.Struct {
+			return nil, fmt.Errorf(
+				"element %d of multipart files field %s is not a file, got %s", i, fieldName, element.Type(),
+			)
+		}
+
+		entryParts, err := readFileEntry(fieldName, element, fieldOpts)
+		if err != nil {
+			return nil, fmt.Errorf("element %d of multipart files field %s: %w", i, fieldName, err)
+		}
+
+		parts = append(parts, entryParts...)
+	}
+
+	return parts, nil
+}
+
+// readFileEntry
+//
+// converts a struct element of a files field into its file part followed by its metadata parts
+func readFileEntry(fieldName string, entry reflect.Value, fieldOpts clientFieldOptions) ([]multipartPart, error) {
+	upload := &FileUpload{}
+	var explicitType string
+	var metadata []multipartPart
+
+	entryType := entry.Type()
+
+	for i := 0; i < entryType.NumField(); i++ {
+		fieldDesc := entryType.Field(i)
+		if !fieldDesc.IsExported() {
+			continue
+		}
+
+		fieldVal := entry.Field(i)
+
+		switch fieldDesc.Tag.Get("file") {
+		case fileTagName:
+			upload.Filename = fieldVal.String()
+			continue
+		case fileTagContentType:
+			explicitType = fieldVal.String()
+			continue
+		case fileTagContents:
+			if isNilValue(fieldVal) {
+				continue
+			}
+			switch contents := fieldVal.Interface().(type) {
+			case []byte:
+				upload.Reader = bytes.NewReader(contents)
+			case io.Reader:
+				upload.Reader = contents
+				if file, ok := contents.(*os.File); ok && upload.Filename == "" {
+					upload.Filename = filepath.Base(file.Name())
+				}
+			default:
+				return nil, fmt.Errorf(
+					"file contents %s must be an io.Reader or []byte, got %T", fieldDesc.Name, contents,
+				)
+			}
+			continue
+		}
+
+		requestTag, alias, jsonAlias, encode, metaOpts := readClientTag(fieldDesc)
+		if requestPart, _, _ := ParseRequestPart(requestTag); requestPart != RequestPartMultipart {
+			continue
+		}
+
+		name := fieldDesc.Name
+		if jsonAlias != "" {
+			name = jsonAlias
+		}
+		if alias != "" {
+			name = alias
+		}
+
+		metaEncode, _ := strconv.ParseBool(encode)
+		if part, ok := readMultipartPart(name, fieldVal, metaEncode, metaOpts); ok {
+			metadata = append(metadata, part)
+		}
+	}
+
+	if upload.Reader == nil {
+		return nil, fmt.Errorf("no contents set for the file of %s", fieldName)
+	}
+
+	upload.ContentType = resolvePartContentType(explicitType, upload.Filename, fieldOpts)
+
+	return append([]multipartPart{{fieldName: fieldName, file: upload}}, metadata...), nil
+}
+
+// indirectType
+//
+// the type once every pointer is dereferenced
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+// readFormPart
+//
+// converts a field tagged 'form' into a part of a multipart/form-data body, marshaled by its codec. An *os.File
+// field becomes a file part. The compression of a form body does not apply to a single part and is refused.
+func readFormPart(field formField) (multipartPart, error) {
+	part := multipartPart{fieldName: field.fieldName}
+
+	if !field.value.CanInterface() {
+		return part, fmt.Errorf("client generation failed, unable to get body of client field %s", field.fieldName)
+	}
+
+	if file, ok := field.value.Interface().(*os.File); ok {
+		if file == nil {
+			return part, fmt.Errorf("client generation failed, nil file of client field %s", field.fieldName)
+		}
+		filename := filepath.Base(file.Name())
+		part.file = &FileUpload{
+			Filename:    filename,
+			ContentType: resolvePartContentType("", filename, clientFieldOptions{}),
+			Reader:      file,
+		}
+		return part, nil
+	}
+
+	if field.compression != "" {
+		return part, fmt.Errorf(
+			"client generation failed, compression %s of client field %s is not supported in a multipart body",
+			field.compression, field.fieldName,
+		)
+	}
+
+	body, err := field.codec.Marshal(field.value.Interface())
+	if err != nil {
+		return part, fmt.Errorf("client generation failed, %s, of client field %s", err, field.fieldName)
+	}
+
+	part.value = string(body)
+	part.contentType = field.codec.ContentType()
+
+	return part, nil
+}
+
+// resolvePartContentType
+//
+// chooses the Content-Type of a file part: an explicit type, then the 'contentType' tag, then the type
+// registered for the file name extension
+func resolvePartContentType(explicit, filename string, fieldOpts clientFieldOptions) string {
+	if explicit != "" {
+		return explicit
+	}
+	if fieldOpts.contentType != "" {
+		return fieldOpts.contentType
+	}
+
+	return mime.TypeByExtension(filepath.Ext(filename))
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func writeMultipartPart(writer *multipart.Writer, part multipartPart) error {
+	if part.file == nil && part.contentType == "" {
+		return writer.WriteField(part.fieldName, part.value)
+	}
+
+	if part.file == nil {
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"`, quoteEscaper.Replace(part.fieldName)))
+		header.Set("Content-Type", part.contentType)
+
+		partWriter, err := writer.CreatePart(header)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.WriteString(partWriter, part.value)
+
+		return err
+	}
+
+	contentType := part.file.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	filename := part.file.Filename
+	if filename == "" {
+		filename = part.fieldName
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set(
+		"Content-Disposition", fmt.Sprintf(
+			`form-data; name="%s"; filename="%s"`, quoteEscaper.Replace(part.fieldName),
+			quoteEscaper.Replace(filename),
+		),
+	)
+	header.Set("Content-Type", contentType)
+
+	partWriter, err := writer.CreatePart(header)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(partWriter, part.file.Reader)
+
+	return err
+}
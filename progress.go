@@ -0,0 +1,57 @@
+package gkBoot
+
+import (
+	"io"
+	"net/http"
+)
+
+// progressReadCloser
+//
+// reports the running count of bytes read through it, along with the expected total (-1 when unknown)
+type progressReadCloser struct {
+	io.ReadCloser
+	read     int64
+	total    int64
+	progress func(current, total int64)
+}
+
+func (p *progressReadCloser) Read(b []byte) (int, error) {
+	n, err := p.ReadCloser.Read(b)
+	if n > 0 {
+		p.read += int64(n)
+		p.progress(p.read, p.total)
+	}
+
+	return n, err
+}
+
+func newProgressReadCloser(body io.ReadCloser, total int64, progress func(current, total int64)) io.ReadCloser {
+	if total <= 0 {
+		total = -1
+	}
+
+	return &progressReadCloser{ReadCloser: body, total: total, progress: progress}
+}
+
+// trackUploadProgress
+//
+// wraps the body of the request, and any replay of it through GetBody, so that the progress is reported as the
+// transport consumes the body
+func trackUploadProgress(r *http.Request, progress func(bytesSent, total int64)) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return
+	}
+
+	total := r.ContentLength
+	r.Body = newProgressReadCloser(r.Body, total, progress)
+
+	if getBody := r.GetBody; getBody != nil {
+		r.GetBody = func() (io.ReadCloser, error) {
+			body, err := getBody()
+			if err != nil {
+				return nil, err
+			}
+			return newProgressReadCloser(body, total, progress), nil
+		}
+	}
+}
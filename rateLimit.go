@@ -0,0 +1,146 @@
+package gkBoot
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// epochResetThreshold separates X-RateLimit-Reset values given as unix epoch seconds from those given as a
+// number of seconds to wait
+const epochResetThreshold = 1_000_000_000
+
+// RateLimit
+//
+// The rate limit state reported by the X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset headers
+// of a response, see ParseRateLimit. Limit and Remaining are -1 when their header is absent, Reset is zero.
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// ParseRateLimit
+//
+// Parses the rate limit headers of a response, as received by a response.HeaderReceiver, relative to now. The
+// reset may be given either as unix epoch seconds or as the number of seconds until the limit resets. The
+// boolean result is false when none of the headers is present.
+func ParseRateLimit(header http.Header, now time.Time) (RateLimit, bool) {
+	limit := RateLimit{Limit: -1, Remaining: -1}
+	found := false
+
+	if value, err := strconv.Atoi(strings.TrimSpace(header.Get("X-RateLimit-Limit"))); err == nil {
+		limit.Limit = value
+		found = true
+	}
+
+	if value, err := strconv.Atoi(strings.TrimSpace(header.Get("X-RateLimit-Remaining"))); err == nil {
+		limit.Remaining = value
+		found = true
+	}
+
+	if value, err := strconv.ParseInt(strings.TrimSpace(header.Get("X-RateLimit-Reset")), 10, 64); err == nil {
+		if value >= epochResetThreshold {
+			limit.Reset = time.Unix(value, 0)
+		} else if value >= 0 {
+			limit.Reset = now.Add(time.Duration(value) * time.Second)
+		}
+		found = true
+	}
+
+	return limit, found
+}
+
+// RateLimiter
+//
+// Shared by every request given the limiter through WithRateLimiter. A 429 Too Many Requests carrying a
+// Retry-After header, or a response reporting no remaining requests along with the reset of the limit,
+// pauses every later request until then. A RateLimiter is safe for concurrent use.
+type RateLimiter struct {
+	lock   sync.Mutex
+	resume time.Time
+}
+
+// NewRateLimiter
+//
+// Creates a limiter that lets requests through until a response reports the rate limit exhausted
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{}
+}
+
+// ResumeAt
+//
+// The time until which requests are paused, in the past or zero when requests are let through
+func (l *RateLimiter) ResumeAt() time.Time {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.resume
+}
+
+// Wait
+//
+// Blocks until requests are let through again, returning the error of the context when it is done first
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	wait := time.Until(l.ResumeAt())
+	if wait <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Observe
+//
+// Records the rate limit reported by the response, extending the pause of later requests
+func (l *RateLimiter) Observe(resp *http.Response) {
+	now := time.Now()
+
+	var resume time.Time
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if wait, ok := ParseRetryAfter(resp.Header.Get("Retry-After"), now, 0); ok {
+			resume = now.Add(wait)
+		}
+	}
+
+	if limit, ok := ParseRateLimit(resp.Header, now); ok && limit.Remaining == 0 && limit.Reset.After(resume) {
+		resume = limit.Reset
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if resume.After(l.resume) {
+		l.resume = resume
+	}
+}
+
+// wrap
+//
+// waits for the limiter before every attempt sent through send, observing each response
+func (l *RateLimiter) wrap(send RoundTripFunc) RoundTripFunc {
+	return func(r *http.Request) (*http.Response, error) {
+		if err := l.Wait(r.Context()); err != nil {
+			return nil, err
+		}
+
+		resp, err := send(r)
+		if err == nil {
+			l.Observe(resp)
+		}
+
+		return resp, err
+	}
+}
@@ -79,3 +79,27 @@ type OpenAPIExtended interface {
 type OpenAPISecure interface {
 	OpenAPISecurity() []map[string][]string
 }
+
+// BasicAuth
+//
+// When embedded into (or used as an untagged field of) a request object, the client sends the credentials
+// in the Authorization header using the basic scheme. Skipped when both values are empty.
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+// BearerToken
+//
+// When used as the type of an untagged field of a request object, the client sends the token in the
+// Authorization header using the bearer scheme. Skipped when empty.
+type BearerToken string
+
+// BodyProvider
+//
+// Provides the document sent as the body of a client request, with any method. The document is marshaled in
+// place of the request object, so path, query and header fields of the same request are not repeated in the
+// body, and a PATCH may send a partial document. A nil document sends no body.
+type BodyProvider interface {
+	Body() interface{}
+}
@@ -0,0 +1,166 @@
+package gkBoot
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/yomiji/gkBoot/helpers"
+	"github.com/yomiji/gkBoot/request"
+)
+
+// RequestDoc
+//
+// The plan followed by GenerateClientRequest for a request object, as returned by GenerateRequestDoc, from
+// which API documentation or client SDKs may be generated. Path is the route path template, holding a {name}
+// placeholder for each path parameter.
+type RequestDoc struct {
+	Name        string
+	Method      string
+	Path        string
+	Description string
+	Params      []ParamDoc
+}
+
+// ParamDoc
+//
+// A field of a request object and the part of the request it is written to. Name is the name of the field in
+// that part, resolved from its alias, json or field name, Field the name of the struct field and Type its Go
+// type. Enum lists the values allowed by an 'enum' tag, if any.
+type ParamDoc struct {
+	Part     RequestPart
+	Name     string
+	Field    string
+	Type     string
+	Required bool
+	Enum     []string
+}
+
+// In
+//
+// Returns the parameters written to the given part of the request, in the order of their fields.
+func (d RequestDoc) In(part RequestPart) []ParamDoc {
+	var params []ParamDoc
+	for _, param := range d.Params {
+		if param.Part == part {
+			params = append(params, param)
+		}
+	}
+
+	return params
+}
+
+// GenerateRequestDoc
+//
+// Describes how the request object is sent, reading the same tags as GenerateClientRequest: the method and
+// path template of its route and each parameter, categorized by the part of the request it is written to.
+// Embedded structs are described in place of their fields. A request object embedding JSONBody or
+// implementing request.BodyProvider is described with a RequestPartForm parameter for its whole body, named
+// after its type. A field is required when its tag carries a trailing '!' or its requiredFor tag lists the
+// method of the route.
+func GenerateRequestDoc(serviceRequest request.HttpRequest) (RequestDoc, error) {
+	if serviceRequest == nil {
+		return RequestDoc{}, errors.New("nil request not supported")
+	}
+
+	info := serviceRequest.Info()
+	doc := RequestDoc{
+		Name:        info.Name,
+		Method:      string(info.Method),
+		Path:        info.Path,
+		Description: info.Description,
+	}
+
+	if doc.Name == "" {
+		doc.Name = helpers.GetFriendlyRequestName(serviceRequest)
+	}
+
+	requestType := indirectType(reflect.TypeOf(serviceRequest))
+	if requestType.Kind() != reflect.Struct {
+		return doc, fmt.Errorf("request object '%s' must be a Struct type", doc.Name)
+	}
+
+	_, isBodyProvider := serviceRequest.(request.BodyProvider)
+	if _, isJSONBody := serviceRequest.(jsonBody); isJSONBody || isBodyProvider {
+		doc.Params = append(
+			doc.Params, ParamDoc{Part: RequestPartForm, Name: requestType.Name(), Type: requestType.String()},
+		)
+	}
+
+	params, err := describeFields(requestType, doc.Method)
+	doc.Params = append(doc.Params, params...)
+
+	return doc, err
+}
+
+// describeFields
+//
+// describes the tagged fields of the struct type as assignRequest writes them, descending into embedded and
+// untagged struct fields
+func describeFields(structType reflect.Type, method string) ([]ParamDoc, error) {
+	var params []ParamDoc
+	var errs []error
+
+	for i := 0; i < structType.NumField(); i++ {
+		fieldDesc := structType.Field(i)
+
+		requestTag, alias, jsonAlias, _, _ := readClientTag(fieldDesc)
+		requestPart, required, ok := ParseRequestPart(requestTag)
+		required = required || requiredForMethod(fieldDesc, method)
+
+		if requestTag == "" && isAuthorizationType(fieldDesc.Type) {
+			params = append(
+				params, ParamDoc{
+					Part:  RequestPartHeader,
+					Name:  "Authorization",
+					Field: fieldDesc.Name,
+					Type:  fieldDesc.Type.String(),
+				},
+			)
+			continue
+		}
+
+		if requestTag == "" {
+			if fieldDesc.Type.Kind() == reflect.Struct || isEmbeddedStructPointer(fieldDesc) {
+				nested, err := describeFields(indirectType(fieldDesc.Type), method)
+				params = append(params, nested...)
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown 'client' operation: %s", requestTag))
+			continue
+		}
+
+		fieldName := fieldDesc.Name
+		if requestPart != RequestPartRaw {
+			if jsonAlias != "" {
+				fieldName = jsonAlias
+			}
+			if alias != "" {
+				fieldName = alias
+			}
+		}
+
+		param := ParamDoc{
+			Part:     requestPart,
+			Name:     fieldName,
+			Field:    fieldDesc.Name,
+			Type:     fieldDesc.Type.String(),
+			Required: required,
+		}
+
+		if tag, ok := fieldDesc.Tag.Lookup("enum"); ok {
+			for _, value := range strings.Split(tag, ",") {
+				param.Enum = append(param.Enum, strings.TrimSpace(value))
+			}
+		}
+
+		params = append(params, param)
+	}
+
+	return params, errors.Join(errs...)
+}
@@ -0,0 +1,80 @@
+package gkBoot
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// RequestPart
+//
+// The part of the http request a field is read from by GenerateRequestDecoder and written to by
+// GenerateClientRequest, as named by the 'request' tag of the field.
+type RequestPart string
+
+const (
+	// RequestPartPath replaces the {name} placeholder of the route path
+	RequestPartPath RequestPart = "path"
+	// RequestPartQuery is a query parameter
+	RequestPartQuery RequestPart = "query"
+	// RequestPartHeader is a request header
+	RequestPartHeader RequestPart = "header"
+	// RequestPartCookie is a request cookie
+	RequestPartCookie RequestPart = "cookie"
+	// RequestPartForm is the marshaled request body, or a part of a multipart/form-data body along with
+	// other form or multipart fields
+	RequestPartForm RequestPart = "form"
+	// RequestPartMultipart is a part of a multipart/form-data request body, only written by the client
+	RequestPartMultipart RequestPart = "multipart"
+	// RequestPartQueryRest expands a map of names to values into arbitrary query parameters, such as the
+	// filters forwarded by a pass-through proxy, only written by the client
+	RequestPartQueryRest RequestPart = "queryRest"
+	// RequestPartRaw is the request body sent as given, from a []byte, string or io.Reader field, only written
+	// by the client
+	RequestPartRaw RequestPart = "raw"
+	// RequestPartFiles is a slice of files, each element a file part of a multipart/form-data request body,
+	// only written by the client
+	RequestPartFiles RequestPart = "files"
+)
+
+// RequestParts
+//
+// Lists every supported request part.
+func RequestParts() []RequestPart {
+	return []RequestPart{
+		RequestPartPath, RequestPartQuery, RequestPartHeader, RequestPartCookie, RequestPartForm,
+		RequestPartMultipart, RequestPartQueryRest, RequestPartRaw, RequestPartFiles,
+	}
+}
+
+// ParseRequestPart
+//
+// Parses the value of a 'request' tag, such as "query!,omitempty", into its part and whether the trailing '!'
+// marks the field as required. Modifiers following a comma are ignored. The boolean result is false when the
+// part is not one of RequestParts.
+func ParseRequestPart(tag string) (part RequestPart, required bool, ok bool) {
+	tag, _, _ = strings.Cut(tag, ",")
+	required = strings.HasSuffix(tag, "!")
+	part = RequestPart(strings.TrimSuffix(tag, "!"))
+
+	return part, required, slices.Contains(RequestParts(), part)
+}
+
+// requiredForMethod
+//
+// reports whether the 'requiredFor' tag of the field, a comma separated list of methods such as "POST,PUT",
+// names the method of the request, compared without regard to case
+func requiredForMethod(field reflect.StructField, method string) bool {
+	tag, ok := field.Tag.Lookup("requiredFor")
+	if !ok {
+		return false
+	}
+
+	for _, listed := range strings.Split(tag, ",") {
+		if strings.EqualFold(strings.TrimSpace(listed), method) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,29 +1,127 @@
 package response
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"sync"
 )
 
+// StreamReceiver
+// Receives the unread response for processing instead of performing a JSON marshal operation. The receiver owns
+// the response body and must close it. Takes precedence over every other body interface
+type StreamReceiver interface {
+	ReceiveStream(resp *http.Response) error
+}
+
+// StreamTo
+// Receives the body of a 2xx response copied into the writer returned by StreamWriter, such as a file, without
+// buffering it, instead of performing a JSON marshal operation. BodyWritten receives the number of bytes
+// written, even when the copy fails part way. Bodies of other statuses are handled as usual. Takes precedence
+// over CaptureReader, RawBodyReceiver and json.Unmarshaler
+type StreamTo interface {
+	StreamWriter() io.Writer
+	BodyWritten(n int64)
+}
+
 // CaptureReader
-// Captures the reader for processing instead of performing a JSON marshal operation
+// Captures the reader for processing instead of performing a JSON marshal operation. Takes precedence over
+// RawBodyReceiver and json.Unmarshaler. When the object also implements ErredResponse, the body of an error
+// status is given to NewError rather than captured
 type CaptureReader interface {
 	Capture(reader io.Reader) error
 }
 
+// CodedCaptureReader
+// Captures the reader along with the status code of the response, for any status, instead of performing a
+// JSON marshal operation. Takes precedence over CaptureReader
+type CodedCaptureReader interface {
+	CaptureWithCode(code int, reader io.Reader) error
+}
+
+// RawBodyReceiver
+// Receives the fully read response body instead of performing a JSON marshal operation. Takes precedence over
+// json.Unmarshaler
+type RawBodyReceiver interface {
+	ReceiveBody(body []byte) error
+}
+
 // CodedResponse
 // An object implementing this can track the response code from server / client. Complements kitDefaults.StatusCoder
 type CodedResponse interface {
 	NewCode(code int)
 }
 
+// HeaderReceiver
+// An object implementing this receives the headers of the response from the server / client, regardless of status
+type HeaderReceiver interface {
+	CaptureHeaders(header http.Header)
+}
+
+// HeaderCapture
+// An alternative name for HeaderReceiver, complementing CaptureReader
+type HeaderCapture = HeaderReceiver
+
+// CorrelationReceiver
+// An object implementing this receives the correlation ID echoed by the server when the request was sent
+// with gkBoot.WithCorrelationID
+type CorrelationReceiver interface {
+	NewCorrelationID(id string)
+}
+
+// BodyStatusCoder
+// An object implementing this reports the logical status carried in the response body, for APIs that answer
+// with 200 and place the real status in a body field. When ok, the status replaces the HTTP status for
+// CodedResponse, ErredResponse and typed error handling
+type BodyStatusCoder interface {
+	StatusFromBody(body []byte) (code int, ok bool)
+}
+
+// AcceptableCoder
+// An object implementing this declares the status codes indicating success, for APIs answering with 201, 202
+// or 204. ErredResponse receives an error only for a status outside of the declared codes, rather than for
+// any status other than 200
+type AcceptableCoder interface {
+	AcceptableCodes() []int
+}
+
 // ErredResponse
 // An object implementing this can track the error from the server / client. Complements error interface
 type ErredResponse interface {
 	NewError(code int, format string, vars ...interface{})
 }
 
+// Optional
+// An object implementing this is told whether the resource was absent when the request was sent with
+// gkBoot.WithNotFoundAsAbsent, a 404 Not Found then resulting in no error
+type Optional interface {
+	SetAbsent(absent bool)
+}
+
+// Download
+//
+// When used as (or embedded into) a Response object, this streams the body of a 2xx response into Writer,
+// recording the number of bytes written in Written
+type Download struct {
+	Writer  io.Writer
+	Written int64
+}
+
+// StreamWriter
+//
+// Implements StreamTo
+func (d *Download) StreamWriter() io.Writer {
+	return d.Writer
+}
+
+// BodyWritten
+//
+// Implements StreamTo
+func (d *Download) BodyWritten(n int64) {
+	d.Written = n
+}
+
 // BasicResponse
 //
 // When embedded into a Response object, this wil provide basic functionality
@@ -42,6 +140,75 @@ func (b *BasicResponse) NewCode(code int) {
 	b.code = code
 }
 
+// Correlated
+//
+// When embedded into a Response object, this records the correlation ID echoed by the server
+type Correlated struct {
+	correlationID string
+}
+
+// CorrelationID
+//
+// Returns the correlation ID echoed by the server, empty when the server did not echo it
+func (c Correlated) CorrelationID() string {
+	return c.correlationID
+}
+
+// NewCorrelationID
+//
+// Implements CorrelationReceiver
+func (c *Correlated) NewCorrelationID(id string) {
+	c.correlationID = id
+}
+
+// Presence
+//
+// When embedded into a Response object, this records whether the resource was absent, see Optional
+type Presence struct {
+	absent bool
+}
+
+// Absent
+//
+// Returns true when the server answered 404 Not Found
+func (p Presence) Absent() bool {
+	return p.absent
+}
+
+// SetAbsent
+//
+// Implements Optional
+func (p *Presence) SetAbsent(absent bool) {
+	p.absent = absent
+}
+
+// Envelope
+//
+// When used as (or embedded into) a Response object, this captures the status code, the headers and the
+// typed body of the response in one place. The response body is decoded into Data.
+type Envelope[T any] struct {
+	BasicResponse
+	Data    T
+	Headers http.Header
+}
+
+// CaptureHeaders
+//
+// Implements HeaderReceiver
+func (e *Envelope[T]) CaptureHeaders(header http.Header) {
+	e.Headers = header.Clone()
+}
+
+// UnmarshalJSON
+//
+// Implements json.Unmarshaler, decoding the body into Data. An empty body leaves Data unchanged.
+func (e *Envelope[T]) UnmarshalJSON(body []byte) error {
+	if len(body) == 0 {
+		return nil
+	}
+	return json.Unmarshal(body, &e.Data)
+}
+
 // ErrorResponse
 //
 // When embedded into a Response object, this wil provide error handling functionality
@@ -87,17 +254,40 @@ type ExtendedLog interface {
 	GetAll() map[string]interface{}
 }
 
+// OrderedExtendedLog
+//
+// Implemented by responses able to give their request-scoped log values in the order they were logged
+type OrderedExtendedLog interface {
+	GetOrdered() []KV
+}
+
+// KV
+//
+// A single request-scoped log value
+type KV struct {
+	Key   string
+	Value interface{}
+}
+
+// MissingValue
+//
+// Recorded by ExpandedLogging.Log for a trailing key given without a value, as go-kit loggers do
+const MissingValue = "(MISSING)"
+
 // ExpandedLogging
 //
 // Added to a response, should enable additional request-scoped log values
 type ExpandedLogging struct {
 	lvalues map[string]interface{}
-	lock    sync.Mutex
+	// lkeys holds each key once, in the order it was first logged
+	lkeys []string
+	lock  sync.Mutex
 }
 
 // Log
 //
-// create a new log entry to be traversed later
+// create a new log entry to be traversed later. Logging a key again replaces its value while keeping the
+// position of the key. A trailing key without a value is recorded with MissingValue
 func (l *ExpandedLogging) Log(values ...interface{}) {
 	l.lock.Lock()
 	defer l.lock.Unlock()
@@ -105,10 +295,14 @@ func (l *ExpandedLogging) Log(values ...interface{}) {
 		l.lvalues = make(map[string]interface{})
 	}
 	for i := 0; i < len(values); i += 2 {
+		key := fmt.Sprintf("%s", values[i])
+		if _, exists := l.lvalues[key]; !exists {
+			l.lkeys = append(l.lkeys, key)
+		}
 		if i+1 >= len(values) {
-			l.lvalues[fmt.Sprintf("%s", values[i])] = nil
+			l.lvalues[key] = MissingValue
 		} else {
-			l.lvalues[fmt.Sprintf("%s", values[i])] = values[i+1]
+			l.lvalues[key] = values[i+1]
 		}
 	}
 }
@@ -125,3 +319,16 @@ func (l *ExpandedLogging) GetAll() map[string]interface{} {
 	}
 	return result
 }
+
+// GetOrdered
+//
+// returns the logged values in the order each key was first logged
+func (l *ExpandedLogging) GetOrdered() []KV {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	result := make([]KV, 0, len(l.lkeys))
+	for _, k := range l.lkeys {
+		result = append(result, KV{Key: k, Value: l.lvalues[k]})
+	}
+	return result
+}
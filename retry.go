@@ -0,0 +1,289 @@
+package gkBoot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxRetryAfter is the largest wait representable as a time.Duration
+const maxRetryAfter = time.Duration(math.MaxInt64)
+
+// ParseRetryAfter
+//
+// Parses the value of a Retry-After header, which may be given either as a number of delay-seconds or as an
+// HTTP-date, into the duration to wait relative to now. A date in the past results in a zero wait. When
+// maxDelay is greater than zero the result is clamped to it. The boolean result is false when the value
+// could not be parsed.
+func ParseRetryAfter(value string, now time.Time, maxDelay time.Duration) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	var wait time.Duration
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		// guard against overflow of very large delays
+		if seconds > int64(maxRetryAfter/time.Second) {
+			wait = maxRetryAfter
+		} else {
+			wait = time.Duration(seconds) * time.Second
+		}
+	} else if date, err := http.ParseTime(value); err == nil {
+		wait = date.Sub(now)
+		if wait < 0 {
+			wait = 0
+		}
+	} else {
+		return 0, false
+	}
+
+	if maxDelay > 0 && wait > maxDelay {
+		wait = maxDelay
+	}
+
+	return wait, true
+}
+
+// RetryPolicy
+//
+// Determines whether and when a failed request is sent again. Used with WithRetryPolicy or
+// DoGeneratedRequestWithRetry.
+type RetryPolicy struct {
+	// MaxAttempts
+	//
+	// The total number of attempts, including the first. Values below 2 disable retries.
+	MaxAttempts int
+	// BaseDelay
+	//
+	// The wait before the first retry, doubled for every retry after it.
+	BaseDelay time.Duration
+	// MaxDelay
+	//
+	// The largest wait between attempts, including a wait requested by a Retry-After header. Unlimited when
+	// zero.
+	MaxDelay time.Duration
+	// Jitter
+	//
+	// The fraction, between 0 and 1, of each wait that is randomized so that many clients retrying at once
+	// spread out. A Jitter of 0.5 waits between half and the whole of the computed delay.
+	Jitter float64
+	// Retryable
+	//
+	// Decides whether the attempt that produced the response or error is retried. DefaultRetryable is used
+	// when nil.
+	Retryable func(r *http.Request, resp *http.Response, err error) bool
+}
+
+// IdempotencyKeyHeader
+//
+// The header carrying the idempotency key of a request, see WithIdempotencyKey
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// DefaultRetryPolicy
+//
+// The policy of WithIdempotentRetries, making 3 attempts with a jittered wait of up to 100ms before the first
+// retry and 200ms before the second
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   100 * time.Millisecond,
+	MaxDelay:    2 * time.Second,
+	Jitter:      0.5,
+}
+
+// DefaultRetryable
+//
+// Retries idempotent requests (GET, HEAD, OPTIONS, TRACE, PUT and DELETE) that failed in transport, or that
+// received a 429, 502, 503 or 504 status. A POST or PATCH is retried only when it carries an
+// IdempotencyKeyHeader, which lets the server discard a repeated attempt. Requests aborted by their context
+// are never retried.
+func DefaultRetryable(r *http.Request, resp *http.Response, err error) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+	case http.MethodPost, http.MethodPatch:
+		if r.Header.Get(IdempotencyKeyHeader) == "" {
+			return false
+		}
+	default:
+		return false
+	}
+
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// delay
+//
+// the wait before the given retry, one being the first, honoring a Retry-After header on the response
+func (p RetryPolicy) delay(retry int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now(), p.MaxDelay); ok {
+			return wait
+		}
+	}
+
+	wait := p.BaseDelay
+	for i := 1; i < retry && wait < maxRetryAfter/2; i++ {
+		wait *= 2
+	}
+
+	if p.MaxDelay > 0 && wait > p.MaxDelay {
+		wait = p.MaxDelay
+	}
+
+	if jitter := math.Min(math.Max(p.Jitter, 0), 1); jitter > 0 {
+		wait -= time.Duration(rand.Float64() * jitter * float64(wait))
+	}
+
+	return wait
+}
+
+// RetryBudget
+//
+// A token bucket shared by every request given the budget through WithRetryBudget, capping the retries sent
+// across those requests so that an upstream outage does not multiply the load on it. Each retry withdraws a
+// token; tokens are refilled at a steady rate up to the capacity of the bucket. A RetryBudget is safe for
+// concurrent use.
+type RetryBudget struct {
+	lock       sync.Mutex
+	tokens     float64
+	capacity   float64
+	refillRate float64
+	refilled   time.Time
+}
+
+// NewRetryBudget
+//
+// Creates a full budget of capacity retries, refilled by refillPerSecond retries each second. A refill rate of
+// zero never refills the budget.
+func NewRetryBudget(capacity int, refillPerSecond float64) *RetryBudget {
+	return &RetryBudget{
+		tokens:     float64(capacity),
+		capacity:   float64(capacity),
+		refillRate: refillPerSecond,
+		refilled:   time.Now(),
+	}
+}
+
+// Allow
+//
+// Withdraws a token for a single retry, reporting false when the budget is exhausted
+func (b *RetryBudget) Allow() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	now := time.Now()
+	if b.refillRate > 0 {
+		b.tokens = math.Min(b.capacity, b.tokens+now.Sub(b.refilled).Seconds()*b.refillRate)
+	}
+	b.refilled = now
+
+	if b.tokens < 1 {
+		return false
+	}
+
+	b.tokens--
+
+	return true
+}
+
+// sendWithRetry
+//
+// sends the request, retrying according to the policy. The body is replayed for every attempt through
+// GetBody, buffering it first when the request cannot replay it. Retries stop early once the context of
+// the request is done, its deadline would pass before the next attempt or the budget is exhausted,
+// returning the last result.
+func sendWithRetry(
+		send RoundTripFunc, r *http.Request, policy RetryPolicy, budget *RetryBudget,
+) (*http.Response, error) {
+	retryable := policy.Retryable
+	if retryable == nil {
+		retryable = DefaultRetryable
+	}
+
+	if r.Body != nil && r.Body != http.NoBody && r.GetBody == nil && policy.MaxAttempts > 1 {
+		if err := bufferRequestBody(r); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx := r.Context()
+	attempt := r
+
+	for retry := 1; ; retry++ {
+		resp, err := send(attempt)
+		if retry >= policy.MaxAttempts || !retryable(r, resp, err) {
+			return resp, err
+		}
+
+		wait := policy.delay(retry, resp)
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(wait).After(deadline) {
+			return resp, err
+		}
+
+		if budget != nil && !budget.Allow() {
+			return resp, err
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		attempt = r.Clone(ctx)
+		if r.GetBody != nil {
+			body, bodyErr := r.GetBody()
+			if bodyErr != nil {
+				return nil, fmt.Errorf("unable to replay request body for retry: %w", bodyErr)
+			}
+			attempt.Body = body
+		}
+	}
+}
+
+// bufferRequestBody
+//
+// reads the body into memory so that GetBody is able to replay it
+func bufferRequestBody(r *http.Request) error {
+	body, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		return fmt.Errorf("unable to buffer request body for retry: %w", err)
+	}
+
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+
+	return nil
+}
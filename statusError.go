@@ -0,0 +1,131 @@
+package gkBoot
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/yomiji/gkBoot/response"
+)
+
+var (
+	// ErrUnauthorized may be mapped to 401 Unauthorized responses with WithStatusError
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden may be mapped to 403 Forbidden responses with WithStatusError
+	ErrForbidden = errors.New("forbidden")
+	// ErrNotFound may be mapped to 404 Not Found responses with WithStatusError, and is returned for them with
+	// WithNotFoundAsAbsent
+	ErrNotFound = errors.New("not found")
+	// ErrRateLimited may be mapped to 429 Too Many Requests responses with WithStatusError
+	ErrRateLimited = errors.New("rate limited")
+)
+
+// statusError
+//
+// an error mapped to the responses with a status in its range
+type statusError struct {
+	codes StatusRange
+	err   error
+}
+
+// StatusError
+//
+// Returned for a response whose status is mapped to an error with WithStatusError. Unwrap exposes the mapped
+// error, so callers may match it with errors.Is, along with the error decoded from the body by WithErrorType
+// when there is one. RetryAfter holds the wait requested by the Retry-After header of the response, such as
+// that of a 429 Too Many Requests, and is zero when the header is absent.
+type StatusError struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	RetryAfter time.Duration
+	// Err is the error mapped to the status
+	Err error
+	// Cause is the error decoded from the body by the ErrorDecoder, nil when there is none
+	Cause error
+}
+
+func (s *StatusError) Error() string {
+	message := strconv.Itoa(s.StatusCode) + " " + http.StatusText(s.StatusCode) + ": " + s.Err.Error()
+	if s.Cause != nil {
+		message += ": " + s.Cause.Error()
+	}
+	if s.RetryAfter > 0 {
+		message += ", retry after " + s.RetryAfter.String()
+	}
+
+	return message
+}
+
+func (s *StatusError) Unwrap() []error {
+	if s.Cause == nil {
+		return []error{s.Err}
+	}
+
+	return []error{s.Err, s.Cause}
+}
+
+// mapStatusError
+//
+// builds the StatusError of the first mapping whose range contains the status. The boolean result is false
+// when the status is not mapped.
+func mapStatusError(resp *http.Response, status int, body []byte, cfg *ClientConfig) (*StatusError, bool) {
+	for _, mapping := range cfg.statusErrors {
+		if !mapping.codes.Contains(status) {
+			continue
+		}
+
+		statusErr := &StatusError{
+			StatusCode: status,
+			Header:     resp.Header.Clone(),
+			Body:       append([]byte(nil), body...),
+			Err:        mapping.err,
+		}
+
+		if wait, ok := ParseRetryAfter(resp.Header.Get("Retry-After"), cfg.Clock(), 0); ok {
+			statusErr.RetryAfter = wait
+		}
+
+		return statusErr, true
+	}
+
+	return nil, false
+}
+
+// absentNotFound
+//
+// tells a response.Optional whether the resource was absent when NotFoundAsAbsent is set. A 404 Not Found is
+// then no error for a response.Optional, and a *StatusError wrapping ErrNotFound for any other response object.
+// The boolean result is false when the response is to be handled as usual.
+func absentNotFound(resp *http.Response, responseObj interface{}, cfg *ClientConfig) (error, bool) {
+	if !cfg.NotFoundAsAbsent {
+		return nil, false
+	}
+
+	optional, isOptional := responseObj.(response.Optional)
+	if isOptional {
+		optional.SetAbsent(resp.StatusCode == http.StatusNotFound)
+	}
+
+	if resp.StatusCode != http.StatusNotFound {
+		return nil, false
+	}
+
+	defer resp.Body.Close()
+
+	if isOptional {
+		return nil, true
+	}
+
+	// a body that fails to read is left out, the status alone still reports the resource as not found
+	body, _ := io.ReadAll(resp.Body)
+
+	return &StatusError{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		Body:       body,
+		Err:        ErrNotFound,
+	}, true
+}
@@ -0,0 +1,91 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/yomiji/gkBoot"
+	"github.com/yomiji/gkBoot/request"
+)
+
+type BasicAuthTestRequest struct {
+	request.BasicAuth
+	Trace string `request:"header" alias:"X-Trace"`
+}
+
+func (b BasicAuthTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "BasicAuthTest",
+		Method:      request.GET,
+		Path:        "/secure",
+		Description: "A test of the basic auth helper",
+	}
+}
+
+type BearerTokenTestRequest struct {
+	Token *request.BearerToken
+	ID    int `request:"query" alias:"id"`
+}
+
+func (b BearerTokenTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "BearerTokenTest",
+		Method:      request.GET,
+		Path:        "/secure",
+		Description: "A test of the bearer token helper",
+	}
+}
+
+func TestAuthorizationHelpers(t *testing.T) {
+	t.Run(
+		"Basic Auth", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest(
+				"http://localhost:8080",
+				BasicAuthTestRequest{BasicAuth: request.BasicAuth{Username: "alice", Password: "s3cret"}, Trace: "t1"},
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if r.Header.Get("Authorization") != "Basic YWxpY2U6czNjcmV0" {
+				subT.Fatalf("unexpected Authorization header '%s'", r.Header.Get("Authorization"))
+			}
+			if r.Header.Get("X-Trace") != "t1" {
+				subT.Fatalf("expected the tagged header alongside basic auth, got '%s'", r.Header.Get("X-Trace"))
+			}
+		},
+	)
+
+	t.Run(
+		"Bearer Token", func(subT *testing.T) {
+			token := request.BearerToken("abc.def")
+			r, err := gkBoot.GenerateClientRequest("http://localhost:8080", BearerTokenTestRequest{Token: &token, ID: 4})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if r.Header.Get("Authorization") != "Bearer abc.def" {
+				subT.Fatalf("unexpected Authorization header '%s'", r.Header.Get("Authorization"))
+			}
+			if r.URL.Query().Get("id") != "4" {
+				subT.Fatalf("expected the query alongside the token, got '%s'", r.URL.RawQuery)
+			}
+		},
+	)
+
+	t.Run(
+		"Empty Values Skipped", func(subT *testing.T) {
+			basic, err := gkBoot.GenerateClientRequest("http://localhost:8080", BasicAuthTestRequest{})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			bearer, err := gkBoot.GenerateClientRequest("http://localhost:8080", BearerTokenTestRequest{})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if basic.Header.Get("Authorization") != "" || bearer.Header.Get("Authorization") != "" {
+				subT.Fatalf(
+					"expected no Authorization header, got '%s' and '%s'", basic.Header.Get("Authorization"),
+					bearer.Header.Get("Authorization"),
+				)
+			}
+		},
+	)
+}
@@ -0,0 +1,182 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/yomiji/gkBoot"
+	"github.com/yomiji/gkBoot/request"
+)
+
+type RPCTestCall struct {
+	gkBoot.JSONBody
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  []int  `json:"params"`
+	ID      *int   `json:"id,omitempty"`
+}
+
+func (r RPCTestCall) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "RPCTest",
+		Method:      request.POST,
+		Path:        "/rpc",
+		Description: "A test of JSON-RPC batching",
+	}
+}
+
+type RPCTestResponse struct {
+	ID     int `json:"id"`
+	Result int `json:"result"`
+}
+
+func rpcCall(method string, id int, params ...int) RPCTestCall {
+	return RPCTestCall{JSONRPC: "2.0", Method: method, Params: params, ID: &id}
+}
+
+func TestDoBatchRequest(t *testing.T) {
+	var requests int
+	var received []RPCTestCall
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if r.Method != http.MethodPost || r.URL.Path != "/rpc" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				body, _ := io.ReadAll(r.Body)
+				received = nil
+				if err := json.Unmarshal(body, &received); err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+
+				// answer in reverse order, skipping notifications and the "drop" method
+				var results []map[string]any
+				for i := len(received) - 1; i >= 0; i-- {
+					call := received[i]
+					if call.ID == nil || call.Method == "drop" {
+						continue
+					}
+					result := 0
+					for _, p := range call.Params {
+						if call.Method == "sum" {
+							result += p
+						} else {
+							if result == 0 {
+								result = 1
+							}
+							result *= p
+						}
+					}
+					results = append(results, map[string]any{"jsonrpc": "2.0", "id": *call.ID, "result": result})
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(results)
+			},
+		),
+	)
+	defer srv.Close()
+
+	t.Run(
+		"Three Calls Demultiplexed", func(subT *testing.T) {
+			requests = 0
+			sum, product, other := new(RPCTestResponse), new(RPCTestResponse), new(RPCTestResponse)
+
+			err := gkBoot.DoBatchRequest(
+				srv.URL, []gkBoot.BatchCall{
+					{Request: rpcCall("sum", 1, 1, 2, 3), Response: sum},
+					{Request: rpcCall("product", 2, 2, 3, 4), Response: product},
+					{Request: rpcCall("sum", 3, 10, 20), Response: other},
+				},
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if requests != 1 || len(received) != 3 {
+				subT.Fatalf("expected a single request of 3 calls, got %d requests of %d calls", requests, len(received))
+			}
+			if sum.ID != 1 || sum.Result != 6 {
+				subT.Fatalf("unexpected sum response %+v", sum)
+			}
+			if product.ID != 2 || product.Result != 24 {
+				subT.Fatalf("unexpected product response %+v", product)
+			}
+			if other.ID != 3 || other.Result != 30 {
+				subT.Fatalf("unexpected other response %+v", other)
+			}
+		},
+	)
+
+	t.Run(
+		"Notification", func(subT *testing.T) {
+			sum := new(RPCTestResponse)
+
+			err := gkBoot.DoBatchRequest(
+				srv.URL, []gkBoot.BatchCall{
+					{Request: RPCTestCall{JSONRPC: "2.0", Method: "log", Params: []int{1}}},
+					{Request: rpcCall("sum", 7, 1, 1), Response: sum},
+				},
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if len(received) != 2 || sum.Result != 2 {
+				subT.Fatalf("unexpected result %+v of %d calls", sum, len(received))
+			}
+		},
+	)
+
+	t.Run(
+		"Unanswered Call", func(subT *testing.T) {
+			err := gkBoot.DoBatchRequest(
+				srv.URL, []gkBoot.BatchCall{
+					{Request: rpcCall("sum", 1, 1), Response: new(RPCTestResponse)},
+					{Request: rpcCall("drop", 2), Response: new(RPCTestResponse)},
+				},
+			)
+			if err == nil || !strings.Contains(err.Error(), "id 2") {
+				subT.Fatalf("expected an unanswered call error, got %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Missing ID", func(subT *testing.T) {
+			err := gkBoot.DoBatchRequest(
+				srv.URL, []gkBoot.BatchCall{
+					{Request: RPCTestCall{JSONRPC: "2.0", Method: "sum"}, Response: new(RPCTestResponse)},
+				},
+			)
+			if err == nil || !strings.Contains(err.Error(), "has no id") {
+				subT.Fatalf("expected a missing id error, got %v", err)
+			}
+		},
+	)
+	t.Run(
+		"Canceled Context", func(subT *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			before := requests
+			err := gkBoot.DoBatchRequestWithContext(
+				ctx, srv.URL, []gkBoot.BatchCall{
+					{Request: rpcCall("sum", 1, 1, 2), Response: new(RPCTestResponse)},
+				},
+			)
+			if !errors.Is(err, context.Canceled) {
+				subT.Fatalf("expected a canceled error, got %v", err)
+			}
+			if requests != before {
+				subT.Fatalf("expected the canceled batch not to be sent")
+			}
+		},
+	)
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yomiji/gkBoot"
+	"github.com/yomiji/gkBoot/request"
+)
+
+type BinaryTestRequest struct {
+	ID string `request:"path" json:"id"`
+}
+
+func (b BinaryTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "BinaryTest",
+		Method:      request.PUT,
+		Path:        "/blobs/{id}",
+		Description: "A test of sending and receiving raw bytes",
+	}
+}
+
+func TestDoRequestBinary(t *testing.T) {
+	var contentType, accept string
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				contentType = r.Header.Get("Content-Type")
+				accept = r.Header.Get("Accept")
+				if r.URL.Path == "/blobs/missing" {
+					w.WriteHeader(http.StatusNotFound)
+					_, _ = w.Write([]byte("no such blob"))
+					return
+				}
+				// echo the blob reversed, so the response is not merely the request
+				body, _ := io.ReadAll(r.Body)
+				for i, j := 0, len(body)-1; i < j; i, j = i+1, j-1 {
+					body[i], body[j] = body[j], body[i]
+				}
+				w.Header().Set("Content-Type", "application/octet-stream")
+				_, _ = w.Write(body)
+			},
+		),
+	)
+	defer srv.Close()
+
+	t.Run(
+		"Round Trip Arbitrary Bytes", func(subT *testing.T) {
+			blob := make([]byte, 4096)
+			for i := range blob {
+				blob[i] = byte(i * 7)
+			}
+
+			var received []byte
+			err := gkBoot.DoRequestBinary(context.Background(), srv.URL, BinaryTestRequest{ID: "a"}, blob, &received)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+
+			if contentType != "application/octet-stream" || accept != "application/octet-stream" {
+				subT.Fatalf("expected octet-stream Content-Type and Accept, got %q and %q", contentType, accept)
+			}
+
+			expected := bytes.Clone(blob)
+			for i, j := 0, len(expected)-1; i < j; i, j = i+1, j-1 {
+				expected[i], expected[j] = expected[j], expected[i]
+			}
+			if !bytes.Equal(received, expected) {
+				subT.Fatalf("received bytes do not match the reversed blob")
+			}
+		},
+	)
+
+	t.Run(
+		"Error Status", func(subT *testing.T) {
+			received := []byte("untouched")
+			err := gkBoot.DoRequestBinary(
+				context.Background(), srv.URL, BinaryTestRequest{ID: "missing"}, []byte{0x00}, &received,
+			)
+			if err == nil {
+				subT.Fatalf("expected an error for a 404")
+			}
+			if coder, ok := err.(interface{ StatusCode() int }); !ok || coder.StatusCode() != http.StatusNotFound {
+				subT.Fatalf("expected an error carrying status 404, got %v", err)
+			}
+			if string(received) != "untouched" {
+				subT.Fatalf("expected the response target to be untouched, got %q", received)
+			}
+		},
+	)
+}
@@ -0,0 +1,1158 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/yomiji/gkBoot"
+	"github.com/yomiji/gkBoot/request"
+	"github.com/yomiji/gkBoot/response"
+)
+
+type FileUploadTestRequest struct {
+	File *os.File `request:"form"`
+}
+
+func (f FileUploadTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "FileUploadTest",
+		Method:      request.PUT,
+		Path:        "/upload",
+		Description: "A test of streaming a file body",
+	}
+}
+
+func TestFileBody(t *testing.T) {
+	contents := bytes.Repeat([]byte("gkBoot file body "), 512)
+
+	file, err := os.CreateTemp(t.TempDir(), "upload-*.bin")
+	if err != nil {
+		t.Fatalf("unable to create temp file: %s", err)
+	}
+	defer file.Close()
+
+	if _, err = file.Write(contents); err != nil {
+		t.Fatalf("unable to write temp file: %s", err)
+	}
+
+	var receivedLength string
+	var received []byte
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				receivedLength = r.Header.Get("Content-Length")
+				received, _ = io.ReadAll(r.Body)
+				w.WriteHeader(http.StatusNoContent)
+			},
+		),
+	)
+	defer srv.Close()
+
+	t.Run(
+		"Content Length And Replay", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest(srv.URL, FileUploadTestRequest{File: file})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if r.ContentLength != int64(len(contents)) {
+				subT.Fatalf("expected content length %d, got %d", len(contents), r.ContentLength)
+			}
+
+			resp, err := http.DefaultClient.Do(r)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			resp.Body.Close()
+
+			if receivedLength != strconv.Itoa(len(contents)) {
+				subT.Fatalf("expected Content-Length header %d, got %s", len(contents), receivedLength)
+			}
+			if !bytes.Equal(received, contents) {
+				subT.Fatalf("uploaded body does not match the file contents")
+			}
+
+			// a retry must re-read the file from the start
+			replay, err := r.GetBody()
+			if err != nil {
+				subT.Fatalf("unable to replay body: %s", err)
+			}
+			replayed, _ := io.ReadAll(replay)
+			if !bytes.Equal(replayed, contents) {
+				subT.Fatalf("replayed body does not match the file contents")
+			}
+		},
+	)
+}
+
+func TestLargeFileContentLength(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping large file test in short mode")
+	}
+
+	file, err := os.CreateTemp(t.TempDir(), "large-*.bin")
+	if err != nil {
+		t.Fatalf("unable to create temp file: %s", err)
+	}
+	defer file.Close()
+
+	// a sparse file beyond the range of a 32-bit int, nothing is uploaded
+	var size int64 = 3 << 30
+	if err = file.Truncate(size); err != nil {
+		t.Skipf("unable to create sparse file: %s", err)
+	}
+
+	r, err := gkBoot.GenerateClientRequest("http://localhost:8080", FileUploadTestRequest{File: file})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if r.ContentLength != size {
+		t.Fatalf("expected content length %d, got %d", size, r.ContentLength)
+	}
+}
+
+func TestUploadProgress(t *testing.T) {
+	contents := bytes.Repeat([]byte("0123456789"), 100000)
+
+	file, err := os.CreateTemp(t.TempDir(), "progress-*.bin")
+	if err != nil {
+		t.Fatalf("unable to create temp file: %s", err)
+	}
+	defer file.Close()
+
+	if _, err = file.Write(contents); err != nil {
+		t.Fatalf("unable to write temp file: %s", err)
+	}
+
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				_, _ = io.Copy(io.Discard, r.Body)
+				w.WriteHeader(http.StatusNoContent)
+			},
+		),
+	)
+	defer srv.Close()
+
+	t.Run(
+		"Increasing Progress", func(subT *testing.T) {
+			var reports []int64
+			var reportedTotal int64
+
+			err := gkBoot.DoRequestNoResponse(
+				srv.URL, FileUploadTestRequest{File: file},
+				gkBoot.WithUploadProgress(
+					func(bytesSent, total int64) {
+						reports = append(reports, bytesSent)
+						reportedTotal = total
+					},
+				),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if len(reports) < 2 {
+				subT.Fatalf("expected several progress reports, got %d", len(reports))
+			}
+			for i := 1; i < len(reports); i++ {
+				if reports[i] <= reports[i-1] {
+					subT.Fatalf("expected increasing progress, got %v", reports)
+				}
+			}
+			if reports[len(reports)-1] != int64(len(contents)) || reportedTotal != int64(len(contents)) {
+				subT.Fatalf(
+					"expected final progress %d of %d, got %d of %d", len(contents), len(contents),
+					reports[len(reports)-1], reportedTotal,
+				)
+			}
+		},
+	)
+}
+
+type DownloadTestResponse struct {
+	Data []byte
+}
+
+func (d *DownloadTestResponse) Capture(reader io.Reader) (err error) {
+	d.Data, err = io.ReadAll(reader)
+	return err
+}
+
+func TestDownloadProgress(t *testing.T) {
+	contents := bytes.Repeat([]byte("abcdefghij"), 100000)
+
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Length", strconv.Itoa(len(contents)))
+				_, _ = w.Write(contents)
+			},
+		),
+	)
+	defer srv.Close()
+
+	t.Run(
+		"Increasing Progress", func(subT *testing.T) {
+			var reports []int64
+			var reportedTotal int64
+
+			resp := new(DownloadTestResponse)
+			err := gkBoot.DoRequestWithOptions(
+				srv.URL, OptionsTestRequest{}, resp,
+				gkBoot.WithDownloadProgress(
+					func(bytesRead, total int64) {
+						reports = append(reports, bytesRead)
+						reportedTotal = total
+					},
+				),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if !bytes.Equal(resp.Data, contents) {
+				subT.Fatalf("downloaded body does not match")
+			}
+			if len(reports) < 2 {
+				subT.Fatalf("expected several progress reports, got %d", len(reports))
+			}
+			for i := 1; i < len(reports); i++ {
+				if reports[i] <= reports[i-1] {
+					subT.Fatalf("expected increasing progress, got %v", reports)
+				}
+			}
+			if reports[len(reports)-1] != int64(len(contents)) || reportedTotal != int64(len(contents)) {
+				subT.Fatalf(
+					"expected final progress %d of %d, got %d of %d", len(contents), len(contents),
+					reports[len(reports)-1], reportedTotal,
+				)
+			}
+		},
+	)
+}
+
+type GzipTestBody struct {
+	Items []string `json:"items"`
+}
+
+type GzipTagTestRequest struct {
+	Body GzipTestBody `request:"form" compress:"gzip"`
+}
+
+func (g GzipTagTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "GzipTagTest",
+		Method:      request.PUT,
+		Path:        "/upload",
+		Description: "A test of compressing a json body by tag",
+	}
+}
+
+func TestGzipRequestBody(t *testing.T) {
+	body := GzipTestBody{Items: make([]string, 200)}
+	for i := range body.Items {
+		body.Items[i] = "repeated item " + strconv.Itoa(i%4)
+	}
+	plain, _ := json.Marshal(body)
+
+	var received []byte
+	var encoding string
+	var contentLength int64
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				encoding = r.Header.Get("Content-Encoding")
+				contentLength = r.ContentLength
+				reader, err := gzip.NewReader(r.Body)
+				if err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				received, _ = io.ReadAll(reader)
+				w.WriteHeader(http.StatusOK)
+			},
+		),
+	)
+	defer srv.Close()
+
+	assertCompressed := func(subT *testing.T, r *http.Request) {
+		if r.Header.Get("Content-Encoding") != "gzip" {
+			subT.Fatalf("expected gzip Content-Encoding, got '%s'", r.Header.Get("Content-Encoding"))
+		}
+		if r.ContentLength <= 0 || r.ContentLength >= int64(len(plain)) {
+			subT.Fatalf("expected a compressed Content-Length below %d, got %d", len(plain), r.ContentLength)
+		}
+		length := r.ContentLength
+
+		if err := gkBoot.DoGeneratedRequestWithOptions[any](r, nil); err != nil {
+			subT.Fatalf("unexpected error: %s", err)
+		}
+		if encoding != "gzip" || contentLength != length {
+			subT.Fatalf("server received encoding '%s' and length %d, wanted %d", encoding, contentLength, length)
+		}
+		if !bytes.Equal(received, plain) {
+			subT.Fatalf("decompressed body does not match the marshaled body")
+		}
+	}
+
+	t.Run(
+		"Compress Tag", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest(srv.URL, GzipTagTestRequest{Body: body})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			assertCompressed(subT, r)
+		},
+	)
+
+	t.Run(
+		"Compress Option", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequestWithOptions(
+				srv.URL, RetryTestRequest{Body: RetryTestBody{Name: strings.Repeat("widget ", 100)}},
+				gkBoot.WithGzipRequestBody(),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			plain, _ = json.Marshal(RetryTestBody{Name: strings.Repeat("widget ", 100)})
+			assertCompressed(subT, r)
+		},
+	)
+}
+
+func TestResponseDecompression(t *testing.T) {
+	plain := []byte(`{"value":"ok"}`)
+
+	encoded := map[string][]byte{}
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, _ = gz.Write(plain)
+	_ = gz.Close()
+	encoded["gzip"] = append([]byte(nil), buf.Bytes()...)
+
+	buf.Reset()
+	zw := zlib.NewWriter(&buf)
+	_, _ = zw.Write(plain)
+	_ = zw.Close()
+	encoded["deflate"] = append([]byte(nil), buf.Bytes()...)
+
+	buf.Reset()
+	fw, _ := flate.NewWriter(&buf, flate.DefaultCompression)
+	_, _ = fw.Write(plain)
+	_ = fw.Close()
+	encoded["raw-deflate"] = append([]byte(nil), buf.Bytes()...)
+
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				encoding := r.URL.Query().Get("encoding")
+				if encoding == "raw-deflate" {
+					w.Header().Set("Content-Encoding", "deflate")
+				} else {
+					w.Header().Set("Content-Encoding", encoding)
+				}
+				_, _ = w.Write(encoded[encoding])
+			},
+		),
+	)
+	defer srv.Close()
+
+	for _, encoding := range []string{"gzip", "deflate", "raw-deflate"} {
+		t.Run(
+			"Decodes "+encoding, func(subT *testing.T) {
+				resp := new(OptionsTestResponse)
+				err := gkBoot.DoRequestWithOptions(
+					srv.URL, OptionsTestRequest{}, resp,
+					gkBoot.WithQueryValues(url.Values{"encoding": {encoding}}),
+					gkBoot.WithHeader("Accept-Encoding", "gzip, deflate"),
+				)
+				if err != nil {
+					subT.Fatalf("unexpected error: %s", err)
+				}
+				if resp.Value != "ok" {
+					subT.Fatalf("expected decoded value 'ok', got '%s'", resp.Value)
+				}
+			},
+		)
+	}
+
+	t.Run(
+		"Capture Reader Receives Plain Body", func(subT *testing.T) {
+			resp := new(DownloadTestResponse)
+			err := gkBoot.DoRequestWithOptions(
+				srv.URL, OptionsTestRequest{}, resp,
+				gkBoot.WithQueryValues(url.Values{"encoding": {"gzip"}}),
+				gkBoot.WithHeader("Accept-Encoding", "gzip"),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if !bytes.Equal(resp.Data, plain) {
+				subT.Fatalf("expected the decompressed body, got %q", resp.Data)
+			}
+		},
+	)
+
+	t.Run(
+		"Transport Decompressed Once", func(subT *testing.T) {
+			resp := new(OptionsTestResponse)
+			err := gkBoot.DoRequestWithOptions(
+				srv.URL, OptionsTestRequest{}, resp, gkBoot.WithQueryValues(url.Values{"encoding": {"gzip"}}),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.Value != "ok" {
+				subT.Fatalf("expected decoded value 'ok', got '%s'", resp.Value)
+			}
+		},
+	)
+}
+
+func TestCompressedErrorBody(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, _ = gz.Write([]byte(`{"code":"invalid","message":"name is required"}`))
+	_ = gz.Close()
+	compressed := buf.Bytes()
+
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Encoding", "gzip")
+				if r.URL.Query().Get("plain") != "" {
+					// a proxy error page labeled gzip but sent as plain text
+					w.Header().Set("Content-Type", "text/plain")
+					w.WriteHeader(http.StatusBadGateway)
+					_, _ = w.Write([]byte("upstream unavailable"))
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write(compressed)
+			},
+		),
+	)
+	defer srv.Close()
+
+	// asking for gzip explicitly keeps the transport from decompressing the body itself
+	acceptGzip := gkBoot.WithHeader("Accept-Encoding", "gzip")
+
+	t.Run(
+		"Erred Response", func(subT *testing.T) {
+			resp := new(OptionsTestResponse)
+			err := gkBoot.DoRequestWithOptions(srv.URL, OptionsTestRequest{}, resp, acceptGzip)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if !strings.Contains(resp.Error(), "name is required") {
+				subT.Fatalf("expected the decompressed message in the error, got %q", resp.Error())
+			}
+		},
+	)
+
+	t.Run(
+		"No Response", func(subT *testing.T) {
+			err := gkBoot.DoRequestNoResponse(srv.URL, OptionsTestRequest{}, acceptGzip)
+			if err == nil || !strings.Contains(err.Error(), "name is required") {
+				subT.Fatalf("expected the decompressed message in the error, got %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Typed Error", func(subT *testing.T) {
+			err := gkBoot.DoRequestNoResponse(srv.URL, OptionsTestRequest{}, acceptGzip, gkBoot.WithErrorType[*APIError]())
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.Message != "name is required" {
+				subT.Fatalf("expected the decompressed body decoded as an *APIError, got %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Plain Body Labeled Gzip", func(subT *testing.T) {
+			err := gkBoot.DoRequestNoResponse(
+				srv.URL, OptionsTestRequest{}, acceptGzip, gkBoot.WithQueryValues(url.Values{"plain": {"1"}}),
+			)
+			if err == nil || !strings.Contains(err.Error(), "upstream unavailable") {
+				subT.Fatalf("expected the plain message in the error, got %v", err)
+			}
+		},
+	)
+}
+
+type PatchTestRequest struct {
+	ID      int    `request:"path!"`
+	Version string `request:"query" json:"version"`
+	Trace   string `request:"header" alias:"X-Trace"`
+	Changes map[string]interface{}
+}
+
+func (p PatchTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "PatchTest",
+		Method:      request.PATCH,
+		Path:        "/users/{ID}",
+		Description: "A test of a body provided independently of the request fields",
+	}
+}
+
+func (p PatchTestRequest) Body() interface{} {
+	if p.Changes == nil {
+		return nil
+	}
+	return p.Changes
+}
+
+func TestBodyProvider(t *testing.T) {
+	t.Run(
+		"Sends Partial Document", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest(
+				"http://localhost:8080",
+				PatchTestRequest{ID: 7, Version: "2", Trace: "abc", Changes: map[string]interface{}{"name": "new"}},
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if r.Method != http.MethodPatch || r.URL.Path != "/users/7" || r.URL.Query().Get("version") != "2" {
+				subT.Fatalf("unexpected request %s %s", r.Method, r.URL)
+			}
+			if r.Header.Get("X-Trace") != "abc" {
+				subT.Fatalf("expected X-Trace header 'abc', got '%s'", r.Header.Get("X-Trace"))
+			}
+			if r.Header.Get("Content-Type") != "application/json" {
+				subT.Fatalf("expected json content type, got '%s'", r.Header.Get("Content-Type"))
+			}
+			body, _ := io.ReadAll(r.Body)
+			if string(body) != `{"name":"new"}` {
+				subT.Fatalf("expected partial document body, got '%s'", body)
+			}
+		},
+	)
+
+	t.Run(
+		"Nil Document Sends No Body", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest("http://localhost:8080", PatchTestRequest{ID: 7})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if r.Body != nil && r.Body != http.NoBody {
+				subT.Fatalf("expected no body, got %v", r.Body)
+			}
+		},
+	)
+}
+
+func TestJSONBodyContentLength(t *testing.T) {
+	assertLength := func(subT *testing.T, r *http.Request, expected []byte) {
+		if r.ContentLength != int64(len(expected)) {
+			subT.Fatalf("expected content length %d, got %d", len(expected), r.ContentLength)
+		}
+		if r.GetBody == nil {
+			subT.Fatalf("expected a replayable body")
+		}
+
+		// the body must be re-readable for retries and redirects
+		for i := 0; i < 2; i++ {
+			replay, err := r.GetBody()
+			if err != nil {
+				subT.Fatalf("unable to replay body: %s", err)
+			}
+			replayed, _ := io.ReadAll(replay)
+			if !bytes.Equal(replayed, expected) {
+				subT.Fatalf("replayed body %q does not match %q", replayed, expected)
+			}
+		}
+	}
+
+	t.Run(
+		"Form Field", func(subT *testing.T) {
+			body := RetryTestBody{Name: "widget"}
+			expected, _ := json.Marshal(body)
+
+			r, err := gkBoot.GenerateClientRequest("http://localhost:8080", RetryTestRequest{Body: body})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			assertLength(subT, r, expected)
+		},
+	)
+
+	t.Run(
+		"JSONBody", func(subT *testing.T) {
+			req := NoResponseTestRequest{Command: "start"}
+			expected, _ := json.Marshal(req)
+
+			r, err := gkBoot.GenerateClientRequest("http://localhost:8080", req)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			assertLength(subT, r, expected)
+		},
+	)
+}
+
+func TestContentChecksum(t *testing.T) {
+	var received []byte
+	var contentMD5, contentSHA256 string
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				received, _ = io.ReadAll(r.Body)
+				contentMD5 = r.Header.Get("Content-MD5")
+				contentSHA256 = r.Header.Get("x-amz-content-sha256")
+				w.WriteHeader(http.StatusOK)
+			},
+		),
+	)
+	defer srv.Close()
+
+	t.Run(
+		"Content-MD5 Of JSON Body", func(subT *testing.T) {
+			err := gkBoot.DoRequestNoResponse(
+				srv.URL, RetryTestRequest{Body: RetryTestBody{Name: "widget"}},
+				gkBoot.WithContentChecksum(gkBoot.ChecksumMD5),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			sum := md5.Sum(received)
+			if len(received) == 0 || contentMD5 != base64.StdEncoding.EncodeToString(sum[:]) {
+				subT.Fatalf("Content-MD5 '%s' does not match the body %q", contentMD5, received)
+			}
+		},
+	)
+
+	t.Run(
+		"SHA-256 Of Compressed Body", func(subT *testing.T) {
+			err := gkBoot.DoRequestNoResponse(
+				srv.URL, RetryTestRequest{Body: RetryTestBody{Name: strings.Repeat("widget ", 50)}},
+				gkBoot.WithGzipRequestBody(), gkBoot.WithContentChecksum(gkBoot.ChecksumSHA256),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			sum := sha256.Sum256(received)
+			if contentSHA256 != hex.EncodeToString(sum[:]) {
+				subT.Fatalf("x-amz-content-sha256 '%s' does not match the sent body", contentSHA256)
+			}
+		},
+	)
+
+	t.Run(
+		"File Body Sent In Full", func(subT *testing.T) {
+			contents := bytes.Repeat([]byte("checksum "), 1000)
+			file, err := os.CreateTemp(subT.TempDir(), "checksum-*.bin")
+			if err != nil {
+				subT.Fatalf("unable to create temp file: %s", err)
+			}
+			defer file.Close()
+			if _, err = file.Write(contents); err != nil {
+				subT.Fatalf("unable to write temp file: %s", err)
+			}
+
+			err = gkBoot.DoRequestNoResponse(
+				srv.URL, FileUploadTestRequest{File: file}, gkBoot.WithContentChecksum(gkBoot.ChecksumSHA256),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			sum := sha256.Sum256(contents)
+			if !bytes.Equal(received, contents) || contentSHA256 != hex.EncodeToString(sum[:]) {
+				subT.Fatalf("expected the full file and its digest, got %d bytes", len(received))
+			}
+		},
+	)
+}
+
+func TestResponseWriter(t *testing.T) {
+	plain := bytes.Repeat([]byte("download "), 1024)
+
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	_, _ = gz.Write(plain)
+	_ = gz.Close()
+
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Query().Get("status") {
+				case "404":
+					w.WriteHeader(http.StatusNotFound)
+					_, _ = w.Write([]byte("missing"))
+					return
+				case "1":
+					w.Header().Set("Content-Encoding", "gzip")
+					_, _ = w.Write(compressed.Bytes())
+					return
+				case "2":
+					// stall after the first chunk until the client goes away
+					_, _ = w.Write(plain)
+					w.(http.Flusher).Flush()
+					<-r.Context().Done()
+					return
+				}
+				_, _ = w.Write(plain)
+			},
+		),
+	)
+	defer srv.Close()
+
+	t.Run(
+		"Into Buffer", func(subT *testing.T) {
+			var buf bytes.Buffer
+			var written int64
+			resp := new(OptionsTestResponse)
+			err := gkBoot.DoRequestWithOptions(
+				srv.URL, OptionsTestRequest{}, resp, gkBoot.WithResponseWriter(&buf, &written),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if !bytes.Equal(buf.Bytes(), plain) || written != int64(len(plain)) {
+				subT.Fatalf("expected %d bytes written, got %d of %d", len(plain), written, buf.Len())
+			}
+			if resp.Value != "" {
+				subT.Fatalf("expected the body not to be decoded, got '%s'", resp.Value)
+			}
+		},
+	)
+
+	t.Run(
+		"Into File Decompressed", func(subT *testing.T) {
+			file, err := os.CreateTemp(subT.TempDir(), "download")
+			if err != nil {
+				subT.Fatalf("unable to create file: %s", err)
+			}
+			defer file.Close()
+
+			var written int64
+			err = gkBoot.DoRequestWithOptions[OptionsTestRequest, OptionsTestResponse](
+				srv.URL, OptionsTestRequest{Status: 1}, nil,
+				gkBoot.WithResponseWriter(file, &written), gkBoot.WithHeader("Accept-Encoding", "gzip"),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+
+			content, _ := os.ReadFile(file.Name())
+			if !bytes.Equal(content, plain) || written != int64(len(plain)) {
+				subT.Fatalf("expected the decompressed body in the file, got %d bytes", len(content))
+			}
+		},
+	)
+
+	t.Run(
+		"Error Status Not Written", func(subT *testing.T) {
+			var buf bytes.Buffer
+			err := gkBoot.DoRequestWithOptions[OptionsTestRequest, OptionsTestResponse](
+				srv.URL, OptionsTestRequest{Status: 404}, nil, gkBoot.WithResponseWriter(&buf, nil),
+			)
+			if err == nil {
+				subT.Fatalf("expected the 404 to fail")
+			}
+			if buf.Len() != 0 {
+				subT.Fatalf("expected nothing written, got %s", buf.String())
+			}
+		},
+	)
+	t.Run(
+		"Cancelled Mid Copy", func(subT *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			writer := &cancellingWriter{cancel: cancel}
+			err := gkBoot.DoRequestWithContext[OptionsTestRequest, OptionsTestResponse](
+				ctx, srv.URL, OptionsTestRequest{Status: 2}, nil, gkBoot.WithResponseWriter(writer, nil),
+			)
+			if err == nil {
+				subT.Fatalf("expected the cancelled copy to fail")
+			}
+		},
+	)
+}
+
+// cancellingWriter cancels the request once the first bytes arrive
+type cancellingWriter struct {
+	cancel context.CancelFunc
+}
+
+func (c *cancellingWriter) Write(p []byte) (int, error) {
+	c.cancel()
+	return len(p), nil
+}
+
+type StreamToTestResponse struct {
+	response.Download
+	response.ErrorResponse
+}
+
+// limitedWriter fails once limit bytes have been written
+type limitedWriter struct {
+	limit   int
+	written int
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if l.written+len(p) > l.limit {
+		n := l.limit - l.written
+		l.written = l.limit
+		return n, errors.New("disk full")
+	}
+	l.written += len(p)
+	return len(p), nil
+}
+
+func TestStreamTo(t *testing.T) {
+	plain := bytes.Repeat([]byte("download "), 4096)
+
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("status") == "404" {
+					w.WriteHeader(http.StatusNotFound)
+					_, _ = w.Write([]byte(`{"message":"missing"}`))
+					return
+				}
+				_, _ = w.Write(plain)
+			},
+		),
+	)
+	defer srv.Close()
+
+	t.Run(
+		"Into File", func(subT *testing.T) {
+			file, err := os.CreateTemp(subT.TempDir(), "download")
+			if err != nil {
+				subT.Fatalf("unable to create file: %s", err)
+			}
+			defer file.Close()
+
+			resp := &StreamToTestResponse{Download: response.Download{Writer: file}}
+			err = gkBoot.DoRequest(srv.URL, OptionsTestRequest{}, resp)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.Written != int64(len(plain)) {
+				subT.Fatalf("expected %d bytes written, got %d", len(plain), resp.Written)
+			}
+
+			contents, _ := os.ReadFile(file.Name())
+			if !bytes.Equal(contents, plain) {
+				subT.Fatalf("expected the file to hold the body")
+			}
+		},
+	)
+
+	t.Run(
+		"Error Status Handled As Usual", func(subT *testing.T) {
+			var buf bytes.Buffer
+			resp := &StreamToTestResponse{Download: response.Download{Writer: &buf}}
+			err := gkBoot.DoRequest(srv.URL, OptionsTestRequest{Status: 404}, resp)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if buf.Len() != 0 || resp.Written != 0 {
+				subT.Fatalf("expected nothing streamed for a 404, got %d bytes", buf.Len())
+			}
+			if resp.StatusCode() != http.StatusNotFound || !strings.Contains(resp.Error(), "missing") {
+				subT.Fatalf("expected the 404 recorded as an error, got %d %q", resp.StatusCode(), resp.Error())
+			}
+		},
+	)
+
+	t.Run(
+		"Partial Copy", func(subT *testing.T) {
+			writer := &limitedWriter{limit: 1000}
+			resp := &StreamToTestResponse{Download: response.Download{Writer: writer}}
+			err := gkBoot.DoRequest(srv.URL, OptionsTestRequest{}, resp)
+			if err == nil || !strings.Contains(err.Error(), "disk full") {
+				subT.Fatalf("expected the error of the writer, got %v", err)
+			}
+			if resp.Written != 1000 {
+				subT.Fatalf("expected the partial 1000 bytes reported, got %d", resp.Written)
+			}
+		},
+	)
+}
+
+type RawBodyTestRequest struct {
+	ContentType string    `request:"header" alias:"Content-Type"`
+	Payload     io.Reader `request:"raw"`
+}
+
+func (r RawBodyTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "RawBodyTest",
+		Method:      request.PUT,
+		Path:        "/raw",
+		Description: "A test of sending a raw body",
+	}
+}
+
+type RawBytesTestRequest struct {
+	Payload []byte `request:"raw!"`
+}
+
+func (r RawBytesTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "RawBytesTest",
+		Method:      request.POST,
+		Path:        "/raw",
+		Description: "A test of sending raw bytes",
+	}
+}
+
+// oneShotReader hides the io.Seeker of the reader it wraps
+type oneShotReader struct {
+	io.Reader
+}
+
+func TestRawBody(t *testing.T) {
+	var receivedType string
+	var received []byte
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				receivedType = r.Header.Get("Content-Type")
+				received, _ = io.ReadAll(r.Body)
+				w.WriteHeader(http.StatusNoContent)
+			},
+		),
+	)
+	defer srv.Close()
+
+	send := func(subT *testing.T, r *http.Request) {
+		resp, err := http.DefaultClient.Do(r)
+		if err != nil {
+			subT.Fatalf("unexpected error: %s", err)
+		}
+		resp.Body.Close()
+	}
+
+	t.Run(
+		"Bytes Sent As Is", func(subT *testing.T) {
+			payload := []byte(`{"not":"marshaled"}`)
+
+			r, err := gkBoot.GenerateClientRequest(srv.URL, RawBytesTestRequest{Payload: payload})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if r.ContentLength != int64(len(payload)) {
+				subT.Fatalf("expected content length %d, got %d", len(payload), r.ContentLength)
+			}
+
+			send(subT, r)
+
+			if !bytes.Equal(received, payload) {
+				subT.Fatalf("expected body %q, got %q", payload, received)
+			}
+		},
+	)
+
+	t.Run(
+		"Missing Required Bytes", func(subT *testing.T) {
+			_, err := gkBoot.GenerateClientRequest(srv.URL, RawBytesTestRequest{})
+			if err == nil {
+				subT.Fatalf("expected an error for a missing required raw body")
+			}
+		},
+	)
+
+	t.Run(
+		"Content Type From Header Field", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest(
+				srv.URL, RawBodyTestRequest{ContentType: "text/csv", Payload: strings.NewReader("a,b\n1,2\n")},
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+
+			send(subT, r)
+
+			if receivedType != "text/csv" {
+				subT.Fatalf("expected Content-Type text/csv, got %q", receivedType)
+			}
+			if string(received) != "a,b\n1,2\n" {
+				subT.Fatalf("unexpected body %q", received)
+			}
+		},
+	)
+
+	t.Run(
+		"Seekable Reader Replayed From Its Offset", func(subT *testing.T) {
+			file, err := os.CreateTemp(subT.TempDir(), "raw-*.bin")
+			if err != nil {
+				subT.Fatalf("unable to create temp file: %s", err)
+			}
+			defer file.Close()
+
+			if _, err = file.WriteString("skipped|streamed"); err != nil {
+				subT.Fatalf("unable to write temp file: %s", err)
+			}
+			if _, err = file.Seek(int64(len("skipped|")), io.SeekStart); err != nil {
+				subT.Fatalf("unable to seek temp file: %s", err)
+			}
+
+			var reader io.Reader = file
+
+			r, err := gkBoot.GenerateClientRequest(srv.URL, RawBodyTestRequest{Payload: reader})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if r.ContentLength != int64(len("streamed")) {
+				subT.Fatalf("expected content length %d, got %d", len("streamed"), r.ContentLength)
+			}
+			if r.GetBody == nil {
+				subT.Fatalf("expected a seekable reader to be replayable")
+			}
+
+			send(subT, r)
+
+			if string(received) != "streamed" {
+				subT.Fatalf("expected body %q, got %q", "streamed", received)
+			}
+
+			replay, err := r.GetBody()
+			if err != nil {
+				subT.Fatalf("unable to replay body: %s", err)
+			}
+			replayed, _ := io.ReadAll(replay)
+			if string(replayed) != "streamed" {
+				subT.Fatalf("expected replayed body %q, got %q", "streamed", replayed)
+			}
+		},
+	)
+
+	t.Run(
+		"Unseekable Reader Streamed Once", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest(
+				srv.URL, RawBodyTestRequest{Payload: oneShotReader{strings.NewReader("streamed")}},
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if r.ContentLength != -1 || r.GetBody != nil {
+				subT.Fatalf("expected an unknown length without replay, got %d", r.ContentLength)
+			}
+
+			send(subT, r)
+
+			if string(received) != "streamed" {
+				subT.Fatalf("expected body %q, got %q", "streamed", received)
+			}
+		},
+	)
+}
+
+type FormFieldsTestRequest struct {
+	Username string            `request:"form" alias:"username"`
+	Scopes   []string          `request:"form" alias:"scope"`
+	Profile  map[string]string `request:"form" json:"profile"`
+}
+
+func (f FormFieldsTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "FormFieldsTest",
+		Method:      request.POST,
+		Path:        "/login",
+		Description: "A test of several form fields merged into one body",
+	}
+}
+
+type FormFieldsFileTestRequest struct {
+	Username string   `request:"form" alias:"username"`
+	File     *os.File `request:"form"`
+}
+
+func (f FormFieldsFileTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "FormFieldsFileTest",
+		Method:      request.POST,
+		Path:        "/login",
+		Description: "A test of a file among several form fields",
+	}
+}
+
+func TestFormFieldsBody(t *testing.T) {
+	var receivedType string
+	var received []byte
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				receivedType = r.Header.Get("Content-Type")
+				received, _ = io.ReadAll(r.Body)
+				w.WriteHeader(http.StatusNoContent)
+			},
+		),
+	)
+	defer srv.Close()
+
+	t.Run(
+		"Two Form Fields Merged", func(subT *testing.T) {
+			req := FormFieldsTestRequest{Username: "jo & co", Scopes: []string{"read", "write"}}
+
+			err := gkBoot.DoRequestNoResponse(srv.URL, req)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+
+			if receivedType != "application/x-www-form-urlencoded" {
+				subT.Fatalf("expected a urlencoded Content-Type, got %q", receivedType)
+			}
+			if expected := "scope=read&scope=write&username=jo+%26+co"; string(received) != expected {
+				subT.Fatalf("expected body %q, got %q", expected, received)
+			}
+		},
+	)
+
+	t.Run(
+		"Map Field Marshaled", func(subT *testing.T) {
+			req := FormFieldsTestRequest{Username: "jo", Profile: map[string]string{"theme": "dark"}}
+
+			r, err := gkBoot.GenerateClientRequest(srv.URL, req)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+
+			body, _ := io.ReadAll(r.Body)
+			if expected := "profile=%7B%22theme%22%3A%22dark%22%7D&username=jo"; string(body) != expected {
+				subT.Fatalf("expected body %q, got %q", expected, body)
+			}
+			if r.ContentLength != int64(len(body)) {
+				subT.Fatalf("expected content length %d, got %d", len(body), r.ContentLength)
+			}
+		},
+	)
+
+	t.Run(
+		"File Cannot Be Merged", func(subT *testing.T) {
+			file, err := os.CreateTemp(subT.TempDir(), "form-*.txt")
+			if err != nil {
+				subT.Fatalf("unable to create temp file: %s", err)
+			}
+			defer file.Close()
+
+			_, err = gkBoot.GenerateClientRequest(srv.URL, FormFieldsFileTestRequest{Username: "jo", File: file})
+			if err == nil || !strings.Contains(err.Error(), "cannot be merged into a form body") {
+				subT.Fatalf("expected a merge error, got %v", err)
+			}
+		},
+	)
+}
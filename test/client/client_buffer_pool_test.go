@@ -0,0 +1,174 @@
+package client
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/yomiji/gkBoot"
+)
+
+// bufferPoolTransport answers every request in memory so benchmarks measure the client alone
+type bufferPoolTransport struct {
+	body []byte
+}
+
+func (b bufferPoolTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode:    http.StatusOK,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(b.body)),
+		ContentLength: int64(len(b.body)),
+		Request:       r,
+	}, nil
+}
+
+type rawBodyPoolResponse struct {
+	body []byte
+}
+
+func (r *rawBodyPoolResponse) ReceiveBody(body []byte) error {
+	r.body = body
+	return nil
+}
+
+// retainedBodyError keeps the body it was decoded from
+type retainedBodyError struct {
+	raw []byte
+}
+
+func (r *retainedBodyError) Error() string {
+	return string(r.raw)
+}
+
+// bufferPoolErrorTransport answers every request with a 400 and the body
+type bufferPoolErrorTransport struct {
+	body []byte
+}
+
+func (b bufferPoolErrorTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode:    http.StatusBadRequest,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(b.body)),
+		ContentLength: int64(len(b.body)),
+		Request:       r,
+	}, nil
+}
+
+func newBufferPoolTestClient(body string) *http.Client {
+	return &http.Client{Transport: bufferPoolTransport{body: []byte(body)}}
+}
+
+func newBufferPoolErrorClient(body string) *http.Client {
+	return &http.Client{Transport: bufferPoolErrorTransport{body: []byte(body)}}
+}
+
+func TestBufferPool(t *testing.T) {
+	pool := gkBoot.NewBufferPool(64 * 1024)
+
+	t.Run(
+		"Decodes With Pooled Buffers", func(subT *testing.T) {
+			client := newBufferPoolTestClient(`{"value":"ok"}`)
+			for i := 0; i < 3; i++ {
+				resp := new(OptionsTestResponse)
+				err := gkBoot.DoRequestWithOptions(
+					"http://localhost:8080", OptionsTestRequest{}, resp,
+					gkBoot.WithHTTPClient(client), gkBoot.WithBufferPool(pool),
+				)
+				if err != nil {
+					subT.Fatalf("unexpected error: %s", err)
+				}
+				if resp.Value != "ok" {
+					subT.Fatalf("expected decoded value 'ok', got '%s'", resp.Value)
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"Raw Body Is Not Reused", func(subT *testing.T) {
+			first := new(rawBodyPoolResponse)
+			err := gkBoot.DoRequestWithOptions(
+				"http://localhost:8080", OptionsTestRequest{}, first,
+				gkBoot.WithHTTPClient(newBufferPoolTestClient(`{"value":"first"}`)), gkBoot.WithBufferPool(pool),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+
+			second := new(OptionsTestResponse)
+			err = gkBoot.DoRequestWithOptions(
+				"http://localhost:8080", OptionsTestRequest{}, second,
+				gkBoot.WithHTTPClient(newBufferPoolTestClient(`{"value":"other"}`)), gkBoot.WithBufferPool(pool),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+
+			if string(first.body) != `{"value":"first"}` {
+				subT.Fatalf("expected raw body to survive later requests, got '%s'", first.body)
+			}
+		},
+	)
+
+	t.Run(
+		"Error Decoder Body Is Not Reused", func(subT *testing.T) {
+			withRetainingDecoder := func(config *gkBoot.ClientConfig) {
+				config.ErrorDecoder = func(resp *http.Response, body []byte) (error, bool) {
+					return &retainedBodyError{raw: body}, true
+				}
+			}
+
+			first := gkBoot.DoRequestWithOptions(
+				"http://localhost:8080", OptionsTestRequest{}, new(OptionsTestResponse),
+				gkBoot.WithHTTPClient(newBufferPoolErrorClient("FIRST-ERROR-BODY")),
+				gkBoot.WithBufferPool(pool), withRetainingDecoder,
+			)
+
+			second := gkBoot.DoRequestWithOptions(
+				"http://localhost:8080", OptionsTestRequest{}, new(OptionsTestResponse),
+				gkBoot.WithHTTPClient(newBufferPoolErrorClient(`{"other":"x"}`)),
+				gkBoot.WithBufferPool(pool), withRetainingDecoder,
+			)
+
+			if first == nil || first.Error() != "FIRST-ERROR-BODY" {
+				subT.Fatalf("expected the decoded error to survive later requests, got '%v'", first)
+			}
+			if second == nil || second.Error() != `{"other":"x"}` {
+				subT.Fatalf("expected the second decoded error, got '%v'", second)
+			}
+		},
+	)
+}
+
+func BenchmarkBufferPool(b *testing.B) {
+	body := `{"value":"` + strings.Repeat("x", 32*1024) + `"}`
+	client := newBufferPoolTestClient(body)
+
+	run := func(b *testing.B, opts ...gkBoot.ClientOption) {
+		opts = append(opts, gkBoot.WithHTTPClient(client))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			resp := new(OptionsTestResponse)
+			err := gkBoot.DoRequestWithOptions("http://localhost:8080", OptionsTestRequest{}, resp, opts...)
+			if err != nil {
+				b.Fatalf("unexpected error: %s", err)
+			}
+		}
+	}
+
+	b.Run(
+		"Without Pool", func(subB *testing.B) {
+			run(subB)
+		},
+	)
+
+	b.Run(
+		"With Pool", func(subB *testing.B) {
+			run(subB, gkBoot.WithBufferPool(gkBoot.NewBufferPool(64*1024)))
+		},
+	)
+}
@@ -0,0 +1,202 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/yomiji/gkBoot"
+)
+
+func TestFluentCaller(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewEncoder(w).Encode(
+					map[string]string{
+						"value": r.Header.Get("X-Tenant") + "|" + r.Header.Get("X-Trace") + "|" + r.URL.RawQuery,
+					},
+				)
+			},
+		),
+	)
+	defer srv.Close()
+
+	base := gkBoot.Call[OptionsTestRequest, OptionsTestResponse](srv.URL).WithHeader("X-Tenant", "acme")
+
+	t.Run(
+		"Chained Options", func(subT *testing.T) {
+			var resp OptionsTestResponse
+			err := base.
+				WithHeader("X-Trace", "t1").
+				WithQueryValues(url.Values{"page": {"2"}}).
+				With(gkBoot.WithAccept("application/json")).
+				Do(OptionsTestRequest{Status: 200}, &resp)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.Value != "acme|t1|page=2&status=200" {
+				subT.Fatalf("unexpected echoed value '%s'", resp.Value)
+			}
+		},
+	)
+
+	t.Run(
+		"Base Is Reusable", func(subT *testing.T) {
+			var resp OptionsTestResponse
+			err := base.Do(OptionsTestRequest{Status: 200}, &resp)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.Value != "acme||status=200" {
+				subT.Fatalf("expected only the base options, got '%s'", resp.Value)
+			}
+		},
+	)
+}
+
+func TestClient(t *testing.T) {
+	var remoteAddrs sync.Map
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				remoteAddrs.Store(r.RemoteAddr, true)
+				_ = json.NewEncoder(w).Encode(
+					map[string]string{"value": r.Header.Get("X-Tenant") + "|" + r.URL.RawQuery},
+				)
+			},
+		),
+	)
+	defer srv.Close()
+
+	var sent atomic.Int32
+	counting := gkBoot.BeforeSend(
+		func(r *http.Request) error {
+			sent.Add(1)
+			return nil
+		},
+	)
+
+	client := gkBoot.NewClient(srv.URL, gkBoot.WithHeader("X-Tenant", "acme"), gkBoot.WithMiddleware(counting))
+
+	t.Run(
+		"Stored Options", func(subT *testing.T) {
+			sent.Store(0)
+
+			var resp OptionsTestResponse
+			err := client.Do(context.Background(), OptionsTestRequest{Status: 200}, &resp)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.Value != "acme|status=200" {
+				subT.Fatalf("unexpected echoed value '%s'", resp.Value)
+			}
+			if sent.Load() != 1 {
+				subT.Fatalf("expected the middleware to run once, ran %d times", sent.Load())
+			}
+			if client.BaseURL() != srv.URL {
+				subT.Fatalf("unexpected base url '%s'", client.BaseURL())
+			}
+		},
+	)
+
+	t.Run(
+		"Per Call Options", func(subT *testing.T) {
+			var resp OptionsTestResponse
+			err := client.Do(
+				context.Background(), OptionsTestRequest{Status: 200}, &resp,
+				gkBoot.WithQueryValues(url.Values{"page": {"2"}}),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.Value != "acme|page=2&status=200" {
+				subT.Fatalf("unexpected echoed value '%s'", resp.Value)
+			}
+		},
+	)
+
+	t.Run(
+		"Dedicated HTTP Client", func(subT *testing.T) {
+			if client.HTTPClient() == nil || client.HTTPClient() == http.DefaultClient {
+				subT.Fatalf("expected a dedicated http client")
+			}
+			if client.With(gkBoot.WithAccept("application/json")).HTTPClient() != client.HTTPClient() {
+				subT.Fatalf("expected the copy to share the http client")
+			}
+
+			given := &http.Client{}
+			if gkBoot.NewClient(srv.URL, gkBoot.WithHTTPClient(given)).HTTPClient() != given {
+				subT.Fatalf("expected the given http client to be used")
+			}
+		},
+	)
+
+	t.Run(
+		"Connections Are Reused", func(subT *testing.T) {
+			remoteAddrs.Range(
+				func(key, _ any) bool {
+					remoteAddrs.Delete(key)
+					return true
+				},
+			)
+
+			for i := 0; i < 5; i++ {
+				err := client.Do(context.Background(), OptionsTestRequest{Status: 200}, nil)
+				if err != nil {
+					subT.Fatalf("unexpected error: %s", err)
+				}
+			}
+
+			connections := 0
+			remoteAddrs.Range(
+				func(_, _ any) bool {
+					connections++
+					return true
+				},
+			)
+			if connections != 1 {
+				subT.Fatalf("expected a single pooled connection, got %d", connections)
+			}
+		},
+	)
+
+	t.Run(
+		"Concurrent Use", func(subT *testing.T) {
+			sent.Store(0)
+
+			var wg sync.WaitGroup
+			errs := make(chan error, 20)
+			for i := 0; i < 20; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					var resp OptionsTestResponse
+					err := client.Do(context.Background(), OptionsTestRequest{Status: 200}, &resp)
+					if err == nil && resp.Value != "acme|status=200" {
+						err = fmt.Errorf("unexpected echoed value '%s'", resp.Value)
+					}
+					errs <- err
+				}()
+			}
+			wg.Wait()
+			close(errs)
+
+			for err := range errs {
+				if err != nil {
+					subT.Fatalf("unexpected error: %s", err)
+				}
+			}
+			if sent.Load() != 20 {
+				subT.Fatalf("expected 20 requests, sent %d", sent.Load())
+			}
+		},
+	)
+}
@@ -0,0 +1,713 @@
+package client
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yomiji/gkBoot"
+	"github.com/yomiji/gkBoot/request"
+)
+
+type xmlTestDecoder struct{}
+
+func (x xmlTestDecoder) ContentType() string {
+	return "application/xml"
+}
+
+func (x xmlTestDecoder) Unmarshal(data []byte, v any) error {
+	return xml.Unmarshal(data, v)
+}
+
+func TestAcceptFromDecoders(t *testing.T) {
+	t.Run(
+		"Default JSON Accept", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest("http://localhost:8080", OptionsTestRequest{})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if r.Header.Get("Accept") != "application/json" {
+				subT.Fatalf("expected JSON Accept header, got '%s'", r.Header.Get("Accept"))
+			}
+		},
+	)
+
+	gkBoot.RegisterDecoder(xmlTestDecoder{})
+	defer gkBoot.DeregisterDecoder("application/xml")
+
+	t.Run(
+		"Weighted Multi Type Accept", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest("http://localhost:8080", OptionsTestRequest{})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if r.Header.Get("Accept") != "application/json, application/xml;q=0.9" {
+				subT.Fatalf("unexpected Accept header '%s'", r.Header.Get("Accept"))
+			}
+		},
+	)
+
+	t.Run(
+		"Per Request Override", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequestWithOptions(
+				"http://localhost:8080", OptionsTestRequest{}, gkBoot.WithAccept("application/xml"),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if r.Header.Get("Accept") != "application/xml" {
+				subT.Fatalf("expected overridden Accept header, got '%s'", r.Header.Get("Accept"))
+			}
+		},
+	)
+}
+
+type xmlTestCodec struct {
+	xmlTestDecoder
+}
+
+func (x xmlTestCodec) Marshal(v any) ([]byte, error) {
+	return xml.Marshal(v)
+}
+
+type CodecTestWidget struct {
+	XMLName xml.Name `xml:"widget"`
+	Name    string   `xml:"name"`
+}
+
+type CodecTestRequest struct {
+	Widget CodecTestWidget `request:"form"`
+}
+
+func (c CodecTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "CodecTest",
+		Method:      request.POST,
+		Path:        "/widgets",
+		Description: "A test of request body codecs",
+	}
+}
+
+func (c CodecTestRequest) BodyContentType() string {
+	return "text/xml"
+}
+
+func TestRegisteredCodec(t *testing.T) {
+	var contentType string
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				contentType = r.Header.Get("Content-Type")
+				w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+				_, _ = io.Copy(w, r.Body)
+			},
+		),
+	)
+	defer srv.Close()
+
+	t.Run(
+		"Unregistered Content Type", func(subT *testing.T) {
+			_, err := gkBoot.GenerateClientRequest(srv.URL, CodecTestRequest{})
+			if err == nil || !strings.Contains(err.Error(), "no codec registered for body content type 'text/xml'") {
+				subT.Fatalf("expected a missing codec error, got %v", err)
+			}
+		},
+	)
+
+	gkBoot.RegisterCodec("text/xml", xmlTestCodec{})
+	defer gkBoot.DeregisterDecoder("text/xml")
+
+	t.Run(
+		"Encodes And Decodes Body", func(subT *testing.T) {
+			resp := new(CodecTestWidget)
+			err := gkBoot.DoRequest(srv.URL, CodecTestRequest{Widget: CodecTestWidget{Name: "sprocket"}}, resp)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if contentType != "text/xml" {
+				subT.Fatalf("expected the codec content type, got '%s'", contentType)
+			}
+			if resp.Name != "sprocket" {
+				subT.Fatalf("expected the echoed widget to decode, got '%s'", resp.Name)
+			}
+		},
+	)
+}
+
+type MergePatchTestRequest struct {
+	gkBoot.JSONBody
+	ID   int    `request:"path!" json:"-"`
+	Name string `json:"name"`
+}
+
+func (m MergePatchTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "MergePatchTest",
+		Method:      request.PATCH,
+		Path:        "/widgets/{ID}",
+		Description: "A test of a +json body content type",
+	}
+}
+
+func (m MergePatchTestRequest) BodyContentType() string {
+	return "application/merge-patch+json"
+}
+
+type JSONPatchTestRequest struct {
+	ID    int              `request:"path!"`
+	Patch []map[string]any `request:"form" contentType:"application/json-patch+json"`
+}
+
+func (j JSONPatchTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "JSONPatchTest",
+		Method:      request.PATCH,
+		Path:        "/widgets/{ID}",
+		Description: "A test of a form body content type tag",
+	}
+}
+
+type ContentTypeHeaderTestRequest struct {
+	Body        map[string]string `request:"form"`
+	ContentType string            `request:"header" alias:"Content-Type"`
+}
+
+func (c ContentTypeHeaderTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "ContentTypeHeaderTest",
+		Method:      request.POST,
+		Path:        "/widgets",
+		Description: "A test of a Content-Type header field",
+	}
+}
+
+func TestBodyContentType(t *testing.T) {
+	t.Run(
+		"JSON Body Declared Type", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest("http://localhost:8080", MergePatchTestRequest{ID: 1, Name: "gear"})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if ct := r.Header.Values("Content-Type"); len(ct) != 1 || ct[0] != "application/merge-patch+json" {
+				subT.Fatalf("expected a single merge patch content type, got %v", ct)
+			}
+			body, _ := io.ReadAll(r.Body)
+			if string(body) != `{"name":"gear"}` {
+				subT.Fatalf("expected the body marshaled as JSON, got %s", body)
+			}
+		},
+	)
+
+	t.Run(
+		"Form Field Tag", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest(
+				"http://localhost:8080", JSONPatchTestRequest{
+					ID:    1,
+					Patch: []map[string]any{{"op": "replace", "path": "/name", "value": "gear"}},
+				},
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if ct := r.Header.Values("Content-Type"); len(ct) != 1 || ct[0] != "application/json-patch+json" {
+				subT.Fatalf("expected a single json patch content type, got %v", ct)
+			}
+			body, _ := io.ReadAll(r.Body)
+			if string(body) != `[{"op":"replace","path":"/name","value":"gear"}]` {
+				subT.Fatalf("expected the patch marshaled as JSON, got %s", body)
+			}
+		},
+	)
+
+	t.Run(
+		"Header Field Takes Precedence", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest(
+				"http://localhost:8080", ContentTypeHeaderTestRequest{
+					Body:        map[string]string{"name": "gear"},
+					ContentType: "application/vnd.widget+json",
+				},
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if ct := r.Header.Values("Content-Type"); len(ct) != 1 || ct[0] != "application/vnd.widget+json" {
+				subT.Fatalf("expected the single content type of the header field, got %v", ct)
+			}
+		},
+	)
+}
+
+type SniffTestWidget struct {
+	XMLName xml.Name `xml:"widget" json:"-"`
+	Name    string   `xml:"name" json:"name"`
+}
+
+func TestContentSniffing(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				// suppress the Content-Type otherwise detected by the server
+				w.Header()["Content-Type"] = nil
+				if r.URL.Query().Get("status") == "1" {
+					_, _ = w.Write([]byte("<widget><name>sprocket</name></widget>"))
+					return
+				}
+				_, _ = w.Write([]byte(`  {"name":"sprocket"}`))
+			},
+		),
+	)
+	defer srv.Close()
+
+	gkBoot.RegisterDecoder(xmlTestDecoder{})
+	defer gkBoot.DeregisterDecoder("application/xml")
+
+	t.Run(
+		"JSON Without Content Type", func(subT *testing.T) {
+			resp := new(SniffTestWidget)
+			err := gkBoot.DoRequestWithOptions(srv.URL, OptionsTestRequest{}, resp, gkBoot.WithContentSniffing())
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.Name != "sprocket" {
+				subT.Fatalf("expected sniffed JSON to decode, got '%s'", resp.Name)
+			}
+		},
+	)
+
+	t.Run(
+		"XML Without Content Type", func(subT *testing.T) {
+			resp := new(SniffTestWidget)
+			err := gkBoot.DoRequestWithOptions(
+				srv.URL, OptionsTestRequest{Status: 1}, resp, gkBoot.WithContentSniffing(),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.Name != "sprocket" {
+				subT.Fatalf("expected sniffed XML to decode, got '%s'", resp.Name)
+			}
+		},
+	)
+
+	t.Run(
+		"Sniffing Is Opt In", func(subT *testing.T) {
+			resp := new(SniffTestWidget)
+			err := gkBoot.DoRequest(srv.URL, OptionsTestRequest{Status: 1}, resp)
+			if err == nil {
+				subT.Fatalf("expected XML decoded as JSON to fail without sniffing")
+			}
+		},
+	)
+}
+
+type SelectedBodyTestRequest struct {
+	UseForm bool
+	Name    string
+}
+
+func (s SelectedBodyTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "SelectedBodyTest",
+		Method:      request.POST,
+		Path:        "/widgets",
+		Description: "A test of selecting the body encoding",
+	}
+}
+
+func (s SelectedBodyTestRequest) SelectBodyEncoding(accept string) (string, func() ([]byte, error)) {
+	if s.UseForm || strings.Contains(accept, "application/x-www-form-urlencoded") {
+		return "application/x-www-form-urlencoded", func() ([]byte, error) {
+			return []byte(url.Values{"name": {s.Name}}.Encode()), nil
+		}
+	}
+
+	return "application/json", func() ([]byte, error) {
+		return []byte(`{"name":"` + s.Name + `"}`), nil
+	}
+}
+
+func TestSelectBodyEncoding(t *testing.T) {
+	t.Run(
+		"JSON Selected", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest("http://localhost:8080", SelectedBodyTestRequest{Name: "gear"})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			body, _ := io.ReadAll(r.Body)
+			if r.Header.Get("Content-Type") != "application/json" || string(body) != `{"name":"gear"}` {
+				subT.Fatalf("unexpected json body %q of type '%s'", body, r.Header.Get("Content-Type"))
+			}
+		},
+	)
+
+	t.Run(
+		"Form Selected By Flag", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest(
+				"http://localhost:8080", SelectedBodyTestRequest{UseForm: true, Name: "gear"},
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			body, _ := io.ReadAll(r.Body)
+			if r.Header.Get("Content-Type") != "application/x-www-form-urlencoded" || string(body) != "name=gear" {
+				subT.Fatalf("unexpected form body %q of type '%s'", body, r.Header.Get("Content-Type"))
+			}
+			if r.ContentLength != int64(len(body)) {
+				subT.Fatalf("expected Content-Length %d, got %d", len(body), r.ContentLength)
+			}
+		},
+	)
+
+	t.Run(
+		"Form Selected By Accept", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequestWithOptions(
+				"http://localhost:8080", SelectedBodyTestRequest{Name: "gear"},
+				gkBoot.WithAccept("application/x-www-form-urlencoded"),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if r.Header.Get("Content-Type") != "application/x-www-form-urlencoded" {
+				subT.Fatalf("expected the form body for the Accept header, got '%s'", r.Header.Get("Content-Type"))
+			}
+		},
+	)
+}
+
+type StrictTestResponse struct {
+	Value string `json:"value"`
+}
+
+type StrictTestBody struct {
+	HTML string `json:"html"`
+}
+
+type StrictTestRequest struct {
+	Body StrictTestBody `request:"form"`
+}
+
+func (s StrictTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "StrictTest",
+		Method:      request.POST,
+		Path:        "/strict",
+		Description: "A test of configured JSON encoding and decoding",
+	}
+}
+
+func TestJSONFactories(t *testing.T) {
+	var received string
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				received = string(body)
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"value":"ok","count":12345678901234567890}`))
+			},
+		),
+	)
+	defer srv.Close()
+
+	t.Run(
+		"Lenient By Default", func(subT *testing.T) {
+			resp := new(StrictTestResponse)
+			if err := gkBoot.DoRequest(srv.URL, StrictTestRequest{}, resp); err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.Value != "ok" {
+				subT.Fatalf("expected decoded value 'ok', got '%s'", resp.Value)
+			}
+		},
+	)
+
+	t.Run(
+		"Strict Names Unknown Field", func(subT *testing.T) {
+			resp := new(StrictTestResponse)
+			err := gkBoot.DoRequestWithOptions(srv.URL, StrictTestRequest{}, resp, gkBoot.WithStrictJSON())
+			if err == nil || !strings.Contains(err.Error(), `unknown field "count"`) {
+				subT.Fatalf("expected an unknown field error naming count, got %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Decoder Using Numbers", func(subT *testing.T) {
+			resp := new(map[string]any)
+			err := gkBoot.DoRequestWithOptions(
+				srv.URL, StrictTestRequest{}, resp, gkBoot.WithJSONDecoder(
+					func(r io.Reader) *json.Decoder {
+						decoder := json.NewDecoder(r)
+						decoder.UseNumber()
+						return decoder
+					},
+				),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if number, ok := (*resp)["count"].(json.Number); !ok || number.String() != "12345678901234567890" {
+				subT.Fatalf("expected an exact json.Number, got %#v", (*resp)["count"])
+			}
+		},
+	)
+
+	t.Run(
+		"Encoder Without HTML Escaping", func(subT *testing.T) {
+			resp := new(StrictTestResponse)
+			err := gkBoot.DoRequestWithOptions(
+				srv.URL, StrictTestRequest{Body: StrictTestBody{HTML: "<b>&</b>"}}, resp, gkBoot.WithJSONEncoder(
+					func(w io.Writer) *json.Encoder {
+						encoder := json.NewEncoder(w)
+						encoder.SetEscapeHTML(false)
+						return encoder
+					},
+				),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if received != `{"html":"<b>&</b>"}` {
+				subT.Fatalf("expected an unescaped body, got %s", received)
+			}
+		},
+	)
+}
+
+type NameMapperTestItem struct {
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity,omitempty"`
+}
+
+type NameMapperTestRequest struct {
+	gkBoot.JSONBody
+	ID       int                           `request:"path!" json:"-"`
+	Customer string                        `json:"customer"`
+	Items    []NameMapperTestItem          `json:"items"`
+	Labels   map[string]NameMapperTestItem `json:"labels"`
+	Created  time.Time                     `json:"created"`
+}
+
+func (n NameMapperTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "NameMapperTest",
+		Method:      request.PUT,
+		Path:        "/orders/{ID}",
+		Description: "A test of renaming the fields of the body",
+	}
+}
+
+type NameMapperAddrTestRequest struct {
+	gkBoot.JSONBody
+	Name    string      `json:"name"`
+	Addr    netip.Addr  `json:"addr"`
+	Gateway *netip.Addr `json:"gateway"`
+}
+
+func (n NameMapperAddrTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "NameMapperAddrTest",
+		Method:      request.PUT,
+		Path:        "/hosts",
+		Description: "A test of renaming a body holding text marshalers",
+	}
+}
+
+type nameMapperAudit struct {
+	CreatedBy string `json:"createdBy"`
+}
+
+type NameMapperUnexportedTestRequest struct {
+	gkBoot.JSONBody
+	nameMapperAudit
+	Name string `json:"name"`
+}
+
+func (n NameMapperUnexportedTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "NameMapperUnexportedTest",
+		Method:      request.PUT,
+		Path:        "/audits",
+		Description: "A test of renaming a body embedding an unexported struct",
+	}
+}
+
+func TestBodyNameMapper(t *testing.T) {
+	capitalize := func(name string) string {
+		return strings.ToUpper(name[:1]) + name[1:]
+	}
+
+	req := NameMapperTestRequest{
+		ID:       1,
+		Customer: "acme",
+		Items:    []NameMapperTestItem{{SKU: "a1", Quantity: 2}, {SKU: "b2"}},
+		Labels:   map[string]NameMapperTestItem{"gift": {SKU: "c3", Quantity: 1}},
+		Created:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	t.Run(
+		"Renamed Body Keys", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequestWithOptions(
+				"http://localhost:8080", req, gkBoot.WithBodyNameMapper(capitalize),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			body, _ := io.ReadAll(r.Body)
+			expected := `{"Customer":"acme","Items":[{"Sku":"a1","Quantity":2},{"Sku":"b2"}],` +
+				`"Labels":{"gift":{"Sku":"c3","Quantity":1}},"Created":"2024-01-02T03:04:05Z"}`
+			if string(body) != expected {
+				subT.Fatalf("expected body %s, got %s", expected, body)
+			}
+			if r.URL.Path != "/orders/1" {
+				subT.Fatalf("expected the path field left out of the body, got path %s", r.URL.Path)
+			}
+		},
+	)
+
+	t.Run(
+		"Text Marshalers Left As They Are", func(subT *testing.T) {
+			gateway := netip.MustParseAddr("1.2.3.254")
+			addrReq := NameMapperAddrTestRequest{Name: "n", Addr: netip.MustParseAddr("1.2.3.4"), Gateway: &gateway}
+
+			r, err := gkBoot.GenerateClientRequestWithOptions(
+				"http://localhost:8080", addrReq, gkBoot.WithBodyNameMapper(strings.ToUpper),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			body, _ := io.ReadAll(r.Body)
+			if expected := `{"NAME":"n","ADDR":"1.2.3.4","GATEWAY":"1.2.3.254"}`; string(body) != expected {
+				subT.Fatalf("expected body %s, got %s", expected, body)
+			}
+		},
+	)
+
+	t.Run(
+		"Unexported Embedded Struct Promoted", func(subT *testing.T) {
+			auditReq := NameMapperUnexportedTestRequest{nameMapperAudit: nameMapperAudit{CreatedBy: "ops"}, Name: "n"}
+
+			r, err := gkBoot.GenerateClientRequestWithOptions(
+				"http://localhost:8080", auditReq, gkBoot.WithBodyNameMapper(strings.ToUpper),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			body, _ := io.ReadAll(r.Body)
+			if expected := `{"CREATEDBY":"ops","NAME":"n"}`; string(body) != expected {
+				subT.Fatalf("expected body %s, got %s", expected, body)
+			}
+		},
+	)
+
+	t.Run(
+		"Tagged Names Without Mapper", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest("http://localhost:8080", req)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			body, _ := io.ReadAll(r.Body)
+			if !strings.HasPrefix(string(body), `{"customer":"acme","items":[{"sku":"a1"`) {
+				subT.Fatalf("expected the tagged names, got %s", body)
+			}
+		},
+	)
+}
+
+type StatusDecoderTestResponse struct {
+	Value   string `json:"value"`
+	Failure string `json:"-"`
+}
+
+// failureTestDecoder
+//
+// decodes a plain-text error body into the Failure of the response
+type failureTestDecoder struct{}
+
+func (f failureTestDecoder) ContentType() string {
+	return "text/plain"
+}
+
+func (f failureTestDecoder) Unmarshal(data []byte, v any) error {
+	v.(*StatusDecoderTestResponse).Failure = string(data)
+	return nil
+}
+
+func TestStatusDecoder(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("status") == "500" {
+					w.Header().Set("Content-Type", "text/plain")
+					w.WriteHeader(http.StatusInternalServerError)
+					_, _ = w.Write([]byte("database unavailable"))
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"value":"ok"}`))
+			},
+		),
+	)
+	defer srv.Close()
+
+	textErrors := gkBoot.WithStatusDecoder(gkBoot.StatusErrors, failureTestDecoder{})
+
+	t.Run(
+		"500 Decoded As Text", func(subT *testing.T) {
+			resp := new(StatusDecoderTestResponse)
+			err := gkBoot.DoRequestWithOptions(srv.URL, OptionsTestRequest{Status: 500}, resp, textErrors)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.Failure != "database unavailable" || resp.Value != "" {
+				subT.Fatalf("expected the text error body, got %+v", resp)
+			}
+		},
+	)
+
+	t.Run(
+		"200 Decoded As JSON", func(subT *testing.T) {
+			resp := new(StatusDecoderTestResponse)
+			err := gkBoot.DoRequestWithOptions(srv.URL, OptionsTestRequest{Status: 200}, resp, textErrors)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.Value != "ok" || resp.Failure != "" {
+				subT.Fatalf("expected the json body, got %+v", resp)
+			}
+		},
+	)
+
+	t.Run(
+		"Without Status Decoder", func(subT *testing.T) {
+			resp := new(StatusDecoderTestResponse)
+			err := gkBoot.DoRequestWithOptions(srv.URL, OptionsTestRequest{Status: 500}, resp)
+			if err == nil {
+				subT.Fatalf("expected the text body to fail json decoding")
+			}
+		},
+	)
+
+	t.Run(
+		"Text Decoder Into String", func(subT *testing.T) {
+			var text string
+			err := gkBoot.DoRequestWithOptions(
+				srv.URL, OptionsTestRequest{Status: 500}, &text,
+				gkBoot.WithStatusDecoder(gkBoot.Status5xx, gkBoot.TextDecoder{}),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if text != "database unavailable" {
+				subT.Fatalf("expected the plain string error, got '%s'", text)
+			}
+		},
+	)
+}
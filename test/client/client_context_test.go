@@ -0,0 +1,425 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/yomiji/gkBoot"
+	"github.com/yomiji/gkBoot/request"
+)
+
+type contextTestKey struct{}
+
+type ContextRequesterTestRequest struct {
+	sawValue *bool
+}
+
+func (c ContextRequesterTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "ContextRequesterTest",
+		Method:      request.GET,
+		Path:        "/context",
+		Description: "A requester that reads the context",
+	}
+}
+
+func (c ContextRequesterTestRequest) Request(ctx context.Context) (*http.Request, error) {
+	*c.sawValue = ctx.Value(contextTestKey{}) == "present"
+	return http.NewRequest(http.MethodGet, "/", nil)
+}
+
+func TestDoRequestWithContext(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("status") == "1" {
+					select {
+					case <-r.Context().Done():
+					case <-time.After(5 * time.Second):
+					}
+					return
+				}
+				_, _ = w.Write([]byte(`{"value":"ok"}`))
+			},
+		),
+	)
+	defer srv.Close()
+
+	t.Run(
+		"Deadline Aborts Call", func(subT *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			resp := new(OptionsTestResponse)
+			err := gkBoot.DoRequestWithContext(ctx, srv.URL, OptionsTestRequest{Status: 1}, resp)
+			if !errors.Is(err, context.DeadlineExceeded) {
+				subT.Fatalf("expected deadline exceeded, got %v", err)
+			}
+			if time.Since(start) > 2*time.Second {
+				subT.Fatalf("expected the call to abort promptly")
+			}
+		},
+	)
+
+	t.Run(
+		"Cancelled Generated Request", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest(srv.URL, OptionsTestRequest{Status: 1})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(20*time.Millisecond, cancel)
+
+			resp := new(OptionsTestResponse)
+			err = gkBoot.DoGeneratedRequestWithContext(ctx, r, resp)
+			if !errors.Is(err, context.Canceled) {
+				subT.Fatalf("expected canceled, got %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Context Reaches Requester", func(subT *testing.T) {
+			saw := false
+			ctx := context.WithValue(context.Background(), contextTestKey{}, "present")
+
+			resp := new(OptionsTestResponse)
+			err := gkBoot.DoRequestWithContext(ctx, srv.URL, ContextRequesterTestRequest{sawValue: &saw}, resp)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if !saw {
+				subT.Fatalf("expected the requester to receive the context")
+			}
+			if resp.Value != "ok" {
+				subT.Fatalf("expected decoded value 'ok', got '%s'", resp.Value)
+			}
+		},
+	)
+}
+
+type countingTransport struct {
+	calls int
+}
+
+func (c *countingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	c.calls++
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestDoGeneratedRequestWithClient(t *testing.T) {
+	srv := newOptionsTestServer()
+	defer srv.Close()
+
+	t.Run(
+		"Uses Supplied Client", func(subT *testing.T) {
+			transport := new(countingTransport)
+			client := &http.Client{Transport: transport, Timeout: time.Second}
+
+			r, err := gkBoot.GenerateClientRequest(srv.URL, OptionsTestRequest{Status: 200})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+
+			resp := new(OptionsTestResponse)
+			err = gkBoot.DoGeneratedRequestWithClient(client, r, resp)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if transport.calls != 1 {
+				subT.Fatalf("expected the supplied transport to be used once, got %d", transport.calls)
+			}
+			if resp.Value != "ok" {
+				subT.Fatalf("expected decoded value 'ok', got '%s'", resp.Value)
+			}
+		},
+	)
+
+	t.Run(
+		"TLS Config Leaves Default Client", func(subT *testing.T) {
+			before := http.DefaultClient.Transport
+
+			r, err := gkBoot.GenerateClientRequest(srv.URL, OptionsTestRequest{Status: 200})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+
+			// the plain-text server cannot speak TLS, only the lack of global mutation matters here
+			_ = gkBoot.DoGeneratedRequest(r, new(OptionsTestResponse), &tls.Config{})
+
+			if http.DefaultClient.Transport != before {
+				subT.Fatalf("expected http.DefaultClient to be left untouched")
+			}
+		},
+	)
+}
+
+func TestWithTimeout(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("status") == "1" {
+					select {
+					case <-r.Context().Done():
+					case <-time.After(5 * time.Second):
+					}
+					return
+				}
+				_, _ = w.Write([]byte(`{"value":"ok"}`))
+			},
+		),
+	)
+	defer srv.Close()
+
+	t.Run(
+		"Timeout Aborts Call", func(subT *testing.T) {
+			start := time.Now()
+			err := gkBoot.DoRequestWithOptions(
+				srv.URL, OptionsTestRequest{Status: 1}, new(OptionsTestResponse), gkBoot.WithTimeout(50*time.Millisecond),
+			)
+			if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "timed out") {
+				subT.Fatalf("expected a timed out error, got %v", err)
+			}
+			if time.Since(start) > 2*time.Second {
+				subT.Fatalf("expected the call to abort promptly")
+			}
+		},
+	)
+
+	t.Run(
+		"Shorter Context Wins", func(subT *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			err := gkBoot.DoRequestWithContext(
+				ctx, srv.URL, OptionsTestRequest{Status: 1}, new(OptionsTestResponse), gkBoot.WithTimeout(time.Minute),
+			)
+			if !errors.Is(err, context.DeadlineExceeded) {
+				subT.Fatalf("expected deadline exceeded, got %v", err)
+			}
+			if time.Since(start) > 2*time.Second {
+				subT.Fatalf("expected the context deadline to abort the call")
+			}
+		},
+	)
+
+	t.Run(
+		"Canceled Is Not Timed Out", func(subT *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(20*time.Millisecond, cancel)
+
+			err := gkBoot.DoRequestWithContext(
+				ctx, srv.URL, OptionsTestRequest{Status: 1}, new(OptionsTestResponse), gkBoot.WithTimeout(time.Minute),
+			)
+			if !errors.Is(err, context.Canceled) || !strings.Contains(err.Error(), "canceled") {
+				subT.Fatalf("expected a canceled error, got %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Refused Connection Keeps Its Error", func(subT *testing.T) {
+			err := gkBoot.DoRequestWithOptions(
+				"http://127.0.0.1:1", OptionsTestRequest{Status: 200}, new(OptionsTestResponse),
+				gkBoot.WithTimeout(5*time.Second),
+			)
+			if !errors.Is(err, syscall.ECONNREFUSED) {
+				subT.Fatalf("expected a refused connection, got %v", err)
+			}
+			if errors.Is(err, context.Canceled) || strings.Contains(err.Error(), "canceled") {
+				subT.Fatalf("expected no canceled error, got %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Completes Within Timeout", func(subT *testing.T) {
+			resp := new(OptionsTestResponse)
+			err := gkBoot.DoRequestWithOptions(
+				srv.URL, OptionsTestRequest{Status: 200}, resp, gkBoot.WithTimeout(time.Second),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.Value != "ok" {
+				subT.Fatalf("expected decoded value 'ok', got '%s'", resp.Value)
+			}
+		},
+	)
+}
+
+func TestDeadlineHeader(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"value":"` + r.Header.Get("Grpc-Timeout") + `|` + r.Header.Get("X-Deadline") + `"}`))
+			},
+		),
+	)
+	defer srv.Close()
+
+	now := time.Now()
+	clock := func() time.Time { return now }
+
+	t.Run(
+		"Remaining Deadline", func(subT *testing.T) {
+			ctx, cancel := context.WithDeadline(context.Background(), now.Add(5*time.Second))
+			defer cancel()
+
+			resp := new(OptionsTestResponse)
+			err := gkBoot.DoRequestWithContext(
+				ctx, srv.URL, OptionsTestRequest{Status: 200}, resp, gkBoot.WithDeadlineHeader(""),
+				gkBoot.WithClock(clock),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.Value != "5S|" {
+				subT.Fatalf("expected a Grpc-Timeout of 5S, got '%s'", resp.Value)
+			}
+		},
+	)
+
+	t.Run(
+		"Shorter Timeout Wins", func(subT *testing.T) {
+			ctx, cancel := context.WithDeadline(context.Background(), now.Add(time.Minute))
+			defer cancel()
+
+			resp := new(OptionsTestResponse)
+			err := gkBoot.DoRequestWithContext(
+				ctx, srv.URL, OptionsTestRequest{Status: 200}, resp, gkBoot.WithDeadlineHeader("X-Deadline"),
+				gkBoot.WithTimeout(100*time.Millisecond), gkBoot.WithClock(clock),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.Value != "|100m" {
+				subT.Fatalf("expected an X-Deadline of 100m, got '%s'", resp.Value)
+			}
+		},
+	)
+
+	t.Run(
+		"No Deadline", func(subT *testing.T) {
+			resp := new(OptionsTestResponse)
+			err := gkBoot.DoRequestWithContext(
+				context.Background(), srv.URL, OptionsTestRequest{Status: 200}, resp, gkBoot.WithDeadlineHeader(""),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.Value != "|" {
+				subT.Fatalf("expected no timeout header, got '%s'", resp.Value)
+			}
+		},
+	)
+
+	t.Run(
+		"Formats", func(subT *testing.T) {
+			cases := map[time.Duration]string{
+				0:                                  "0H",
+				-time.Second:                       "0H",
+				1500 * time.Millisecond:            "1500m",
+				2 * time.Hour:                      "2H",
+				90 * time.Minute:                   "90M",
+				time.Second + time.Nanosecond:      "1000001u",
+				200*24*time.Hour + time.Nanosecond: "17280001S",
+			}
+			for d, expected := range cases {
+				if formatted := gkBoot.FormatGrpcTimeout(d); formatted != expected {
+					subT.Fatalf("expected %s to format as %s, got %s", d, expected, formatted)
+				}
+			}
+		},
+	)
+}
+
+type TokenRequesterTestRequest struct{}
+
+func (t TokenRequesterTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "TokenRequesterTest",
+		Method:      request.GET,
+		Path:        "/token",
+		Description: "A requester that fetches a token before building the request",
+	}
+}
+
+func (t TokenRequesterTestRequest) Request(ctx context.Context) (*http.Request, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	r, err := http.NewRequest(http.MethodGet, "/", nil)
+	if err != nil {
+		return nil, err
+	}
+	r.Header.Set("Authorization", "Bearer fetched")
+
+	return r, nil
+}
+
+func TestGenerateClientRequestContext(t *testing.T) {
+	t.Run(
+		"Requester Receives Context", func(subT *testing.T) {
+			var saw bool
+			ctx := context.WithValue(context.Background(), contextTestKey{}, "present")
+
+			r, err := gkBoot.GenerateClientRequestContext(
+				ctx, "http://localhost:8080", ContextRequesterTestRequest{sawValue: &saw},
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if !saw {
+				subT.Fatalf("expected the requester to receive the context")
+			}
+			if r.Context().Value(contextTestKey{}) != "present" {
+				subT.Fatalf("expected the context attached to the request")
+			}
+		},
+	)
+
+	t.Run(
+		"Cancellation Honored", func(subT *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			_, err := gkBoot.GenerateClientRequestContext(ctx, "http://localhost:8080", TokenRequesterTestRequest{})
+			if !errors.Is(err, context.Canceled) {
+				subT.Fatalf("expected the canceled context to abort the requester, got %v", err)
+			}
+
+			r, err := gkBoot.GenerateClientRequest("http://localhost:8080", TokenRequesterTestRequest{})
+			if err != nil || r.Header.Get("Authorization") != "Bearer fetched" {
+				subT.Fatalf("expected the background context to complete, got %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Struct Request", func(subT *testing.T) {
+			ctx := context.WithValue(context.Background(), contextTestKey{}, "present")
+
+			r, err := gkBoot.GenerateClientRequestContext(ctx, "http://localhost:8080", OptionsTestRequest{Status: 200})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if r.Context().Value(contextTestKey{}) != "present" {
+				subT.Fatalf("expected the context attached to the request")
+			}
+		},
+	)
+}
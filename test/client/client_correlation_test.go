@@ -0,0 +1,84 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/yomiji/gkBoot"
+	"github.com/yomiji/gkBoot/response"
+)
+
+type CorrelationTestResponse struct {
+	Value string `json:"value"`
+	response.Correlated
+}
+
+func TestCorrelationID(t *testing.T) {
+	var received string
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				header := r.URL.Query().Get("header")
+				if header == "" {
+					header = gkBoot.DefaultCorrelationHeader
+				}
+				received = r.Header.Get(header)
+				w.Header().Set(header, received)
+				_, _ = w.Write([]byte(`{"value":"ok"}`))
+			},
+		),
+	)
+	defer srv.Close()
+
+	t.Run(
+		"Given ID Round Trips", func(subT *testing.T) {
+			resp := new(CorrelationTestResponse)
+			err := gkBoot.DoRequestWithOptions(
+				srv.URL, OptionsTestRequest{}, resp, gkBoot.WithCorrelationID("abc-123"),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if received != "abc-123" || resp.CorrelationID() != "abc-123" {
+				subT.Fatalf("expected abc-123 to round trip, sent '%s' and read '%s'", received, resp.CorrelationID())
+			}
+		},
+	)
+
+	t.Run(
+		"Generated ID", func(subT *testing.T) {
+			resp := new(CorrelationTestResponse)
+			err := gkBoot.DoRequestWithOptions(srv.URL, OptionsTestRequest{}, resp, gkBoot.WithCorrelationID(""))
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+			if !uuid.MatchString(received) {
+				subT.Fatalf("expected a generated UUID, got '%s'", received)
+			}
+			if resp.CorrelationID() != received {
+				subT.Fatalf("expected the echoed ID '%s', got '%s'", received, resp.CorrelationID())
+			}
+		},
+	)
+
+	t.Run(
+		"Custom Header", func(subT *testing.T) {
+			resp := new(CorrelationTestResponse)
+			err := gkBoot.DoRequestWithOptions(
+				srv.URL, OptionsTestRequest{}, resp,
+				gkBoot.WithCorrelationID("xyz"),
+				gkBoot.WithCorrelationHeader("X-Request-ID"),
+				gkBoot.WithQueryValues(map[string][]string{"header": {"X-Request-ID"}}),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if received != "xyz" || resp.CorrelationID() != "xyz" {
+				subT.Fatalf("expected xyz in X-Request-ID, sent '%s' and read '%s'", received, resp.CorrelationID())
+			}
+		},
+	)
+}
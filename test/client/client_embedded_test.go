@@ -0,0 +1,198 @@
+package client
+
+import (
+	"io"
+	"testing"
+
+	"github.com/yomiji/gkBoot"
+	"github.com/yomiji/gkBoot/request"
+)
+
+type CommonQuery struct {
+	Tenant string `request:"query" alias:"tenant"`
+}
+
+type CommonHeaders struct {
+	Token     string `request:"header" alias:"X-Token"`
+	RequestID string `request:"header" alias:"X-Request-ID"`
+}
+
+type EmbeddedTestRequest struct {
+	CommonQuery
+	*CommonHeaders
+	Name string `request:"query" alias:"name"`
+}
+
+func (e EmbeddedTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "EmbeddedTest",
+		Method:      request.GET,
+		Path:        "/embedded",
+		Description: "A test of embedded request composition",
+	}
+}
+
+func TestEmbeddedRequests(t *testing.T) {
+	t.Run(
+		"Value And Pointer Embeds", func(subT *testing.T) {
+			req := EmbeddedTestRequest{
+				CommonQuery:   CommonQuery{Tenant: "acme"},
+				CommonHeaders: &CommonHeaders{Token: "secret", RequestID: "42"},
+				Name:          "n",
+			}
+
+			for _, serviceRequest := range []request.HttpRequest{req, &req} {
+				r, err := gkBoot.GenerateClientRequest("http://localhost:8080", serviceRequest)
+				if err != nil {
+					subT.Fatalf("unexpected error: %s", err)
+				}
+				if r.URL.Query().Get("tenant") != "acme" || r.URL.Query().Get("name") != "n" {
+					subT.Fatalf("expected the embedded and own query values, got %s", r.URL.RawQuery)
+				}
+				if r.Header.Get("X-Token") != "secret" || r.Header.Get("X-Request-ID") != "42" {
+					subT.Fatalf("expected the headers of the embedded pointer, got %v", r.Header)
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"Nil Pointer Embed", func(subT *testing.T) {
+			req := EmbeddedTestRequest{Name: "n"}
+
+			for _, serviceRequest := range []request.HttpRequest{req, &req} {
+				r, err := gkBoot.GenerateClientRequest("http://localhost:8080", serviceRequest)
+				if err != nil {
+					subT.Fatalf("unexpected error: %s", err)
+				}
+				if _, ok := r.Header["X-Token"]; ok {
+					subT.Fatalf("expected no headers of the nil embedded pointer, got %v", r.Header)
+				}
+				if r.URL.Query().Get("name") != "n" {
+					subT.Fatalf("expected the fields following the nil embed, got %s", r.URL.RawQuery)
+				}
+			}
+		},
+	)
+}
+
+type BodyAudit struct {
+	CreatedBy string `json:"createdBy"`
+}
+
+type BodyAddress struct {
+	City string `json:"city"`
+	Zip  string `json:"zip,omitempty"`
+}
+
+type NestedBodyTestRequest struct {
+	gkBoot.JSONBody
+	BodyAudit `embed:"nest"`
+	ID        int    `request:"path" json:"-"`
+	Name      string `json:"name"`
+}
+
+func (n NestedBodyTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "NestedBodyTest",
+		Method:      request.POST,
+		Path:        "/embedded/{ID}",
+		Description: "A test of nesting an embedded struct in the body",
+	}
+}
+
+type FlattenedBodyTestRequest struct {
+	gkBoot.JSONBody
+	BodyAudit `json:"audit" embed:"flatten"`
+	Address   *BodyAddress `json:"address" embed:"flatten"`
+	Name      string       `json:"name"`
+	City      string       `json:"city"`
+}
+
+func (f FlattenedBodyTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "FlattenedBodyTest",
+		Method:      request.POST,
+		Path:        "/embedded",
+		Description: "A test of flattening struct fields into the body",
+	}
+}
+
+type bodyOwner struct {
+	Owner string `json:"owner"`
+}
+
+type UnexportedEmbedBodyTestRequest struct {
+	gkBoot.JSONBody
+	bodyOwner
+	BodyAudit `embed:"nest"`
+	Name      string `json:"name"`
+}
+
+func (u UnexportedEmbedBodyTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "UnexportedEmbedBodyTest",
+		Method:      request.POST,
+		Path:        "/embedded",
+		Description: "A test of promoting an embedded struct of unexported type in a shaped body",
+	}
+}
+
+func TestEmbeddedBodyShape(t *testing.T) {
+	readBody := func(subT *testing.T, serviceRequest request.HttpRequest) string {
+		r, err := gkBoot.GenerateClientRequest("http://localhost:8080", serviceRequest)
+		if err != nil {
+			subT.Fatalf("unexpected error: %s", err)
+		}
+		body, _ := io.ReadAll(r.Body)
+		return string(body)
+	}
+
+	t.Run(
+		"Nest", func(subT *testing.T) {
+			body := readBody(
+				subT, NestedBodyTestRequest{BodyAudit: BodyAudit{CreatedBy: "alice"}, ID: 1, Name: "n"},
+			)
+			if body != `{"BodyAudit":{"createdBy":"alice"},"name":"n"}` {
+				subT.Fatalf("unexpected body %s", body)
+			}
+		},
+	)
+
+	t.Run(
+		"Flatten", func(subT *testing.T) {
+			body := readBody(
+				subT, &FlattenedBodyTestRequest{
+					BodyAudit: BodyAudit{CreatedBy: "alice"},
+					Address:   &BodyAddress{City: "Springfield"},
+					Name:      "n",
+					City:      "Shelbyville",
+				},
+			)
+			if body != `{"createdBy":"alice","name":"n","city":"Shelbyville"}` {
+				subT.Fatalf("unexpected body %s", body)
+			}
+		},
+	)
+
+	t.Run(
+		"Flatten Nil Pointer", func(subT *testing.T) {
+			body := readBody(subT, FlattenedBodyTestRequest{Name: "n"})
+			if body != `{"createdBy":"","name":"n","city":""}` {
+				subT.Fatalf("unexpected body %s", body)
+			}
+		},
+	)
+	t.Run(
+		"Unexported Embedded Struct Promoted", func(subT *testing.T) {
+			body := readBody(
+				subT, UnexportedEmbedBodyTestRequest{
+					bodyOwner: bodyOwner{Owner: "bob"}, BodyAudit: BodyAudit{CreatedBy: "alice"}, Name: "n",
+				},
+			)
+			if body != `{"owner":"bob","BodyAudit":{"createdBy":"alice"},"name":"n"}` {
+				subT.Fatalf("unexpected body %s", body)
+			}
+		},
+	)
+}
@@ -0,0 +1,160 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/yomiji/gkBoot"
+	"github.com/yomiji/gkBoot/request"
+)
+
+type JSONSeqTestRequest struct {
+	Mode string `request:"query" json:"mode"`
+}
+
+func (j JSONSeqTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "JSONSeqTest",
+		Method:      request.GET,
+		Path:        "/events",
+		Description: "A test of JSON text sequences",
+	}
+}
+
+type JSONSeqTestEvent struct {
+	Seq     int    `json:"seq"`
+	Message string `json:"message"`
+}
+
+func TestDoRequestJSONSeq(t *testing.T) {
+	var accept string
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				accept = r.Header.Get("Accept")
+				w.Header().Set("Content-Type", "application/json-seq")
+				switch r.URL.Query().Get("mode") {
+				case "endless":
+					for i := 0; ; i++ {
+						if _, err := w.Write([]byte("\x1e{\"seq\":1}\n")); err != nil {
+							return
+						}
+						w.(http.Flusher).Flush()
+						select {
+						case <-r.Context().Done():
+							return
+						case <-time.After(10 * time.Millisecond):
+						}
+					}
+				case "fail":
+					w.WriteHeader(http.StatusServiceUnavailable)
+				default:
+					_, _ = w.Write(
+						[]byte(
+							"\x1e{\"seq\":1,\"message\":\"first\"}\n" +
+								"\x1e{\"seq\":2,\n\"message\":\"multi line\"}\n" +
+								"\x1e{\"seq\":3,\"message\":\n" +
+								"\x1e{\"seq\":4,\"message\":\"after truncated\"}\n",
+						),
+					)
+				}
+			},
+		),
+	)
+	defer srv.Close()
+
+	t.Run(
+		"Decodes Each Record", func(subT *testing.T) {
+			records, err := gkBoot.DoRequestJSONSeq[JSONSeqTestRequest, JSONSeqTestEvent](
+				context.Background(), srv.URL, JSONSeqTestRequest{},
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+
+			var events []JSONSeqTestEvent
+			var failed int
+			for record := range records {
+				if record.Err != nil {
+					failed++
+					continue
+				}
+				events = append(events, record.Value)
+			}
+
+			if accept != "application/json-seq" {
+				subT.Fatalf("expected Accept application/json-seq, got '%s'", accept)
+			}
+			if failed != 1 {
+				subT.Fatalf("expected the truncated record to fail alone, got %d failures", failed)
+			}
+			if len(events) != 3 || events[0].Message != "first" || events[1].Message != "multi line" ||
+				events[2].Seq != 4 {
+				subT.Fatalf("unexpected events %+v", events)
+			}
+		},
+	)
+
+	t.Run(
+		"Cancellation Closes Channel", func(subT *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			records, err := gkBoot.DoRequestJSONSeq[JSONSeqTestRequest, JSONSeqTestEvent](
+				ctx, srv.URL, JSONSeqTestRequest{Mode: "endless"},
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+
+			first := <-records
+			if first.Err != nil || first.Value.Seq != 1 {
+				subT.Fatalf("unexpected first record %+v", first)
+			}
+
+			cancel()
+
+			done := make(chan struct{})
+			go func() {
+				for range records {
+				}
+				close(done)
+			}()
+
+			select {
+			case <-done:
+			case <-time.After(2 * time.Second):
+				subT.Fatalf("expected the channel to close after cancellation")
+			}
+		},
+	)
+
+	t.Run(
+		"Non 2xx Fails", func(subT *testing.T) {
+			_, err := gkBoot.DoRequestJSONSeq[JSONSeqTestRequest, JSONSeqTestEvent](
+				context.Background(), srv.URL, JSONSeqTestRequest{Mode: "fail"},
+			)
+			if err == nil {
+				subT.Fatalf("expected an error for a 503")
+			}
+		},
+	)
+
+	t.Run(
+		"Status Error Applied", func(subT *testing.T) {
+			unavailable := errors.New("unavailable")
+			_, err := gkBoot.DoRequestJSONSeq[JSONSeqTestRequest, JSONSeqTestEvent](
+				context.Background(), srv.URL, JSONSeqTestRequest{Mode: "fail"},
+				gkBoot.WithStatusError(gkBoot.StatusRange{From: 503, To: 503}, unavailable),
+			)
+			var statusErr *gkBoot.StatusError
+			if !errors.Is(err, unavailable) || !errors.As(err, &statusErr) || statusErr.StatusCode != 503 {
+				subT.Fatalf("expected the mapped status error, got %v", err)
+			}
+		},
+	)
+}
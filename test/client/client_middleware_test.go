@@ -0,0 +1,134 @@
+package client
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/yomiji/gkBoot"
+)
+
+var middlewareTestKey = []byte("secret")
+
+// signRequest
+//
+// an HMAC of the method, the final url and the body of the request
+func signRequest(method, requestURL string, body []byte) string {
+	mac := hmac.New(sha256.New, middlewareTestKey)
+	mac.Write([]byte(method + "\n" + requestURL + "\n"))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestDoRequestWithMiddleware(t *testing.T) {
+	var signatureValid bool
+	var trace string
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				expected := signRequest(r.Method, "http://"+r.Host+r.URL.RequestURI(), body)
+				signatureValid = r.Header.Get("X-Signature") == expected
+				trace = r.Header.Get("X-Trace")
+				_, _ = w.Write([]byte(`{"value":"ok"}`))
+			},
+		),
+	)
+	defer srv.Close()
+
+	sign := gkBoot.BeforeSend(
+		func(r *http.Request) error {
+			var body []byte
+			if r.GetBody != nil {
+				replay, err := r.GetBody()
+				if err != nil {
+					return err
+				}
+				body, _ = io.ReadAll(replay)
+			}
+			r.Header.Set("X-Signature", signRequest(r.Method, r.URL.String(), body))
+			return nil
+		},
+	)
+
+	t.Run(
+		"Signs Final Request", func(subT *testing.T) {
+			resp := new(OptionsTestResponse)
+			err := gkBoot.DoRequestWithMiddleware(
+				srv.URL, RetryTestRequest{Body: RetryTestBody{Name: "widget"}}, resp, []gkBoot.Middleware{sign},
+				gkBoot.WithQueryValues(map[string][]string{"added": {"after fields"}}),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if !signatureValid {
+				subT.Fatalf("expected the signature to cover the final request")
+			}
+			if resp.Value != "ok" {
+				subT.Fatalf("expected decoded value 'ok', got '%s'", resp.Value)
+			}
+		},
+	)
+
+	t.Run(
+		"Runs In Order", func(subT *testing.T) {
+			var order []string
+			record := func(name string) gkBoot.Middleware {
+				return func(next gkBoot.RoundTripFunc) gkBoot.RoundTripFunc {
+					return func(r *http.Request) (*http.Response, error) {
+						order = append(order, name+" before")
+						r.Header.Add("X-Trace", name)
+						resp, err := next(r)
+						order = append(order, name+" after")
+						return resp, err
+					}
+				}
+			}
+
+			err := gkBoot.DoRequestWithMiddleware(
+				srv.URL, OptionsTestRequest{Status: 200}, new(OptionsTestResponse),
+				[]gkBoot.Middleware{record("outer"), record("inner")},
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if strings.Join(order, ",") != "outer before,inner before,inner after,outer after" {
+				subT.Fatalf("unexpected middleware order %v", order)
+			}
+			if trace != "outer" {
+				subT.Fatalf("expected the first trace header from the outer middleware, got '%s'", trace)
+			}
+		},
+	)
+
+	t.Run(
+		"Error Aborts Request", func(subT *testing.T) {
+			errRefused := errors.New("refused by middleware")
+			trace = ""
+
+			err := gkBoot.DoRequestWithOptions(
+				srv.URL, OptionsTestRequest{Status: 200}, new(OptionsTestResponse),
+				gkBoot.WithMiddleware(
+					gkBoot.BeforeSend(
+						func(r *http.Request) error {
+							r.Header.Set("X-Trace", "sent")
+							return errRefused
+						},
+					),
+				),
+			)
+			if !errors.Is(err, errRefused) {
+				subT.Fatalf("expected the middleware error, got %v", err)
+			}
+			if trace != "" {
+				subT.Fatalf("expected the request not to be sent")
+			}
+		},
+	)
+}
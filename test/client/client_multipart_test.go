@@ -0,0 +1,495 @@
+package client
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yomiji/gkBoot"
+	"github.com/yomiji/gkBoot/request"
+)
+
+type MultipartTestRequest struct {
+	Description string            `request:"multipart" alias:"description"`
+	Count       int               `request:"multipart" json:"count"`
+	Avatar      gkBoot.FileUpload `request:"multipart!" alias:"avatar"`
+	Notes       io.Reader         `request:"multipart" alias:"notes"`
+	Attachment  *os.File          `request:"multipart" alias:"attachment"`
+	Trace       string            `request:"header" alias:"X-Trace"`
+}
+
+func (m MultipartTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "MultipartTest",
+		Method:      request.POST,
+		Path:        "/uploads",
+		Description: "A test of multipart uploads",
+	}
+}
+
+type MultipartContentTypeTestRequest struct {
+	Icon gkBoot.FileUpload `request:"multipart!" alias:"icon" contentType:"image/vnd.microsoft.icon"`
+}
+
+func (m MultipartContentTypeTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "MultipartContentTypeTest",
+		Method:      request.POST,
+		Path:        "/uploads",
+		Description: "A test of the multipart contentType tag",
+	}
+}
+
+type multipartReceived struct {
+	values       map[string]string
+	files        map[string]string
+	filenames    map[string]string
+	contentTypes map[string]string
+	trace        string
+}
+
+func newMultipartTestServer(received *multipartReceived) *httptest.Server {
+	return httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if err := r.ParseMultipartForm(1 << 20); err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				received.trace = r.Header.Get("X-Trace")
+				received.values = map[string]string{}
+				received.files = map[string]string{}
+				received.filenames = map[string]string{}
+				received.contentTypes = map[string]string{}
+				for name, vals := range r.MultipartForm.Value {
+					received.values[name] = vals[0]
+				}
+				for name, headers := range r.MultipartForm.File {
+					f, _ := headers[0].Open()
+					data, _ := io.ReadAll(f)
+					f.Close()
+					received.files[name] = string(data)
+					received.filenames[name] = headers[0].Filename
+					received.contentTypes[name] = headers[0].Header.Get("Content-Type")
+				}
+				w.WriteHeader(http.StatusOK)
+			},
+		),
+	)
+}
+
+func TestMultipartUpload(t *testing.T) {
+	received := new(multipartReceived)
+	srv := newMultipartTestServer(received)
+	defer srv.Close()
+
+	attachmentPath := filepath.Join(t.TempDir(), "report.txt")
+	if err := os.WriteFile(attachmentPath, []byte("quarterly numbers"), 0o600); err != nil {
+		t.Fatalf("unable to write temp file: %s", err)
+	}
+	attachment, err := os.Open(attachmentPath)
+	if err != nil {
+		t.Fatalf("unable to open temp file: %s", err)
+	}
+	defer attachment.Close()
+
+	t.Run(
+		"Fields And Files", func(subT *testing.T) {
+			req := MultipartTestRequest{
+				Description: "profile update",
+				Count:       3,
+				Avatar: gkBoot.FileUpload{
+					Filename: "me.png", ContentType: "image/png", Reader: strings.NewReader("PNGDATA"),
+				},
+				Notes:      bytes.NewBufferString("some notes"),
+				Attachment: attachment,
+				Trace:      "abc",
+			}
+
+			r, err := gkBoot.GenerateClientRequest(srv.URL, req)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data; boundary=") {
+				subT.Fatalf("unexpected Content-Type '%s'", r.Header.Get("Content-Type"))
+			}
+			if r.ContentLength != -1 {
+				subT.Fatalf("expected a streamed body, got content length %d", r.ContentLength)
+			}
+
+			err = gkBoot.DoGeneratedRequestWithOptions[any](r, nil)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+
+			if received.values["description"] != "profile update" || received.values["count"] != "3" {
+				subT.Fatalf("unexpected values %v", received.values)
+			}
+			if received.files["avatar"] != "PNGDATA" || received.filenames["avatar"] != "me.png" ||
+				received.contentTypes["avatar"] != "image/png" {
+				subT.Fatalf(
+					"unexpected avatar part %q %q %q", received.files["avatar"], received.filenames["avatar"],
+					received.contentTypes["avatar"],
+				)
+			}
+			if received.files["notes"] != "some notes" || received.filenames["notes"] != "notes" {
+				subT.Fatalf("unexpected notes part %q %q", received.files["notes"], received.filenames["notes"])
+			}
+			if received.files["attachment"] != "quarterly numbers" || received.filenames["attachment"] != "report.txt" {
+				subT.Fatalf(
+					"unexpected attachment part %q %q", received.files["attachment"], received.filenames["attachment"],
+				)
+			}
+			if received.trace != "abc" {
+				subT.Fatalf("expected header alongside multipart body, got '%s'", received.trace)
+			}
+		},
+	)
+
+	t.Run(
+		"Required File Missing", func(subT *testing.T) {
+			_, err := gkBoot.GenerateClientRequest(srv.URL, MultipartTestRequest{Description: "no avatar"})
+			if err == nil || !strings.Contains(err.Error(), "required multipart field not found or not set: avatar") {
+				subT.Fatalf("expected required multipart error, got %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Seekable Parts Replay", func(subT *testing.T) {
+			req := MultipartTestRequest{
+				Avatar: gkBoot.FileUpload{Filename: "me.png", Reader: strings.NewReader("PNGDATA")},
+			}
+
+			r, err := gkBoot.GenerateClientRequest(srv.URL, req)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			first, _ := io.ReadAll(r.Body)
+			if r.GetBody == nil {
+				subT.Fatalf("expected a replayable body")
+			}
+			replay, err := r.GetBody()
+			if err != nil {
+				subT.Fatalf("unable to replay body: %s", err)
+			}
+			second, _ := io.ReadAll(replay)
+			if !bytes.Equal(first, second) || !bytes.Contains(second, []byte("PNGDATA")) {
+				subT.Fatalf("replayed body does not match the original")
+			}
+		},
+	)
+
+	t.Run(
+		"Pipe Sent Once", func(subT *testing.T) {
+			pipeReader, pipeWriter, err := os.Pipe()
+			if err != nil {
+				subT.Fatalf("unable to open pipe: %s", err)
+			}
+			defer pipeReader.Close()
+
+			go func() {
+				_, _ = pipeWriter.Write([]byte("piped numbers"))
+				pipeWriter.Close()
+			}()
+
+			req := MultipartTestRequest{
+				Avatar:     gkBoot.FileUpload{Filename: "me.png", Reader: strings.NewReader("PNGDATA")},
+				Attachment: pipeReader,
+			}
+
+			r, err := gkBoot.GenerateClientRequest(srv.URL, req)
+			if err != nil {
+				subT.Fatalf("unexpected error for a pipe that cannot seek: %s", err)
+			}
+			if r.GetBody != nil {
+				subT.Fatalf("expected a body sent once")
+			}
+
+			err = gkBoot.DoGeneratedRequestWithOptions[any](r, nil)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if received.files["attachment"] != "piped numbers" {
+				subT.Fatalf("unexpected attachment part %q", received.files["attachment"])
+			}
+		},
+	)
+
+	t.Run(
+		"Content Type From Extension", func(subT *testing.T) {
+			req := MultipartTestRequest{
+				Avatar: gkBoot.FileUpload{Filename: "me.png", Reader: strings.NewReader("PNGDATA")},
+				Notes:  strings.NewReader("some notes"),
+			}
+
+			r, err := gkBoot.GenerateClientRequest(srv.URL, req)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			err = gkBoot.DoGeneratedRequestWithOptions[any](r, nil)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if received.contentTypes["avatar"] != "image/png" {
+				subT.Fatalf("expected avatar part type image/png, got '%s'", received.contentTypes["avatar"])
+			}
+			if received.contentTypes["notes"] != "application/octet-stream" {
+				subT.Fatalf("expected notes part type application/octet-stream, got '%s'", received.contentTypes["notes"])
+			}
+		},
+	)
+
+	t.Run(
+		"Content Type Tag Overrides Extension", func(subT *testing.T) {
+			req := MultipartContentTypeTestRequest{
+				Icon: gkBoot.FileUpload{Filename: "favicon.png", Reader: strings.NewReader("ICO")},
+			}
+
+			r, err := gkBoot.GenerateClientRequest(srv.URL, req)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			err = gkBoot.DoGeneratedRequestWithOptions[any](r, nil)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if received.contentTypes["icon"] != "image/vnd.microsoft.icon" {
+				subT.Fatalf("expected the tagged part type, got '%s'", received.contentTypes["icon"])
+			}
+		},
+	)
+}
+
+type MultipartFileEntry struct {
+	Name     string `file:"name"`
+	Type     string `file:"contentType"`
+	Contents []byte `file:"contents"`
+	Caption  string `request:"multipart" alias:"caption"`
+}
+
+type MultipartFilesTestRequest struct {
+	Album string               `request:"multipart" alias:"album"`
+	Files []MultipartFileEntry `request:"files!" alias:"files"`
+}
+
+func (m MultipartFilesTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "MultipartFilesTest",
+		Method:      request.POST,
+		Path:        "/uploads",
+		Description: "A test of uploading a slice of files",
+	}
+}
+
+type MultipartReaderFilesTestRequest struct {
+	Files []io.Reader `request:"files" alias:"doc" contentType:"text/plain"`
+}
+
+func (m MultipartReaderFilesTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "MultipartReaderFilesTest",
+		Method:      request.POST,
+		Path:        "/uploads",
+		Description: "A test of uploading a slice of readers",
+	}
+}
+
+func TestMultipartFiles(t *testing.T) {
+	// every part in the order received, as name|filename|content type|contents
+	var parts []string
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				parts = nil
+				reader, err := r.MultipartReader()
+				if err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				for {
+					part, err := reader.NextPart()
+					if err != nil {
+						break
+					}
+					data, _ := io.ReadAll(part)
+					fields := []string{part.FormName(), part.FileName(), part.Header.Get("Content-Type"), string(data)}
+					parts = append(parts, strings.Join(fields, "|"))
+				}
+				w.WriteHeader(http.StatusOK)
+			},
+		),
+	)
+	defer srv.Close()
+
+	t.Run(
+		"Three Files From A Slice", func(subT *testing.T) {
+			req := MultipartFilesTestRequest{
+				Album: "holiday",
+				Files: []MultipartFileEntry{
+					{Name: "beach.png", Contents: []byte("PNG1"), Caption: "the beach"},
+					{Name: "notes.txt", Type: "text/markdown", Contents: []byte("# notes")},
+					{Name: "raw.bin", Contents: []byte{0x00, 0x01}, Caption: "sensor dump"},
+				},
+			}
+
+			err := gkBoot.DoRequestNoResponse(srv.URL, req)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+
+			expected := []string{
+				"album|||holiday",
+				"files|beach.png|image/png|PNG1",
+				"caption|||the beach",
+				"files|notes.txt|text/markdown|# notes",
+				"caption|||",
+				"files|raw.bin|application/octet-stream|\x00\x01",
+				"caption|||sensor dump",
+			}
+			if strings.Join(parts, "\n") != strings.Join(expected, "\n") {
+				subT.Fatalf("expected parts\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(parts, "\n"))
+			}
+		},
+	)
+
+	t.Run(
+		"Slice Of Readers", func(subT *testing.T) {
+			req := MultipartReaderFilesTestRequest{
+				Files: []io.Reader{strings.NewReader("first"), nil, strings.NewReader("second")},
+			}
+
+			err := gkBoot.DoRequestNoResponse(srv.URL, req)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+
+			expected := []string{"doc|doc|text/plain|first", "doc|doc|text/plain|second"}
+			if strings.Join(parts, "\n") != strings.Join(expected, "\n") {
+				subT.Fatalf("expected parts %v, got %v", expected, parts)
+			}
+		},
+	)
+
+	t.Run(
+		"Missing Required Files", func(subT *testing.T) {
+			_, err := gkBoot.GenerateClientRequest(srv.URL, MultipartFilesTestRequest{Album: "empty"})
+			if err == nil || !strings.Contains(err.Error(), "required multipart files") {
+				subT.Fatalf("expected a missing files error, got %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Entry Without Contents", func(subT *testing.T) {
+			_, err := gkBoot.GenerateClientRequest(
+				srv.URL, MultipartFilesTestRequest{Files: []MultipartFileEntry{{Name: "empty.txt"}}},
+			)
+			if err == nil || !strings.Contains(err.Error(), "no contents") {
+				subT.Fatalf("expected a missing contents error, got %v", err)
+			}
+		},
+	)
+}
+
+type MultipartFormMetadata struct {
+	Title string `json:"title"`
+}
+
+type MultipartFormTestRequest struct {
+	Metadata MultipartFormMetadata `request:"form" json:"metadata"`
+	Album    string                `request:"multipart" alias:"album"`
+	Tags     []string              `request:"form" alias:"tags"`
+}
+
+func (m MultipartFormTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "MultipartFormTest",
+		Method:      request.POST,
+		Path:        "/uploads",
+		Description: "A test of form fields composing a multipart body",
+	}
+}
+
+type MultipartCompressedFormTestRequest struct {
+	First  string `request:"form" alias:"first"`
+	Album  string `request:"multipart" alias:"album"`
+	Second string `request:"form" alias:"second" compress:"gzip"`
+}
+
+func (m MultipartCompressedFormTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "MultipartCompressedFormTest",
+		Method:      request.POST,
+		Path:        "/uploads",
+		Description: "A test of a compressed form field among several",
+	}
+}
+
+func TestMultipartFormFields(t *testing.T) {
+	// every part in the order received, as name|content type|contents
+	var parts []string
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				parts = nil
+				reader, err := r.MultipartReader()
+				if err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				for {
+					part, err := reader.NextPart()
+					if err != nil {
+						break
+					}
+					data, _ := io.ReadAll(part)
+					fields := []string{part.FormName(), part.Header.Get("Content-Type"), string(data)}
+					parts = append(parts, strings.Join(fields, "|"))
+				}
+				w.WriteHeader(http.StatusOK)
+			},
+		),
+	)
+	defer srv.Close()
+
+	t.Run(
+		"Form Fields Between Multipart Fields", func(subT *testing.T) {
+			req := MultipartFormTestRequest{
+				Metadata: MultipartFormMetadata{Title: "holiday"},
+				Album:    "summer",
+				Tags:     []string{"beach", "sun"},
+			}
+
+			err := gkBoot.DoRequestNoResponse(srv.URL, req)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+
+			expected := []string{
+				`metadata|application/json|{"title":"holiday"}`,
+				"album||summer",
+				`tags|application/json|["beach","sun"]`,
+			}
+			if strings.Join(parts, "\n") != strings.Join(expected, "\n") {
+				subT.Fatalf("expected parts %v, got %v", expected, parts)
+			}
+		},
+	)
+
+	t.Run(
+		"Compressed Form Field", func(subT *testing.T) {
+			_, err := gkBoot.GenerateClientRequest(
+				srv.URL, MultipartCompressedFormTestRequest{First: "a", Album: "summer", Second: "b"},
+			)
+			if err == nil || !strings.Contains(err.Error(), "not supported in a multipart body") {
+				subT.Fatalf("expected a compression error, got %v", err)
+			}
+		},
+	)
+}
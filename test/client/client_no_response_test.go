@@ -0,0 +1,77 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/yomiji/gkBoot"
+	"github.com/yomiji/gkBoot/request"
+)
+
+type NoResponseTestRequest struct {
+	gkBoot.JSONBody
+	Command string `json:"command"`
+}
+
+func (n NoResponseTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "NoResponseTest",
+		Method:      request.POST,
+		Path:        "/commands",
+		Description: "A command style request with no response",
+	}
+}
+
+func TestDoRequestNoResponse(t *testing.T) {
+	var received string
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				received = string(body)
+				if r.Method != http.MethodPost {
+					w.WriteHeader(http.StatusMethodNotAllowed)
+					return
+				}
+				if received == `{"command":"fail"}` {
+					w.WriteHeader(http.StatusConflict)
+					_, _ = w.Write([]byte("already running"))
+					return
+				}
+				w.WriteHeader(http.StatusNoContent)
+			},
+		),
+	)
+	defer srv.Close()
+
+	t.Run(
+		"204 Is Success", func(subT *testing.T) {
+			err := gkBoot.DoRequestNoResponse(srv.URL, NoResponseTestRequest{Command: "start"})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if received != `{"command":"start"}` {
+				subT.Fatalf("unexpected request body: %s", received)
+			}
+		},
+	)
+
+	t.Run(
+		"Non 2xx Is Error", func(subT *testing.T) {
+			err := gkBoot.DoRequestNoResponse(srv.URL, NoResponseTestRequest{Command: "fail"})
+			if err == nil {
+				subT.Fatalf("expected error for 409 response")
+			}
+			coded, ok := err.(interface{ StatusCode() int })
+			if !ok || coded.StatusCode() != http.StatusConflict {
+				subT.Fatalf("expected status 409 in error, got %v", err)
+			}
+			if !strings.Contains(err.Error(), "already running") {
+				subT.Fatalf("expected response body in error, got '%s'", err)
+			}
+		},
+	)
+}
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yomiji/gkBoot"
+	"github.com/yomiji/gkBoot/request"
+)
+
+type OpaquePathTestRequest struct {
+	Target string `json:"-"`
+	Page   int    `request:"query" json:"page"`
+}
+
+func (o OpaquePathTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "OpaquePathTest",
+		Method:      request.GET,
+		Path:        "/normalized/path",
+		Description: "A test of sending a verbatim request-target",
+	}
+}
+
+func (o OpaquePathTestRequest) OpaquePath() string {
+	return o.Target
+}
+
+func TestOpaquePath(t *testing.T) {
+	var requestURI string
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				requestURI = r.RequestURI
+				w.WriteHeader(http.StatusOK)
+			},
+		),
+	)
+	defer srv.Close()
+
+	t.Run(
+		"Sent Verbatim", func(subT *testing.T) {
+			err := gkBoot.DoRequestNoResponse(
+				srv.URL+"/base", OpaquePathTestRequest{Target: "/files/a%2Fb/./c%7e", Page: 2},
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if requestURI != "/files/a%2Fb/./c%7e?page=2" {
+				subT.Fatalf("expected the request-target verbatim, got '%s'", requestURI)
+			}
+		},
+	)
+
+	t.Run(
+		"Empty Target Keeps Path", func(subT *testing.T) {
+			err := gkBoot.DoRequestNoResponse(srv.URL, OpaquePathTestRequest{Page: 1})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if requestURI != "/normalized/path?page=1" {
+				subT.Fatalf("expected the route path, got '%s'", requestURI)
+			}
+		},
+	)
+}
@@ -0,0 +1,347 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yomiji/gkBoot"
+	"github.com/yomiji/gkBoot/request"
+	"github.com/yomiji/gkBoot/response"
+)
+
+type OptionsTestRequest struct {
+	Status int `query:"status"`
+}
+
+func (o OptionsTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "OptionsTest",
+		Method:      request.GET,
+		Path:        "/options",
+		Description: "A test of client options",
+	}
+}
+
+type OptionsTestResponse struct {
+	Value string `json:"value"`
+	response.ErrorResponse
+}
+
+func newOptionsTestServer() *httptest.Server {
+	return httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("status") == "500" {
+					w.WriteHeader(http.StatusInternalServerError)
+					_, _ = w.Write([]byte("not json at all"))
+					return
+				}
+				_, _ = w.Write([]byte(`{"value":"ok"}`))
+			},
+		),
+	)
+}
+
+func TestStatusValidator(t *testing.T) {
+	srv := newOptionsTestServer()
+	defer srv.Close()
+
+	errInvalid := errors.New("invalid body")
+	validateCalls := 0
+	validators := map[int]func([]byte) error{
+		http.StatusOK: func(body []byte) error {
+			validateCalls++
+			if string(body) != `{"value":"ok"}` {
+				return errInvalid
+			}
+			return nil
+		},
+	}
+
+	t.Run(
+		"Validates 200 Body", func(subT *testing.T) {
+			resp := new(OptionsTestResponse)
+			err := gkBoot.DoRequestWithOptions(
+				srv.URL, OptionsTestRequest{Status: 200}, resp, gkBoot.WithStatusValidator(validators),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if validateCalls != 1 {
+				subT.Fatalf("expected validator to be called once, got %d", validateCalls)
+			}
+			if resp.Value != "ok" {
+				subT.Fatalf("expected decoded value 'ok', got '%s'", resp.Value)
+			}
+		},
+	)
+
+	t.Run(
+		"Skips 500 Body", func(subT *testing.T) {
+			validateCalls = 0
+			resp := new(OptionsTestResponse)
+			_ = gkBoot.DoRequestWithOptions(
+				srv.URL, OptionsTestRequest{Status: 500}, resp, gkBoot.WithStatusValidator(validators),
+			)
+			if validateCalls != 0 {
+				subT.Fatalf("expected validator to be skipped, got %d calls", validateCalls)
+			}
+			if resp.StatusCode() != http.StatusInternalServerError {
+				subT.Fatalf("expected status 500, got %d", resp.StatusCode())
+			}
+		},
+	)
+
+	t.Run(
+		"Validator Error Aborts", func(subT *testing.T) {
+			failing := map[int]func([]byte) error{
+				http.StatusOK: func([]byte) error { return errInvalid },
+			}
+			resp := new(OptionsTestResponse)
+			err := gkBoot.DoRequestWithOptions(
+				srv.URL, OptionsTestRequest{Status: 200}, resp, gkBoot.WithStatusValidator(failing),
+			)
+			if !errors.Is(err, errInvalid) {
+				subT.Fatalf("expected validation error, got %v", err)
+			}
+			if resp.Value != "" {
+				subT.Fatalf("expected body to remain undecoded, got '%s'", resp.Value)
+			}
+		},
+	)
+}
+
+func TestQueryValues(t *testing.T) {
+	t.Run(
+		"Merge With Struct Params", func(subT *testing.T) {
+			extra := url.Values{}
+			extra.Add("page", "2")
+			extra.Add("status", "201")
+
+			r, err := gkBoot.GenerateClientRequestWithOptions(
+				"http://localhost:8080", OptionsTestRequest{Status: 200}, gkBoot.WithQueryValues(extra),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+
+			query := r.URL.Query()
+			if query.Get("page") != "2" {
+				subT.Fatalf("expected page=2, got '%s'", query.Get("page"))
+			}
+			if statuses := query["status"]; len(statuses) != 2 || statuses[0] != "200" || statuses[1] != "201" {
+				subT.Fatalf("expected status values [200 201], got %v", statuses)
+			}
+			if r.URL.RawQuery != "page=2&status=200&status=201" {
+				subT.Fatalf("unexpected encoded query: %s", r.URL.RawQuery)
+			}
+		},
+	)
+}
+
+type DuplicateQueryTestRequest struct {
+	First  string `request:"query" alias:"id"`
+	Second string `request:"query" json:"id"`
+}
+
+func (d DuplicateQueryTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "DuplicateQueryTest",
+		Method:      request.GET,
+		Path:        "/duplicates",
+		Description: "A test of fields resolving to the same query name",
+	}
+}
+
+type warningLogger struct {
+	entries [][]interface{}
+}
+
+func (w *warningLogger) Log(elem ...interface{}) error {
+	w.entries = append(w.entries, elem)
+	return nil
+}
+
+func TestDuplicateQueryKeys(t *testing.T) {
+	req := DuplicateQueryTestRequest{First: "a", Second: "b"}
+
+	t.Run(
+		"Allow And Warn By Default", func(subT *testing.T) {
+			logger := new(warningLogger)
+			r, err := gkBoot.GenerateClientRequestWithOptions(
+				"http://localhost:8080", req, gkBoot.WithClientLogger(logger),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if ids := r.URL.Query()["id"]; len(ids) != 2 {
+				subT.Fatalf("expected two id values, got %v", ids)
+			}
+			if len(logger.entries) != 1 {
+				subT.Fatalf("expected a single warning, got %d", len(logger.entries))
+			}
+		},
+	)
+
+	t.Run(
+		"Error Mode", func(subT *testing.T) {
+			_, err := gkBoot.GenerateClientRequestWithOptions(
+				"http://localhost:8080", req, gkBoot.WithDuplicateQueryKeys(gkBoot.DuplicateQueryKeyError),
+			)
+			if err == nil || !strings.Contains(err.Error(), "duplicate query parameter 'id'") {
+				subT.Fatalf("expected duplicate query parameter error, got %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Merge Mode", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequestWithOptions(
+				"http://localhost:8080", req, gkBoot.WithDuplicateQueryKeys(gkBoot.DuplicateQueryKeyMerge),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if ids := r.URL.Query()["id"]; len(ids) != 1 || ids[0] != "a,b" {
+				subT.Fatalf("expected merged id value 'a,b', got %v", ids)
+			}
+		},
+	)
+}
+
+func TestResponseHook(t *testing.T) {
+	srv := newOptionsTestServer()
+	defer srv.Close()
+
+	t.Run(
+		"Hook Mutates Response", func(subT *testing.T) {
+			resp := new(OptionsTestResponse)
+			err := gkBoot.DoRequestWithOptions(
+				srv.URL, OptionsTestRequest{Status: 200}, resp,
+				gkBoot.WithResponseHook(
+					func(responseObj any) error {
+						r := responseObj.(*OptionsTestResponse)
+						r.Value = strings.ToUpper(r.Value)
+						return nil
+					},
+				),
+				gkBoot.WithResponseHook(
+					func(responseObj any) error {
+						responseObj.(*OptionsTestResponse).Value += "!"
+						return nil
+					},
+				),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.Value != "OK!" {
+				subT.Fatalf("expected hooks to produce 'OK!', got '%s'", resp.Value)
+			}
+		},
+	)
+
+	t.Run(
+		"Hook Error Aborts", func(subT *testing.T) {
+			errHook := errors.New("hook failed")
+			resp := new(OptionsTestResponse)
+			err := gkBoot.DoRequestWithOptions(
+				srv.URL, OptionsTestRequest{Status: 200}, resp,
+				gkBoot.WithResponseHook(func(any) error { return errHook }),
+			)
+			if !errors.Is(err, errHook) {
+				subT.Fatalf("expected hook error, got %v", err)
+			}
+		},
+	)
+}
+
+func TestCloseOption(t *testing.T) {
+	var connection string
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				connection = r.Header.Get("Connection")
+				_, _ = w.Write([]byte(`{"value":"ok"}`))
+			},
+		),
+	)
+	defer srv.Close()
+
+	t.Run(
+		"Sends Connection Close", func(subT *testing.T) {
+			resp := new(OptionsTestResponse)
+			err := gkBoot.DoRequestWithOptions(srv.URL, OptionsTestRequest{}, resp, gkBoot.WithClose(true))
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if connection != "close" {
+				subT.Fatalf("expected Connection: close, got '%s'", connection)
+			}
+		},
+	)
+
+	t.Run(
+		"Keep Alive By Default", func(subT *testing.T) {
+			resp := new(OptionsTestResponse)
+			err := gkBoot.DoRequestWithOptions(srv.URL, OptionsTestRequest{}, resp, gkBoot.WithClose(false))
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if connection != "" {
+				subT.Fatalf("expected no Connection header, got '%s'", connection)
+			}
+		},
+	)
+}
+
+func TestDateHeader(t *testing.T) {
+	t.Run(
+		"Clock Time", func(subT *testing.T) {
+			clock := func() time.Time {
+				return time.Date(1994, time.November, 6, 8, 49, 37, 0, time.FixedZone("EST", -5*60*60))
+			}
+			r, err := gkBoot.GenerateClientRequestWithOptions(
+				"http://localhost:8080", OptionsTestRequest{}, gkBoot.WithDateHeader(), gkBoot.WithClock(clock),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if date := r.Header.Get("Date"); date != "Sun, 06 Nov 1994 13:49:37 GMT" {
+				subT.Fatalf("unexpected Date header %s", date)
+			}
+		},
+	)
+
+	t.Run(
+		"Well Formed", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequestWithOptions(
+				"http://localhost:8080", OptionsTestRequest{}, gkBoot.WithDateHeader(),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			date, err := http.ParseTime(r.Header.Get("Date"))
+			if err != nil {
+				subT.Fatalf("malformed Date header: %s", err)
+			}
+			if time.Since(date) > time.Minute {
+				subT.Fatalf("expected the current time, got %s", date)
+			}
+		},
+	)
+
+	t.Run(
+		"Not Sent By Default", func(subT *testing.T) {
+			r, _ := gkBoot.GenerateClientRequestWithOptions("http://localhost:8080", OptionsTestRequest{})
+			if _, ok := r.Header["Date"]; ok {
+				subT.Fatalf("expected no Date header, got %v", r.Header)
+			}
+		},
+	)
+}
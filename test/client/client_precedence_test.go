@@ -0,0 +1,161 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yomiji/gkBoot"
+)
+
+// precedenceRecorder records the name of the single body interface the pipeline invoked
+type precedenceRecorder struct {
+	winner string
+	calls  int
+}
+
+func (p *precedenceRecorder) record(name string) {
+	p.winner = name
+	p.calls++
+}
+
+type streamCaptureUnmarshalResponse struct{ precedenceRecorder }
+
+func (s *streamCaptureUnmarshalResponse) ReceiveStream(resp *http.Response) error {
+	s.record("stream")
+	return resp.Body.Close()
+}
+
+func (s *streamCaptureUnmarshalResponse) Capture(io.Reader) error {
+	s.record("capture")
+	return nil
+}
+
+func (s *streamCaptureUnmarshalResponse) UnmarshalJSON([]byte) error {
+	s.record("unmarshal")
+	return nil
+}
+
+type captureRawUnmarshalResponse struct{ precedenceRecorder }
+
+func (c *captureRawUnmarshalResponse) Capture(reader io.Reader) error {
+	c.record("capture")
+	_, err := io.Copy(io.Discard, reader)
+	return err
+}
+
+func (c *captureRawUnmarshalResponse) ReceiveBody([]byte) error {
+	c.record("raw")
+	return nil
+}
+
+func (c *captureRawUnmarshalResponse) UnmarshalJSON([]byte) error {
+	c.record("unmarshal")
+	return nil
+}
+
+type rawUnmarshalResponse struct {
+	precedenceRecorder
+	body string
+}
+
+func (r *rawUnmarshalResponse) ReceiveBody(body []byte) error {
+	r.record("raw")
+	r.body = string(body)
+	return nil
+}
+
+func (r *rawUnmarshalResponse) UnmarshalJSON([]byte) error {
+	r.record("unmarshal")
+	return nil
+}
+
+type unmarshalOnlyResponse struct{ precedenceRecorder }
+
+func (u *unmarshalOnlyResponse) UnmarshalJSON([]byte) error {
+	u.record("unmarshal")
+	return nil
+}
+
+type registryDecodedResponse struct {
+	Value string `xml:"value"`
+}
+
+func TestResponseInterfacePrecedence(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/xml")
+				_, _ = w.Write([]byte("<widget><value>ok</value></widget>"))
+			},
+		),
+	)
+	defer srv.Close()
+
+	gkBoot.RegisterDecoder(xmlTestDecoder{})
+	defer gkBoot.DeregisterDecoder("application/xml")
+
+	t.Run(
+		"StreamReceiver Wins", func(subT *testing.T) {
+			resp := new(streamCaptureUnmarshalResponse)
+			if err := gkBoot.DoRequest(srv.URL, OptionsTestRequest{}, resp); err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.winner != "stream" || resp.calls != 1 {
+				subT.Fatalf("expected only the stream receiver, got %s after %d calls", resp.winner, resp.calls)
+			}
+		},
+	)
+
+	t.Run(
+		"CaptureReader Over RawBodyReceiver", func(subT *testing.T) {
+			resp := new(captureRawUnmarshalResponse)
+			if err := gkBoot.DoRequest(srv.URL, OptionsTestRequest{}, resp); err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.winner != "capture" || resp.calls != 1 {
+				subT.Fatalf("expected only the capture reader, got %s after %d calls", resp.winner, resp.calls)
+			}
+		},
+	)
+
+	t.Run(
+		"RawBodyReceiver Over Unmarshaler", func(subT *testing.T) {
+			resp := new(rawUnmarshalResponse)
+			if err := gkBoot.DoRequest(srv.URL, OptionsTestRequest{}, resp); err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.winner != "raw" || resp.calls != 1 {
+				subT.Fatalf("expected only the raw body receiver, got %s after %d calls", resp.winner, resp.calls)
+			}
+			if resp.body != "<widget><value>ok</value></widget>" {
+				subT.Fatalf("unexpected raw body %q", resp.body)
+			}
+		},
+	)
+
+	t.Run(
+		"Unmarshaler Over Registry", func(subT *testing.T) {
+			resp := new(unmarshalOnlyResponse)
+			if err := gkBoot.DoRequest(srv.URL, OptionsTestRequest{}, resp); err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.winner != "unmarshal" || resp.calls != 1 {
+				subT.Fatalf("expected only the unmarshaler, got %s after %d calls", resp.winner, resp.calls)
+			}
+		},
+	)
+
+	t.Run(
+		"Registry By Content Type", func(subT *testing.T) {
+			resp := new(registryDecodedResponse)
+			if err := gkBoot.DoRequest(srv.URL, OptionsTestRequest{}, resp); err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.Value != "ok" {
+				subT.Fatalf("expected the registered XML decoder to decode 'ok', got '%s'", resp.Value)
+			}
+		},
+	)
+}
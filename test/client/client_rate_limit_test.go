@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yomiji/gkBoot"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	now := time.Date(2024, time.March, 5, 14, 30, 0, 0, time.UTC)
+
+	t.Run(
+		"Epoch Reset", func(subT *testing.T) {
+			header := http.Header{}
+			header.Set("X-RateLimit-Limit", "60")
+			header.Set("X-RateLimit-Remaining", "0")
+			header.Set("X-RateLimit-Reset", "1709649060")
+
+			limit, ok := gkBoot.ParseRateLimit(header, now)
+			if !ok || limit.Limit != 60 || limit.Remaining != 0 || !limit.Reset.Equal(now.Add(time.Minute)) {
+				subT.Fatalf("unexpected rate limit %+v", limit)
+			}
+		},
+	)
+
+	t.Run(
+		"Delta Reset", func(subT *testing.T) {
+			header := http.Header{}
+			header.Set("X-RateLimit-Reset", "30")
+
+			limit, ok := gkBoot.ParseRateLimit(header, now)
+			if !ok || limit.Limit != -1 || limit.Remaining != -1 || !limit.Reset.Equal(now.Add(30*time.Second)) {
+				subT.Fatalf("unexpected rate limit %+v", limit)
+			}
+		},
+	)
+
+	t.Run(
+		"Absent", func(subT *testing.T) {
+			if _, ok := gkBoot.ParseRateLimit(http.Header{}, now); ok {
+				subT.Fatalf("expected no rate limit")
+			}
+		},
+	)
+}
+
+func TestTooManyRequests(t *testing.T) {
+	var attempts atomic.Int32
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if attempts.Add(1) == 1 || r.URL.Query().Get("status") == "1" {
+					w.Header().Set("Retry-After", "60")
+					w.Header().Set("X-RateLimit-Remaining", "0")
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				w.Header().Set("X-RateLimit-Remaining", "9")
+				_, _ = w.Write([]byte(`{"value":"ok"}`))
+			},
+		),
+	)
+	defer srv.Close()
+
+	t.Run(
+		"Retry After Honored", func(subT *testing.T) {
+			attempts.Store(0)
+
+			// the wait asked by Retry-After is clamped by the policy to keep the test fast
+			policy := gkBoot.RetryPolicy{MaxAttempts: 2, MaxDelay: 20 * time.Millisecond}
+			resp := new(HeaderCaptureTestResponse)
+			err := gkBoot.DoRequestWithOptions(srv.URL, OptionsTestRequest{}, resp, gkBoot.WithRetryPolicy(policy))
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if attempts.Load() != 2 || resp.Value != "ok" {
+				subT.Fatalf("expected success on the retry, got %d attempts", attempts.Load())
+			}
+			if resp.remaining != "9" {
+				subT.Fatalf("expected the rate limit headers of the last response, got '%s'", resp.remaining)
+			}
+		},
+	)
+
+	t.Run(
+		"Shared Limiter Pauses Later Requests", func(subT *testing.T) {
+			attempts.Store(0)
+			limiter := gkBoot.NewRateLimiter()
+
+			resp := new(HeaderCaptureTestResponse)
+			_ = gkBoot.DoRequestWithOptions(
+				srv.URL, OptionsTestRequest{Status: 1}, resp, gkBoot.WithRateLimiter(limiter),
+			)
+			if resp.StatusCode() != http.StatusTooManyRequests {
+				subT.Fatalf("expected the 429, got %d", resp.StatusCode())
+			}
+			if until := time.Until(limiter.ResumeAt()); until < 50*time.Second {
+				subT.Fatalf("expected requests paused for the Retry-After, got %s", until)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+
+			err := gkBoot.DoRequestWithContext(
+				ctx, srv.URL, OptionsTestRequest{}, new(HeaderCaptureTestResponse), gkBoot.WithRateLimiter(limiter),
+			)
+			if !errors.Is(err, context.DeadlineExceeded) {
+				subT.Fatalf("expected the paused request to wait out its deadline, got %v", err)
+			}
+			if attempts.Load() != 1 {
+				subT.Fatalf("expected the paused request not to be sent, got %d attempts", attempts.Load())
+			}
+		},
+	)
+}
@@ -0,0 +1,123 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/yomiji/gkBoot"
+	"github.com/yomiji/gkBoot/request"
+	"github.com/yomiji/gkBoot/response"
+)
+
+type RedirectTestRequest struct{}
+
+func (r RedirectTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "RedirectTest",
+		Method:      request.GET,
+		Path:        "/account",
+		Description: "A test of redirect policies",
+	}
+}
+
+type RedirectTestResponse struct {
+	response.BasicResponse
+	Value    string `json:"value"`
+	location string
+}
+
+func (r *RedirectTestResponse) CaptureHeaders(header http.Header) {
+	r.location = header.Get("Location")
+}
+
+func TestRedirectPolicy(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/account" {
+					http.Redirect(w, r, "/login", http.StatusFound)
+					return
+				}
+				_, _ = w.Write([]byte(`{"value":"login page"}`))
+			},
+		),
+	)
+	defer srv.Close()
+
+	t.Run(
+		"Follows By Default", func(subT *testing.T) {
+			resp := new(RedirectTestResponse)
+			err := gkBoot.DoRequestWithOptions(srv.URL, RedirectTestRequest{}, resp)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.StatusCode() != http.StatusOK || resp.Value != "login page" {
+				subT.Fatalf("expected the redirect to be followed, got %d '%s'", resp.StatusCode(), resp.Value)
+			}
+		},
+	)
+
+	t.Run(
+		"No Follow Returns 3xx", func(subT *testing.T) {
+			resp := new(RedirectTestResponse)
+			err := gkBoot.DoRequestWithOptions(
+				srv.URL, RedirectTestRequest{}, resp, gkBoot.WithRedirectPolicy(gkBoot.NoFollowRedirects),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.StatusCode() != http.StatusFound || resp.location != "/login" {
+				subT.Fatalf("expected 302 to /login, got %d to '%s'", resp.StatusCode(), resp.location)
+			}
+			if resp.Value != "" {
+				subT.Fatalf("expected the redirect body to be left undecoded, got '%s'", resp.Value)
+			}
+			if http.DefaultClient.CheckRedirect != nil {
+				subT.Fatalf("expected http.DefaultClient to be left untouched")
+			}
+		},
+	)
+
+	t.Run(
+		"Follow Overrides Client", func(subT *testing.T) {
+			client := &http.Client{CheckRedirect: gkBoot.NoFollowRedirects}
+
+			resp := new(RedirectTestResponse)
+			err := gkBoot.DoRequestWithOptions(
+				srv.URL, RedirectTestRequest{}, resp, gkBoot.WithHTTPClient(client),
+				gkBoot.WithRedirectPolicy(gkBoot.FollowRedirects),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.Value != "login page" {
+				subT.Fatalf("expected the redirect to be followed, got '%s'", resp.Value)
+			}
+		},
+	)
+
+	t.Run(
+		"Custom Policy", func(subT *testing.T) {
+			errBlocked := errors.New("login redirect blocked")
+			var target string
+
+			err := gkBoot.DoRequestWithOptions(
+				srv.URL, RedirectTestRequest{}, new(RedirectTestResponse), gkBoot.WithRedirectPolicy(
+					func(req *http.Request, via []*http.Request) error {
+						target = req.URL.Path
+						if strings.HasPrefix(req.URL.Path, "/login") {
+							return errBlocked
+						}
+						return nil
+					},
+				),
+			)
+			if !errors.Is(err, errBlocked) || target != "/login" {
+				subT.Fatalf("expected the custom policy to block /login, got %v for '%s'", err, target)
+			}
+		},
+	)
+}
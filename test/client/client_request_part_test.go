@@ -0,0 +1,196 @@
+package client
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yomiji/gkBoot"
+	"github.com/yomiji/gkBoot/request"
+)
+
+func TestRequestParts(t *testing.T) {
+	t.Run(
+		"Parse Tags", func(subT *testing.T) {
+			cases := []struct {
+				tag      string
+				part     gkBoot.RequestPart
+				required bool
+				ok       bool
+			}{
+				{"path!", gkBoot.RequestPartPath, true, true},
+				{"query", gkBoot.RequestPartQuery, false, true},
+				{"query!,omitempty", gkBoot.RequestPartQuery, true, true},
+				{"header,keepempty", gkBoot.RequestPartHeader, false, true},
+				{"cookie", gkBoot.RequestPartCookie, false, true},
+				{"form", gkBoot.RequestPartForm, false, true},
+				{"multipart!", gkBoot.RequestPartMultipart, true, true},
+				{"body", gkBoot.RequestPart("body"), false, false},
+			}
+
+			for _, c := range cases {
+				part, required, ok := gkBoot.ParseRequestPart(c.tag)
+				if part != c.part || required != c.required || ok != c.ok {
+					subT.Fatalf(
+						"tag %s parsed as (%s, %t, %t), want (%s, %t, %t)", c.tag, part, required, ok, c.part,
+						c.required, c.ok,
+					)
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"Every Part Listed", func(subT *testing.T) {
+			expected := []gkBoot.RequestPart{
+				gkBoot.RequestPartPath, gkBoot.RequestPartQuery, gkBoot.RequestPartHeader, gkBoot.RequestPartCookie,
+				gkBoot.RequestPartForm, gkBoot.RequestPartMultipart, gkBoot.RequestPartQueryRest,
+				gkBoot.RequestPartRaw, gkBoot.RequestPartFiles,
+			}
+			if !reflect.DeepEqual(gkBoot.RequestParts(), expected) {
+				subT.Fatalf("unexpected request parts %v", gkBoot.RequestParts())
+			}
+		},
+	)
+
+	t.Run(
+		"Field Tag Parsed", func(subT *testing.T) {
+			type PartTestRequest struct {
+				OptionsTestRequest
+				Token string `request:"header" alias:"X-Token"`
+			}
+
+			field, _ := reflect.TypeOf(PartTestRequest{}).FieldByName("Token")
+			part, _, ok := gkBoot.ParseRequestPart(field.Tag.Get("request"))
+			if !ok || part != gkBoot.RequestPartHeader {
+				subT.Fatalf("expected the header part, got %s", part)
+			}
+
+			r, err := gkBoot.GenerateClientRequest(
+				"http://localhost:8080", PartTestRequest{Token: "t"},
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if r.Header.Get("X-Token") != "t" {
+				subT.Fatalf("expected the %s part to be written, got %v", part, r.Header)
+			}
+		},
+	)
+}
+
+type DocPaging struct {
+	Page int `request:"query" json:"page"`
+}
+
+type DocTestRequest struct {
+	ID      int               `request:"path!"`
+	Status  []string          `request:"query" alias:"status" enum:"open, closed"`
+	Trace   string            `request:"header" alias:"X-Trace"`
+	Session *string           `request:"cookie" alias:"session" requiredFor:"PUT"`
+	Body    map[string]string `request:"form" json:"body"`
+	Token   request.BearerToken
+	Limit   int `query:"limit" required:"true"`
+	DocPaging
+}
+
+func (d DocTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "DocTest",
+		Method:      request.PUT,
+		Path:        "/tickets/{ID}",
+		Description: "A test of request documentation",
+	}
+}
+
+type DocBodyTestRequest struct {
+	gkBoot.JSONBody
+	Name string `json:"name"`
+}
+
+func (d DocBodyTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Method: request.POST,
+		Path:   "/tickets",
+	}
+}
+
+type DocUnknownTestRequest struct {
+	Value string `request:"body"`
+}
+
+func (d DocUnknownTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{Name: "DocUnknownTest", Method: request.GET, Path: "/unknown"}
+}
+
+func TestGenerateRequestDoc(t *testing.T) {
+	t.Run(
+		"Representative Request", func(subT *testing.T) {
+			doc, err := gkBoot.GenerateRequestDoc(DocTestRequest{})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if doc.Name != "DocTest" || doc.Method != "PUT" || doc.Path != "/tickets/{ID}" {
+				subT.Fatalf("unexpected route %s %s %s", doc.Name, doc.Method, doc.Path)
+			}
+
+			expected := []gkBoot.ParamDoc{
+				{Part: gkBoot.RequestPartPath, Name: "ID", Field: "ID", Type: "int", Required: true},
+				{
+					Part: gkBoot.RequestPartQuery, Name: "status", Field: "Status", Type: "[]string",
+					Enum: []string{"open", "closed"},
+				},
+				{Part: gkBoot.RequestPartHeader, Name: "X-Trace", Field: "Trace", Type: "string"},
+				{Part: gkBoot.RequestPartCookie, Name: "session", Field: "Session", Type: "*string", Required: true},
+				{Part: gkBoot.RequestPartForm, Name: "body", Field: "Body", Type: "map[string]string"},
+				{Part: gkBoot.RequestPartHeader, Name: "Authorization", Field: "Token", Type: "request.BearerToken"},
+				{Part: gkBoot.RequestPartQuery, Name: "limit", Field: "Limit", Type: "int", Required: true},
+				{Part: gkBoot.RequestPartQuery, Name: "page", Field: "Page", Type: "int"},
+			}
+			if !reflect.DeepEqual(doc.Params, expected) {
+				subT.Fatalf("unexpected params\n%+v\nexpected\n%+v", doc.Params, expected)
+			}
+		},
+	)
+
+	t.Run(
+		"Categorized", func(subT *testing.T) {
+			doc, _ := gkBoot.GenerateRequestDoc(DocTestRequest{})
+
+			query := doc.In(gkBoot.RequestPartQuery)
+			if len(query) != 3 || query[0].Name != "status" || query[1].Name != "limit" || query[2].Name != "page" {
+				subT.Fatalf("unexpected query params %+v", query)
+			}
+			if headers := doc.In(gkBoot.RequestPartHeader); len(headers) != 2 {
+				subT.Fatalf("unexpected header params %+v", headers)
+			}
+			if multipart := doc.In(gkBoot.RequestPartMultipart); len(multipart) != 0 {
+				subT.Fatalf("expected no multipart params, got %+v", multipart)
+			}
+		},
+	)
+
+	t.Run(
+		"Whole Body", func(subT *testing.T) {
+			doc, err := gkBoot.GenerateRequestDoc(&DocBodyTestRequest{})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			body := doc.In(gkBoot.RequestPartForm)
+			if len(body) != 1 || body[0].Name != "DocBodyTestRequest" || len(doc.Params) != 1 {
+				subT.Fatalf("expected a single body param, got %+v", doc.Params)
+			}
+			if doc.Name == "" {
+				subT.Fatalf("expected a name for a route without one")
+			}
+		},
+	)
+
+	t.Run(
+		"Unknown Part", func(subT *testing.T) {
+			_, err := gkBoot.GenerateRequestDoc(DocUnknownTestRequest{})
+			if err == nil {
+				subT.Fatalf("expected the unknown part to fail")
+			}
+		},
+	)
+}
@@ -0,0 +1,623 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/yomiji/gkBoot"
+	"github.com/yomiji/gkBoot/request"
+)
+
+type RequiredTestRequest struct {
+	Count   int     `request:"query!" alias:"count"`
+	Enabled bool    `request:"query!" alias:"enabled"`
+	Name    string  `request:"header!" alias:"X-Name"`
+	Owner   *string `request:"query!" alias:"owner"`
+}
+
+func (rt RequiredTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "RequiredTest",
+		Method:      request.GET,
+		Path:        "/required",
+		Description: "A test of required field semantics",
+	}
+}
+
+func TestRequiredFields(t *testing.T) {
+	owner := "alice"
+
+	t.Run(
+		"Zero Values Are Present", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest("http://localhost:8080", RequiredTestRequest{Owner: &owner})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			query := r.URL.Query()
+			if query.Get("count") != "0" || query.Get("enabled") != "false" {
+				subT.Fatalf("expected zero int and false bool to be sent, got %s", r.URL.RawQuery)
+			}
+			if values, ok := r.Header["X-Name"]; !ok || values[0] != "" {
+				subT.Fatalf("expected the empty string header to be sent, got %v", r.Header)
+			}
+		},
+	)
+
+	t.Run(
+		"Nil Pointer Is Missing", func(subT *testing.T) {
+			_, err := gkBoot.GenerateClientRequest("http://localhost:8080", RequiredTestRequest{})
+			if err == nil || !strings.Contains(err.Error(), "owner") {
+				subT.Fatalf("expected the nil owner to fail, got %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Strict Rejects Empty String", func(subT *testing.T) {
+			_, err := gkBoot.GenerateClientRequestWithOptions(
+				"http://localhost:8080", RequiredTestRequest{Owner: &owner}, gkBoot.WithStrictRequired(),
+			)
+			if err == nil || !strings.Contains(err.Error(), "X-Name") {
+				subT.Fatalf("expected the empty name to fail, got %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Strict Accepts Zero Numbers", func(subT *testing.T) {
+			_, err := gkBoot.GenerateClientRequestWithOptions(
+				"http://localhost:8080", RequiredTestRequest{Name: "n", Owner: &owner}, gkBoot.WithStrictRequired(),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+		},
+	)
+}
+
+type AllRequiredTestRequest struct {
+	ID      *int    `request:"path!"`
+	Session *string `request:"cookie!" alias:"session"`
+	Token   *string `request:"header!" alias:"X-Token"`
+	Filter  *string `request:"query!" alias:"filter"`
+}
+
+func (rt AllRequiredTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "AllRequiredTest",
+		Method:      request.GET,
+		Path:        "/required/{ID}",
+		Description: "A test of reporting every missing required field",
+	}
+}
+
+func (rt AllRequiredTestRequest) Validate() error {
+	if rt.Filter == nil {
+		return errors.New("filter must be given")
+	}
+	return nil
+}
+
+func TestAllRequiredErrors(t *testing.T) {
+	t.Run(
+		"Every Error Reported", func(subT *testing.T) {
+			_, err := gkBoot.GenerateClientRequest("http://localhost:8080", AllRequiredTestRequest{})
+			if err == nil {
+				subT.Fatalf("expected the missing fields to fail")
+			}
+			for _, expected := range []string{"filter must be given", "ID", "session", "X-Token", "filter"} {
+				if !strings.Contains(err.Error(), expected) {
+					subT.Fatalf("expected the error to mention %s, got %s", expected, err)
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"Fail Fast Reports First", func(subT *testing.T) {
+			_, err := gkBoot.GenerateClientRequestWithOptions(
+				"http://localhost:8080", AllRequiredTestRequest{}, gkBoot.WithFailFast(),
+			)
+			if err == nil || !strings.Contains(err.Error(), "filter must be given") {
+				subT.Fatalf("expected the validation error, got %v", err)
+			}
+			if strings.Contains(err.Error(), "X-Token") {
+				subT.Fatalf("expected only the first error, got %s", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Fail Fast Stops At First Field", func(subT *testing.T) {
+			filter := "f"
+			_, err := gkBoot.GenerateClientRequestWithOptions(
+				"http://localhost:8080", AllRequiredTestRequest{Filter: &filter}, gkBoot.WithFailFast(),
+			)
+			if err == nil || !strings.Contains(err.Error(), "ID") || strings.Contains(err.Error(), "session") {
+				subT.Fatalf("expected only the missing path error, got %v", err)
+			}
+		},
+	)
+}
+
+type OptionalTestRequest struct {
+	Filter  *string `request:"query" alias:"filter"`
+	Token   *string `request:"header" alias:"X-Token"`
+	Session *string `request:"cookie" alias:"session"`
+}
+
+func (ot OptionalTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "OptionalTest",
+		Method:      request.GET,
+		Path:        "/optional",
+		Description: "A test of omitting nil optional fields",
+	}
+}
+
+func TestNilOptionalFields(t *testing.T) {
+	t.Run(
+		"Nil Pointers Omitted", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest("http://localhost:8080", OptionalTestRequest{})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if r.URL.RawQuery != "" {
+				subT.Fatalf("expected no query, got %s", r.URL.RawQuery)
+			}
+			if _, ok := r.Header["X-Token"]; ok {
+				subT.Fatalf("expected no X-Token header, got %v", r.Header)
+			}
+			if len(r.Cookies()) != 0 {
+				subT.Fatalf("expected no cookies, got %v", r.Cookies())
+			}
+		},
+	)
+
+	t.Run(
+		"Empty Values Sent", func(subT *testing.T) {
+			empty := ""
+			r, err := gkBoot.GenerateClientRequest(
+				"http://localhost:8080", OptionalTestRequest{Filter: &empty, Token: &empty, Session: &empty},
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if r.URL.RawQuery != "filter=" {
+				subT.Fatalf("expected the empty filter, got %s", r.URL.RawQuery)
+			}
+			if values, ok := r.Header["X-Token"]; !ok || values[0] != "" {
+				subT.Fatalf("expected the empty X-Token header, got %v", r.Header)
+			}
+			if cookie, err := r.Cookie("session"); err != nil || cookie.Value != "" {
+				subT.Fatalf("expected the empty session cookie, got %v", r.Cookies())
+			}
+		},
+	)
+}
+
+type OmitEmptyTestRequest struct {
+	Page    int     `request:"query,omitempty" alias:"page"`
+	Search  string  `request:"query" json:"search,omitempty"`
+	Verbose bool    `request:"header,omitempty" alias:"X-Verbose"`
+	Limit   int     `request:"query,keepempty" json:"limit,omitempty"`
+	Session string  `request:"cookie,omitempty" alias:"session"`
+	Sort    string  `request:"query!,omitempty" alias:"sort"`
+	Cursor  *string `request:"query,omitempty" alias:"cursor"`
+	Count   *int    `request:"header" alias:"X-Count" json:"count,omitempty"`
+}
+
+func (ot OmitEmptyTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "OmitEmptyTest",
+		Method:      request.GET,
+		Path:        "/omitempty",
+		Description: "A test of omitting empty optional fields",
+	}
+}
+
+func TestOmitEmptyFields(t *testing.T) {
+	t.Run(
+		"Empty Values Omitted", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest("http://localhost:8080", OmitEmptyTestRequest{})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			// keepempty overrides the json omitempty and required fields are always written
+			if r.URL.RawQuery != "limit=0&sort=" {
+				subT.Fatalf("expected only limit and sort, got %s", r.URL.RawQuery)
+			}
+			if _, ok := r.Header["X-Verbose"]; ok {
+				subT.Fatalf("expected no X-Verbose header, got %v", r.Header)
+			}
+			if len(r.Cookies()) != 0 {
+				subT.Fatalf("expected no cookies, got %v", r.Cookies())
+			}
+		},
+	)
+
+	t.Run(
+		"Set Values Sent", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest(
+				"http://localhost:8080", OmitEmptyTestRequest{
+					Page: 2, Search: "go", Verbose: true, Limit: 10, Session: "s", Sort: "name",
+				},
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if r.URL.RawQuery != "limit=10&page=2&search=go&sort=name" {
+				subT.Fatalf("unexpected query %s", r.URL.RawQuery)
+			}
+			if r.Header.Get("X-Verbose") != "true" {
+				subT.Fatalf("expected the X-Verbose header, got %v", r.Header)
+			}
+			if cookie, err := r.Cookie("session"); err != nil || cookie.Value != "s" {
+				subT.Fatalf("expected the session cookie, got %v", r.Cookies())
+			}
+		},
+	)
+
+	t.Run(
+		"Non-Nil Pointers Sent", func(subT *testing.T) {
+			cursor := ""
+			count := 0
+			r, err := gkBoot.GenerateClientRequest(
+				"http://localhost:8080", OmitEmptyTestRequest{Cursor: &cursor, Count: &count},
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if r.URL.RawQuery != "cursor=&limit=0&sort=" {
+				subT.Fatalf("expected the empty cursor, got %s", r.URL.RawQuery)
+			}
+			if r.Header.Get("X-Count") != "0" {
+				subT.Fatalf("expected the X-Count header, got %v", r.Header)
+			}
+		},
+	)
+}
+
+type SharedUserFields struct {
+	ID   int     `request:"query" alias:"id"`
+	Name *string `request:"query" alias:"name" requiredFor:"POST,put"`
+}
+
+type CreateUserTestRequest struct {
+	SharedUserFields
+}
+
+func (c CreateUserTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "CreateUserTest",
+		Method:      request.POST,
+		Path:        "/users",
+		Description: "A test of fields required for a method",
+	}
+}
+
+type FindUserTestRequest struct {
+	SharedUserFields
+}
+
+func (f FindUserTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "FindUserTest",
+		Method:      request.GET,
+		Path:        "/users",
+		Description: "A test of fields required for a method",
+	}
+}
+
+func TestRequiredForMethod(t *testing.T) {
+	name := "alice"
+
+	t.Run(
+		"Required For Listed Method", func(subT *testing.T) {
+			_, err := gkBoot.GenerateClientRequest("http://localhost:8080", CreateUserTestRequest{})
+			if err == nil || !strings.Contains(err.Error(), "name") {
+				subT.Fatalf("expected the missing name to fail a POST, got %v", err)
+			}
+
+			r, err := gkBoot.GenerateClientRequest(
+				"http://localhost:8080", CreateUserTestRequest{SharedUserFields{Name: &name}},
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if r.URL.Query().Get("name") != "alice" {
+				subT.Fatalf("expected the name to be sent, got %s", r.URL.RawQuery)
+			}
+		},
+	)
+
+	t.Run(
+		"Optional For Other Methods", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest("http://localhost:8080", FindUserTestRequest{SharedUserFields{ID: 7}})
+			if err != nil {
+				subT.Fatalf("expected the name to be optional for a GET, got %s", err)
+			}
+			if r.URL.Query().Get("id") != "7" {
+				subT.Fatalf("unexpected query %s", r.URL.RawQuery)
+			}
+		},
+	)
+
+	t.Run(
+		"Server Decoder", func(subT *testing.T) {
+			decoder, err := gkBoot.GenerateRequestDecoder(CreateUserTestRequest{})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+
+			_, err = decoder(context.Background(), httptest.NewRequest(http.MethodPost, "/users?id=7", nil))
+			if err == nil {
+				subT.Fatalf("expected the missing name to fail a POST")
+			}
+
+			_, err = decoder(context.Background(), httptest.NewRequest(http.MethodGet, "/users?id=7", nil))
+			if err != nil {
+				subT.Fatalf("expected the name to be optional for a GET, got %s", err)
+			}
+		},
+	)
+}
+
+type Color string
+
+type EnumTestRequest struct {
+	Color    Color    `request:"query" alias:"color" enum:"red, green,blue"`
+	Level    int      `request:"query" alias:"level" enum:"1,2,3"`
+	Sizes    []string `request:"query" alias:"size" enum:"s,m,l"`
+	Priority *uint8   `request:"header" alias:"X-Priority" enum:"0,9"`
+}
+
+func (e EnumTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "EnumTest",
+		Method:      request.GET,
+		Path:        "/enum",
+		Description: "A test of enum validation",
+	}
+}
+
+type EnumEmbeddedTestRequest struct {
+	EnumTestRequest
+}
+
+type EnumSkipTestRequest struct {
+	EnumTestRequest
+	gkBoot.UsingSkipClientValidation
+}
+
+func TestEnumValidation(t *testing.T) {
+	t.Run(
+		"Allowed Values", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest(
+				"http://localhost:8080", EnumTestRequest{Color: "green", Level: 2, Sizes: []string{"s", "l"}},
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if r.URL.RawQuery != "color=green&level=2&size=s%2Cl" {
+				subT.Fatalf("unexpected query %s", r.URL.RawQuery)
+			}
+		},
+	)
+
+	t.Run(
+		"Every Disallowed Value Reported", func(subT *testing.T) {
+			priority := uint8(5)
+			_, err := gkBoot.GenerateClientRequest(
+				"http://localhost:8080",
+				EnumTestRequest{Color: "purple", Level: 4, Sizes: []string{"m", "xl"}, Priority: &priority},
+			)
+			if err == nil {
+				subT.Fatalf("expected the disallowed values to fail")
+			}
+			for _, expected := range []string{`"purple"`, `"4"`, `"xl"`, `"5"`, "red, green,blue"} {
+				if !strings.Contains(err.Error(), expected) {
+					subT.Fatalf("expected the error to mention %s, got %s", expected, err)
+				}
+			}
+			if strings.Contains(err.Error(), `"m"`) {
+				subT.Fatalf("expected the allowed element to pass, got %s", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Zero Values Not Checked", func(subT *testing.T) {
+			_, err := gkBoot.GenerateClientRequest("http://localhost:8080", EnumTestRequest{})
+			if err != nil {
+				subT.Fatalf("expected the unset fields to pass, got %s", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Embedded Fields", func(subT *testing.T) {
+			_, err := gkBoot.GenerateClientRequest(
+				"http://localhost:8080", EnumEmbeddedTestRequest{EnumTestRequest{Color: "purple", Level: 1}},
+			)
+			if err == nil || !strings.Contains(err.Error(), "Color") {
+				subT.Fatalf("expected the embedded field to fail, got %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Skipped", func(subT *testing.T) {
+			_, err := gkBoot.GenerateClientRequest(
+				"http://localhost:8080", EnumSkipTestRequest{EnumTestRequest: EnumTestRequest{Color: "purple"}},
+			)
+			if err != nil {
+				subT.Fatalf("expected validation to be skipped, got %s", err)
+			}
+		},
+	)
+}
+
+type RangeTestRequest struct {
+	Age    int      `request:"query" alias:"age" min:"18" max:"120"`
+	Ratio  *float64 `request:"query" alias:"ratio" max:"1"`
+	Name   string   `request:"query" alias:"name" minLength:"3" maxLength:"5"`
+	Tags   []string `request:"query" alias:"tag" maxLength:"3"`
+	Limit  uint     `query:"limit" minimum:"1" maximum:"50"`
+	Broken int      `request:"header" alias:"X-Broken" min:"low"`
+}
+
+func (rt RangeTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "RangeTest",
+		Method:      request.GET,
+		Path:        "/range",
+		Description: "A test of range and length validation",
+	}
+}
+
+func (rt RangeTestRequest) Validate() error {
+	if rt.Name == "admin" {
+		return errors.New("name is reserved")
+	}
+	return nil
+}
+
+type RangeSkipTestRequest struct {
+	RangeTestRequest
+	gkBoot.UsingSkipClientValidation
+}
+
+func TestRangeValidation(t *testing.T) {
+	t.Run(
+		"Within Bounds", func(subT *testing.T) {
+			ratio := 0.5
+			_, err := gkBoot.GenerateClientRequest(
+				"http://localhost:8080",
+				RangeTestRequest{Age: 18, Ratio: &ratio, Name: "émile", Tags: []string{"a", "abc"}, Limit: 50},
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Violations Name Field And Constraint", func(subT *testing.T) {
+			ratio := 1.5
+			_, err := gkBoot.GenerateClientRequest(
+				"http://localhost:8080",
+				RangeTestRequest{Age: 121, Ratio: &ratio, Name: "al", Tags: []string{"abcd"}, Limit: 51},
+			)
+			if err == nil {
+				subT.Fatalf("expected the out of range values to fail")
+			}
+			for _, expected := range []string{
+				"'Age' value 121 exceeds the maximum of 120",
+				"'Ratio' value 1.5 exceeds the maximum of 1",
+				"'Name' length 2 is below the minLength of 3",
+				"'Tags' length 4 exceeds the maxLength of 3",
+				"'Limit' value 51 exceeds the maximum of 50",
+			} {
+				if !strings.Contains(err.Error(), expected) {
+					subT.Fatalf("expected the error to mention %s, got %s", expected, err)
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"Composes With Validator", func(subT *testing.T) {
+			_, err := gkBoot.GenerateClientRequest("http://localhost:8080", RangeTestRequest{Age: 5, Name: "admin"})
+			if err == nil || !strings.Contains(err.Error(), "name is reserved") ||
+					!strings.Contains(err.Error(), "'Age' value 5 is below the minimum of 18") {
+				subT.Fatalf("expected both the validator and the range errors, got %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Invalid Tag", func(subT *testing.T) {
+			_, err := gkBoot.GenerateClientRequest("http://localhost:8080", RangeTestRequest{Broken: 1})
+			if err == nil || !strings.Contains(err.Error(), "invalid min tag") {
+				subT.Fatalf("expected the invalid tag to be reported, got %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Skipped", func(subT *testing.T) {
+			_, err := gkBoot.GenerateClientRequest(
+				"http://localhost:8080", RangeSkipTestRequest{RangeTestRequest: RangeTestRequest{Age: 5, Broken: 1}},
+			)
+			if err != nil {
+				subT.Fatalf("expected validation to be skipped, got %s", err)
+			}
+		},
+	)
+}
+
+type PatternTestRequest struct {
+	Currency  string   `request:"query" alias:"currency" pattern:"^[A-Z]{3}$"`
+	Countries []string `request:"query" alias:"country" pattern:"^[A-Z]{2}$"`
+	Broken    string   `request:"header" alias:"X-Broken" pattern:"^[A-Z"`
+}
+
+func (p PatternTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "PatternTest",
+		Method:      request.GET,
+		Path:        "/pattern",
+		Description: "A test of pattern validation",
+	}
+}
+
+func TestPatternValidation(t *testing.T) {
+	t.Run(
+		"Matching Values", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest(
+				"http://localhost:8080", PatternTestRequest{Currency: "EUR", Countries: []string{"FR", "DE"}},
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if r.URL.Query().Get("currency") != "EUR" {
+				subT.Fatalf("unexpected query %s", r.URL.RawQuery)
+			}
+		},
+	)
+
+	t.Run(
+		"Mismatch Rejected", func(subT *testing.T) {
+			_, err := gkBoot.GenerateClientRequest(
+				"http://localhost:8080", PatternTestRequest{Currency: "euro", Countries: []string{"FR", "DEU"}},
+			)
+			if err == nil {
+				subT.Fatalf("expected the mismatching values to fail")
+			}
+			for _, expected := range []string{`'Currency' value "euro"`, `'Countries' value "DEU"`} {
+				if !strings.Contains(err.Error(), expected) {
+					subT.Fatalf("expected the error to mention %s, got %s", expected, err)
+				}
+			}
+			if errors.Is(err, gkBoot.ErrInvalidPattern) {
+				subT.Fatalf("expected a mismatch to be told from an invalid pattern, got %s", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Invalid Pattern", func(subT *testing.T) {
+			for i := 0; i < 2; i++ {
+				_, err := gkBoot.GenerateClientRequest("http://localhost:8080", PatternTestRequest{Broken: "A"})
+				if !errors.Is(err, gkBoot.ErrInvalidPattern) || !strings.Contains(err.Error(), "Broken") {
+					subT.Fatalf("expected an invalid pattern error, got %v", err)
+				}
+			}
+		},
+	)
+}
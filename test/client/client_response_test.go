@@ -0,0 +1,544 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/yomiji/gkBoot"
+	"github.com/yomiji/gkBoot/request"
+	"github.com/yomiji/gkBoot/response"
+)
+
+type EnvelopeTestRequest struct{}
+
+func (e EnvelopeTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "EnvelopeTest",
+		Method:      request.POST,
+		Path:        "/widgets",
+		Description: "A test of the response envelope",
+	}
+}
+
+type EnvelopeTestWidget struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestEnvelope(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Location", "/widgets/7")
+				w.Header().Set("X-RateLimit-Remaining", "42")
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte(`{"id":7,"name":"sprocket"}`))
+			},
+		),
+	)
+	defer srv.Close()
+
+	t.Run(
+		"Status Headers And Body", func(subT *testing.T) {
+			resp := new(response.Envelope[EnvelopeTestWidget])
+			err := gkBoot.DoRequest(srv.URL, EnvelopeTestRequest{}, resp)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.StatusCode() != http.StatusCreated {
+				subT.Fatalf("expected status 201, got %d", resp.StatusCode())
+			}
+			if resp.Headers.Get("Location") != "/widgets/7" {
+				subT.Fatalf("expected Location header, got '%s'", resp.Headers.Get("Location"))
+			}
+			if resp.Headers.Get("X-RateLimit-Remaining") != "42" {
+				subT.Fatalf("expected rate limit header, got '%s'", resp.Headers.Get("X-RateLimit-Remaining"))
+			}
+			if resp.Data.ID != 7 || resp.Data.Name != "sprocket" {
+				subT.Fatalf("unexpected body: %+v", resp.Data)
+			}
+		},
+	)
+}
+
+func TestHandBuiltRequestPipeline(t *testing.T) {
+	var receivedQuery string
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				receivedQuery = r.URL.RawQuery
+				w.Header().Set("X-Request-Id", "abc")
+				w.WriteHeader(http.StatusAccepted)
+				_, _ = w.Write([]byte(`{"id":3,"name":"gear"}`))
+			},
+		),
+	)
+	defer srv.Close()
+
+	t.Run(
+		"Stdlib Request Uses Full Pipeline", func(subT *testing.T) {
+			r, err := http.NewRequest(http.MethodGet, srv.URL+"/widgets?sort=asc", nil)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+
+			validated := false
+			resp := new(response.Envelope[EnvelopeTestWidget])
+			err = gkBoot.DoGeneratedRequestWithOptions(
+				r, resp,
+				gkBoot.WithQueryValues(url.Values{"page": {"1"}}),
+				gkBoot.WithStatusValidator(
+					map[int]func([]byte) error{
+						http.StatusAccepted: func([]byte) error {
+							validated = true
+							return nil
+						},
+					},
+				),
+				gkBoot.WithResponseHook(
+					func(responseObj any) error {
+						responseObj.(*response.Envelope[EnvelopeTestWidget]).Data.Name += "!"
+						return nil
+					},
+				),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if receivedQuery != "page=1&sort=asc" {
+				subT.Fatalf("expected merged query, got '%s'", receivedQuery)
+			}
+			if !validated {
+				subT.Fatalf("expected status validator to run")
+			}
+			if resp.StatusCode() != http.StatusAccepted || resp.Headers.Get("X-Request-Id") != "abc" {
+				subT.Fatalf("expected status and headers to be captured, got %d %v", resp.StatusCode(), resp.Headers)
+			}
+			if resp.Data.ID != 3 || resp.Data.Name != "gear!" {
+				subT.Fatalf("unexpected body: %+v", resp.Data)
+			}
+		},
+	)
+}
+
+// rateLimitRecorder records the rate limit headers of any response it is embedded into
+type rateLimitRecorder struct {
+	remaining string
+	location  string
+}
+
+func (r *rateLimitRecorder) CaptureHeaders(header http.Header) {
+	r.remaining = header.Get("X-RateLimit-Remaining")
+	r.location = header.Get("Location")
+}
+
+type HeaderCaptureTestResponse struct {
+	Value string `json:"value"`
+	rateLimitRecorder
+	response.ErrorResponse
+}
+
+var _ response.HeaderCapture = new(HeaderCaptureTestResponse)
+
+func TestHeaderCapture(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-RateLimit-Remaining", "0")
+				w.Header().Set("Location", "/retry-later")
+				w.WriteHeader(http.StatusTooManyRequests)
+				_, _ = w.Write([]byte("slow down"))
+			},
+		),
+	)
+	defer srv.Close()
+
+	t.Run(
+		"Captured On Error Status", func(subT *testing.T) {
+			resp := new(HeaderCaptureTestResponse)
+			_ = gkBoot.DoRequest(srv.URL, OptionsTestRequest{}, resp)
+			if resp.remaining != "0" || resp.location != "/retry-later" {
+				subT.Fatalf("expected the headers of the 429 response, got %+v", resp.rateLimitRecorder)
+			}
+			if resp.StatusCode() != http.StatusTooManyRequests {
+				subT.Fatalf("expected status 429, got %d", resp.StatusCode())
+			}
+		},
+	)
+}
+
+type BodyStatusTestResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	response.ErrorResponse
+}
+
+func (b *BodyStatusTestResponse) StatusFromBody(body []byte) (int, bool) {
+	var envelope struct {
+		Code int `json:"code"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil || envelope.Code == 0 {
+		return 0, false
+	}
+	return envelope.Code, true
+}
+
+type BodyStatusError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (b *BodyStatusError) Error() string {
+	return b.Message
+}
+
+func TestBodyStatusCoder(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if r.URL.Query().Get("status") == "404" {
+					_, _ = w.Write([]byte(`{"code":404,"message":"no such widget"}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"message":"fine"}`))
+			},
+		),
+	)
+	defer srv.Close()
+
+	t.Run(
+		"Body Status Is An Error", func(subT *testing.T) {
+			resp := new(BodyStatusTestResponse)
+			_ = gkBoot.DoRequest(srv.URL, OptionsTestRequest{Status: 404}, resp)
+			if resp.StatusCode() != http.StatusNotFound {
+				subT.Fatalf("expected the logical status 404, got %d", resp.StatusCode())
+			}
+			if resp.Failed() == nil {
+				subT.Fatalf("expected the response to be marked as failed")
+			}
+		},
+	)
+
+	t.Run(
+		"Typed Error From Body Status", func(subT *testing.T) {
+			resp := new(BodyStatusTestResponse)
+			err := gkBoot.DoRequestTyped[OptionsTestRequest, BodyStatusTestResponse, *BodyStatusError](
+				srv.URL, OptionsTestRequest{Status: 404}, resp,
+			)
+			var statusErr *BodyStatusError
+			if !errors.As(err, &statusErr) || statusErr.Code != 404 {
+				subT.Fatalf("expected a typed error from the body status, got %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"No Body Status Keeps HTTP Status", func(subT *testing.T) {
+			resp := new(BodyStatusTestResponse)
+			err := gkBoot.DoRequest(srv.URL, OptionsTestRequest{Status: 200}, resp)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.StatusCode() != http.StatusOK || resp.Failed() != nil || resp.Message != "fine" {
+				subT.Fatalf("expected a successful response, got %d %v", resp.StatusCode(), resp.Failed())
+			}
+		},
+	)
+}
+
+type AcceptableCodesTestResponse struct {
+	ID string `json:"id"`
+	response.ErrorResponse
+}
+
+func (a *AcceptableCodesTestResponse) AcceptableCodes() []int {
+	return []int{http.StatusCreated, http.StatusAccepted, http.StatusNoContent}
+}
+
+func TestAcceptableCodes(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Query().Get("status") {
+				case "201":
+					w.WriteHeader(http.StatusCreated)
+					_, _ = w.Write([]byte(`{"id":"w-1"}`))
+				case "204":
+					w.WriteHeader(http.StatusNoContent)
+				default:
+					_, _ = w.Write([]byte(`{"id":"w-0"}`))
+				}
+			},
+		),
+	)
+	defer srv.Close()
+
+	t.Run(
+		"201 Is Success", func(subT *testing.T) {
+			resp := new(AcceptableCodesTestResponse)
+			err := gkBoot.DoRequest(srv.URL, OptionsTestRequest{Status: 201}, resp)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.Failed() != nil || resp.StatusCode() != http.StatusCreated || resp.ID != "w-1" {
+				subT.Fatalf("expected a successful 201, got %d %v '%s'", resp.StatusCode(), resp.Failed(), resp.ID)
+			}
+		},
+	)
+
+	t.Run(
+		"204 Skips Decoding", func(subT *testing.T) {
+			resp := new(AcceptableCodesTestResponse)
+			err := gkBoot.DoRequest(srv.URL, OptionsTestRequest{Status: 204}, resp)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.Failed() != nil || resp.StatusCode() != http.StatusNoContent {
+				subT.Fatalf("expected a successful 204, got %d %v", resp.StatusCode(), resp.Failed())
+			}
+		},
+	)
+
+	t.Run(
+		"200 Is Not Assumed", func(subT *testing.T) {
+			resp := new(AcceptableCodesTestResponse)
+			_ = gkBoot.DoRequest(srv.URL, OptionsTestRequest{Status: 200}, resp)
+			if resp.Failed() == nil {
+				subT.Fatalf("expected 200 outside of the acceptable codes to be marked as failed")
+			}
+		},
+	)
+
+	t.Run(
+		"Nil Response Accepts 2xx", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest(srv.URL, OptionsTestRequest{Status: 201})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			err = gkBoot.DoGeneratedRequest[any](r, nil)
+			if err != nil {
+				subT.Fatalf("expected a 201 to succeed without a response object, got %s", err)
+			}
+		},
+	)
+}
+
+type NoContentTestResponse struct {
+	response.BasicResponse
+	Value string `json:"value"`
+}
+
+func TestNoContent(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Query().Get("status") {
+				case "204":
+					w.WriteHeader(http.StatusNoContent)
+				case "500":
+					w.WriteHeader(http.StatusInternalServerError)
+				default:
+					w.Header().Set("Content-Type", "application/json")
+				}
+			},
+		),
+	)
+	defer srv.Close()
+
+	t.Run(
+		"204 With Response Object", func(subT *testing.T) {
+			resp := &NoContentTestResponse{Value: "untouched"}
+			err := gkBoot.DoRequest(srv.URL, OptionsTestRequest{Status: 204}, resp)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.StatusCode() != http.StatusNoContent || resp.Value != "untouched" {
+				subT.Fatalf("expected status 204 and no decoding, got %d '%s'", resp.StatusCode(), resp.Value)
+			}
+		},
+	)
+
+	t.Run(
+		"204 Without Response Object", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest(srv.URL, OptionsTestRequest{Status: 204})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			err = gkBoot.DoGeneratedRequest[NoContentTestResponse](r, nil)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Empty 200 Body", func(subT *testing.T) {
+			resp := new(NoContentTestResponse)
+			err := gkBoot.DoRequest(srv.URL, OptionsTestRequest{Status: 200}, resp)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.StatusCode() != http.StatusOK {
+				subT.Fatalf("expected status 200, got %d", resp.StatusCode())
+			}
+		},
+	)
+
+	t.Run(
+		"Empty Error Body Still Decoded", func(subT *testing.T) {
+			err := gkBoot.DoRequest(srv.URL, OptionsTestRequest{Status: 500}, new(NoContentTestResponse))
+			if err == nil {
+				subT.Fatalf("expected the empty 500 body to fail decoding")
+			}
+		},
+	)
+}
+
+func TestDecodeError(t *testing.T) {
+	long := "<html>" + strings.Repeat("x", 400) + "</html>"
+
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("X-Upstream", "v2")
+				if r.URL.Query().Get("status") == "1" {
+					_, _ = w.Write([]byte(long))
+					return
+				}
+				_, _ = w.Write([]byte(`{"value": 5}`))
+			},
+		),
+	)
+	defer srv.Close()
+
+	t.Run(
+		"Raw Response Kept", func(subT *testing.T) {
+			resp := new(OptionsTestResponse)
+			err := gkBoot.DoRequest(srv.URL, OptionsTestRequest{}, resp)
+
+			var decodeErr *gkBoot.DecodeError
+			if !errors.As(err, &decodeErr) {
+				subT.Fatalf("expected a DecodeError, got %v", err)
+			}
+			if decodeErr.StatusCode != http.StatusOK || decodeErr.Header.Get("X-Upstream") != "v2" {
+				subT.Fatalf("expected the status and headers, got %d %v", decodeErr.StatusCode, decodeErr.Header)
+			}
+			if string(decodeErr.Body) != `{"value": 5}` {
+				subT.Fatalf("expected the raw body, got %s", decodeErr.Body)
+			}
+			var typeErr *json.UnmarshalTypeError
+			if !errors.As(err, &typeErr) {
+				subT.Fatalf("expected the decoder error to be unwrapped, got %v", err)
+			}
+			if !strings.Contains(err.Error(), `{\"value\": 5}`) {
+				subT.Fatalf("expected the body quoted in the message, got %s", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Long Body Truncated In Message", func(subT *testing.T) {
+			resp := new(OptionsTestResponse)
+			err := gkBoot.DoRequest(srv.URL, OptionsTestRequest{Status: 1}, resp)
+
+			var decodeErr *gkBoot.DecodeError
+			if !errors.As(err, &decodeErr) {
+				subT.Fatalf("expected a DecodeError, got %v", err)
+			}
+			if string(decodeErr.Body) != long {
+				subT.Fatalf("expected the complete body, got %d bytes", len(decodeErr.Body))
+			}
+			if strings.Contains(err.Error(), "</html>") || !strings.HasSuffix(err.Error(), `"...`) {
+				subT.Fatalf("expected a truncated body in the message, got %s", err)
+			}
+		},
+	)
+}
+
+type ErredCaptureTestResponse struct {
+	Data []byte
+	response.ErrorResponse
+}
+
+func (e *ErredCaptureTestResponse) Capture(reader io.Reader) (err error) {
+	e.Data, err = io.ReadAll(reader)
+	return err
+}
+
+type CodedCaptureTestResponse struct {
+	Code int
+	Data []byte
+}
+
+func (c *CodedCaptureTestResponse) CaptureWithCode(code int, reader io.Reader) (err error) {
+	c.Code = code
+	c.Data, err = io.ReadAll(reader)
+	return err
+}
+
+func TestCaptureErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("status") == "500" {
+					w.WriteHeader(http.StatusInternalServerError)
+					_, _ = w.Write([]byte("database unavailable"))
+					return
+				}
+				_, _ = w.Write([]byte("report contents"))
+			},
+		),
+	)
+	defer srv.Close()
+
+	t.Run(
+		"Error Body Not Captured", func(subT *testing.T) {
+			resp := new(ErredCaptureTestResponse)
+			err := gkBoot.DoRequest(srv.URL, OptionsTestRequest{Status: 500}, resp)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.Data != nil {
+				subT.Fatalf("expected the error body not to be captured, got %q", resp.Data)
+			}
+			if resp.StatusCode() != http.StatusInternalServerError ||
+				!strings.Contains(resp.Error(), "database unavailable") {
+				subT.Fatalf("expected the 500 body in the error, got %d %q", resp.StatusCode(), resp.Error())
+			}
+		},
+	)
+
+	t.Run(
+		"Success Captured", func(subT *testing.T) {
+			resp := new(ErredCaptureTestResponse)
+			err := gkBoot.DoRequest(srv.URL, OptionsTestRequest{}, resp)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if string(resp.Data) != "report contents" || resp.Error() != "" {
+				subT.Fatalf("expected the body captured without error, got %q %q", resp.Data, resp.Error())
+			}
+		},
+	)
+
+	t.Run(
+		"Captured With Code", func(subT *testing.T) {
+			resp := new(CodedCaptureTestResponse)
+			err := gkBoot.DoRequest(srv.URL, OptionsTestRequest{Status: 500}, resp)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.Code != http.StatusInternalServerError || string(resp.Data) != "database unavailable" {
+				subT.Fatalf("expected the 500 body captured with its code, got %d %q", resp.Code, resp.Data)
+			}
+		},
+	)
+}
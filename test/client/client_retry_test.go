@@ -0,0 +1,308 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yomiji/gkBoot"
+	"github.com/yomiji/gkBoot/request"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run(
+		"Delay Seconds", func(subT *testing.T) {
+			wait, ok := gkBoot.ParseRetryAfter("120", now, 0)
+			if !ok || wait != 2*time.Minute {
+				subT.Fatalf("expected 2m wait, got %s (%t)", wait, ok)
+			}
+		},
+	)
+
+	t.Run(
+		"HTTP Date", func(subT *testing.T) {
+			value := now.Add(90 * time.Second).Format(http.TimeFormat)
+			wait, ok := gkBoot.ParseRetryAfter(value, now, 0)
+			if !ok || wait != 90*time.Second {
+				subT.Fatalf("expected 90s wait, got %s (%t)", wait, ok)
+			}
+		},
+	)
+
+	t.Run(
+		"Past Date Is Zero", func(subT *testing.T) {
+			value := now.Add(-time.Hour).Format(http.TimeFormat)
+			wait, ok := gkBoot.ParseRetryAfter(value, now, 0)
+			if !ok || wait != 0 {
+				subT.Fatalf("expected zero wait, got %s (%t)", wait, ok)
+			}
+		},
+	)
+
+	t.Run(
+		"Clamped To Max Delay", func(subT *testing.T) {
+			wait, ok := gkBoot.ParseRetryAfter("3600", now, 10*time.Second)
+			if !ok || wait != 10*time.Second {
+				subT.Fatalf("expected 10s wait, got %s (%t)", wait, ok)
+			}
+		},
+	)
+
+	t.Run(
+		"Invalid Value", func(subT *testing.T) {
+			if _, ok := gkBoot.ParseRetryAfter("soon", now, 0); ok {
+				subT.Fatalf("expected parse failure")
+			}
+		},
+	)
+}
+
+type RetryTestBody struct {
+	Name string `json:"name"`
+}
+
+type RetryTestRequest struct {
+	Body RetryTestBody `request:"form"`
+}
+
+func (rt RetryTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "RetryTest",
+		Method:      request.PUT,
+		Path:        "/retry",
+		Description: "A test of request retries",
+	}
+}
+
+// newFlakyTestServer
+//
+// fails the first failures requests with a 503, recording every body received
+func newFlakyTestServer(failures int32, attempts *atomic.Int32, bodies *[]string) *httptest.Server {
+	return httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				*bodies = append(*bodies, string(body))
+				if attempts.Add(1) <= failures {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				_, _ = w.Write([]byte(`{"value":"ok"}`))
+			},
+		),
+	)
+}
+
+func TestRetryPolicy(t *testing.T) {
+	policy := gkBoot.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond, Jitter: 0.5}
+
+	t.Run(
+		"Retries And Replays Body", func(subT *testing.T) {
+			var attempts atomic.Int32
+			var bodies []string
+			srv := newFlakyTestServer(2, &attempts, &bodies)
+			defer srv.Close()
+
+			r, err := gkBoot.GenerateClientRequest(srv.URL, RetryTestRequest{Body: RetryTestBody{Name: "widget"}})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			resp := new(OptionsTestResponse)
+			err = gkBoot.DoGeneratedRequestWithRetry(r, resp, policy)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if attempts.Load() != 3 || resp.Value != "ok" {
+				subT.Fatalf("expected success on the third attempt, got %d attempts and '%s'", attempts.Load(), resp.Value)
+			}
+			for _, body := range bodies {
+				if !strings.Contains(body, `"name":"widget"`) {
+					subT.Fatalf("expected the body on every attempt, got %q", bodies)
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"Gives Up After Max Attempts", func(subT *testing.T) {
+			var attempts atomic.Int32
+			var bodies []string
+			srv := newFlakyTestServer(5, &attempts, &bodies)
+			defer srv.Close()
+
+			resp := new(OptionsTestResponse)
+			_ = gkBoot.DoRequestWithOptions(
+				srv.URL, RetryTestRequest{}, resp, gkBoot.WithRetryPolicy(policy),
+			)
+			if attempts.Load() != 3 {
+				subT.Fatalf("expected 3 attempts, got %d", attempts.Load())
+			}
+			if resp.StatusCode() != http.StatusServiceUnavailable {
+				subT.Fatalf("expected the last 503 to be returned, got %d", resp.StatusCode())
+			}
+		},
+	)
+
+	t.Run(
+		"Non Idempotent Not Retried", func(subT *testing.T) {
+			var attempts atomic.Int32
+			var bodies []string
+			srv := newFlakyTestServer(1, &attempts, &bodies)
+			defer srv.Close()
+
+			r, _ := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader("{}"))
+			_ = gkBoot.DoGeneratedRequestWithRetry[any](r, nil, policy)
+			if attempts.Load() != 1 {
+				subT.Fatalf("expected a single attempt for POST, got %d", attempts.Load())
+			}
+		},
+	)
+
+	t.Run(
+		"Stops At Context Deadline", func(subT *testing.T) {
+			var attempts atomic.Int32
+			var bodies []string
+			srv := newFlakyTestServer(5, &attempts, &bodies)
+			defer srv.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			slow := gkBoot.RetryPolicy{MaxAttempts: 5, BaseDelay: time.Second}
+			start := time.Now()
+			resp := new(OptionsTestResponse)
+			_ = gkBoot.DoRequestWithContext(ctx, srv.URL, RetryTestRequest{}, resp, gkBoot.WithRetryPolicy(slow))
+			if attempts.Load() != 1 {
+				subT.Fatalf("expected no retry past the deadline, got %d attempts", attempts.Load())
+			}
+			if time.Since(start) > time.Second {
+				subT.Fatalf("expected to stop early, took %s", time.Since(start))
+			}
+		},
+	)
+}
+
+func TestRetryBudget(t *testing.T) {
+	var attempts atomic.Int32
+	var bodies []string
+	srv := newFlakyTestServer(1000, &attempts, &bodies)
+	defer srv.Close()
+
+	policy := gkBoot.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+	budget := gkBoot.NewRetryBudget(4, 0)
+
+	t.Run(
+		"Exhausted Under Many Failures", func(subT *testing.T) {
+			for i := 0; i < 10; i++ {
+				resp := new(OptionsTestResponse)
+				_ = gkBoot.DoRequestWithOptions(
+					srv.URL, RetryTestRequest{}, resp, gkBoot.WithRetryPolicy(policy), gkBoot.WithRetryBudget(budget),
+				)
+				if resp.StatusCode() != http.StatusServiceUnavailable {
+					subT.Fatalf("expected the original 503, got %d", resp.StatusCode())
+				}
+			}
+			// ten first attempts plus the four retries the budget allowed
+			if attempts.Load() != 14 {
+				subT.Fatalf("expected 14 attempts, got %d", attempts.Load())
+			}
+			if budget.Allow() {
+				subT.Fatalf("expected the budget to remain exhausted")
+			}
+		},
+	)
+
+	t.Run(
+		"Refills Over Time", func(subT *testing.T) {
+			refilling := gkBoot.NewRetryBudget(1, 1000)
+			if !refilling.Allow() {
+				subT.Fatalf("expected a full budget")
+			}
+			time.Sleep(5 * time.Millisecond)
+			if !refilling.Allow() {
+				subT.Fatalf("expected the budget to refill")
+			}
+		},
+	)
+}
+
+type IdempotentRetryTestRequest struct {
+	Body RetryTestBody `request:"form"`
+}
+
+func (it IdempotentRetryTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "IdempotentRetryTest",
+		Method:      request.POST,
+		Path:        "/retry",
+		Description: "A test of retrying non-idempotent requests",
+	}
+}
+
+func TestIdempotentRetries(t *testing.T) {
+	t.Run(
+		"GET Retried", func(subT *testing.T) {
+			var attempts atomic.Int32
+			var bodies []string
+			srv := newFlakyTestServer(1, &attempts, &bodies)
+			defer srv.Close()
+
+			resp := new(OptionsTestResponse)
+			err := gkBoot.DoRequestWithOptions(srv.URL, OptionsTestRequest{}, resp, gkBoot.WithIdempotentRetries())
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if attempts.Load() != 2 || resp.Value != "ok" {
+				subT.Fatalf("expected success on the second attempt, got %d attempts", attempts.Load())
+			}
+		},
+	)
+
+	t.Run(
+		"POST Not Retried", func(subT *testing.T) {
+			var attempts atomic.Int32
+			var bodies []string
+			srv := newFlakyTestServer(1, &attempts, &bodies)
+			defer srv.Close()
+
+			resp := new(OptionsTestResponse)
+			_ = gkBoot.DoRequestWithOptions(
+				srv.URL, IdempotentRetryTestRequest{}, resp, gkBoot.WithIdempotentRetries(),
+			)
+			if attempts.Load() != 1 {
+				subT.Fatalf("expected a single attempt for POST, got %d", attempts.Load())
+			}
+		},
+	)
+
+	t.Run(
+		"POST With Idempotency Key Retried", func(subT *testing.T) {
+			var attempts atomic.Int32
+			var bodies []string
+			srv := newFlakyTestServer(1, &attempts, &bodies)
+			defer srv.Close()
+
+			resp := new(OptionsTestResponse)
+			err := gkBoot.DoRequestWithOptions(
+				srv.URL, IdempotentRetryTestRequest{Body: RetryTestBody{Name: "order"}}, resp,
+				gkBoot.WithIdempotentRetries(), gkBoot.WithIdempotencyKey("order-1"),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if attempts.Load() != 2 || resp.Value != "ok" {
+				subT.Fatalf("expected success on the second attempt, got %d attempts", attempts.Load())
+			}
+			if bodies[1] != bodies[0] {
+				subT.Fatalf("expected the body replayed, got %q", bodies)
+			}
+		},
+	)
+}
@@ -0,0 +1,91 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/yomiji/gkBoot"
+)
+
+// requiredKeysValidator
+//
+// a stand-in for a JSON Schema library that only understands the 'required' keyword
+func requiredKeysValidator(schema, body []byte) error {
+	var parsedSchema struct {
+		Required []string `json:"required"`
+	}
+	if err := json.Unmarshal(schema, &parsedSchema); err != nil {
+		return err
+	}
+
+	var document map[string]any
+	if err := json.Unmarshal(body, &document); err != nil {
+		return err
+	}
+
+	var missing []string
+	for _, key := range parsedSchema.Required {
+		if _, ok := document[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", errSchemaMismatch, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+var errSchemaMismatch = errors.New("missing required properties")
+
+func TestJSONSchema(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("status") == "201" {
+					_, _ = w.Write([]byte(`{"unexpected":"shape"}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"value":"ok"}`))
+			},
+		),
+	)
+	defer srv.Close()
+
+	schema := []byte(`{"type":"object","required":["value"]}`)
+
+	t.Run(
+		"Conforming Response", func(subT *testing.T) {
+			resp := new(OptionsTestResponse)
+			err := gkBoot.DoRequestWithOptions(
+				srv.URL, OptionsTestRequest{Status: 200}, resp, gkBoot.WithJSONSchema(schema, requiredKeysValidator),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.Value != "ok" {
+				subT.Fatalf("expected decoded value 'ok', got '%s'", resp.Value)
+			}
+		},
+	)
+
+	t.Run(
+		"Malformed Response Rejected", func(subT *testing.T) {
+			resp := new(OptionsTestResponse)
+			err := gkBoot.DoRequestWithOptions(
+				srv.URL, OptionsTestRequest{Status: 201}, resp, gkBoot.WithJSONSchema(schema, requiredKeysValidator),
+			)
+			if !errors.Is(err, errSchemaMismatch) {
+				subT.Fatalf("expected schema mismatch, got %v", err)
+			}
+			if !strings.Contains(err.Error(), "value") {
+				subT.Fatalf("expected the error to name the missing property, got %s", err)
+			}
+		},
+	)
+}
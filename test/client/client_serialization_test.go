@@ -0,0 +1,814 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yomiji/gkBoot"
+	"github.com/yomiji/gkBoot/request"
+)
+
+type DelimiterTestRequest struct {
+	Comma  []int    `request:"query" alias:"comma"`
+	Space  []string `request:"query" alias:"space" delimiter:"space"`
+	Pipe   []string `request:"query" alias:"pipe" delimiter:"pipe"`
+	Multi  []int    `request:"query" alias:"id" delimiter:"multi"`
+	Header []string `request:"header" alias:"X-Tags" delimiter:"multi"`
+	Joined []string `request:"header" alias:"X-Joined" delimiter:"pipe"`
+}
+
+func (d DelimiterTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "DelimiterTest",
+		Method:      request.GET,
+		Path:        "/delimiters",
+		Description: "A test of slice delimiters",
+	}
+}
+
+func TestSliceDelimiters(t *testing.T) {
+	req := DelimiterTestRequest{
+		Comma:  []int{1, 2},
+		Space:  []string{"a", "b"},
+		Pipe:   []string{"c", "d"},
+		Multi:  []int{3, 4, 5},
+		Header: []string{"x", "y"},
+		Joined: []string{"p", "q"},
+	}
+
+	r, err := gkBoot.GenerateClientRequest("http://localhost:8080", req)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	query := r.URL.Query()
+
+	t.Run(
+		"Default Comma", func(subT *testing.T) {
+			if query.Get("comma") != "1,2" {
+				subT.Fatalf("expected '1,2', got '%s'", query.Get("comma"))
+			}
+		},
+	)
+
+	t.Run(
+		"Space And Pipe", func(subT *testing.T) {
+			if query.Get("space") != "a b" {
+				subT.Fatalf("expected 'a b', got '%s'", query.Get("space"))
+			}
+			if query.Get("pipe") != "c|d" {
+				subT.Fatalf("expected 'c|d', got '%s'", query.Get("pipe"))
+			}
+			if r.Header.Get("X-Joined") != "p|q" {
+				subT.Fatalf("expected 'p|q', got '%s'", r.Header.Get("X-Joined"))
+			}
+		},
+	)
+
+	t.Run(
+		"Multi Repeats Keys", func(subT *testing.T) {
+			if ids := query["id"]; len(ids) != 3 || ids[0] != "3" || ids[1] != "4" || ids[2] != "5" {
+				subT.Fatalf("expected repeated id keys, got %v", ids)
+			}
+			if tags := r.Header.Values("X-Tags"); len(tags) != 2 || tags[0] != "x" || tags[1] != "y" {
+				subT.Fatalf("expected repeated X-Tags headers, got %v", tags)
+			}
+		},
+	)
+}
+
+type TimeFormatTestRequest struct {
+	Day     time.Time  `request:"path"`
+	Since   time.Time  `request:"query" alias:"since"`
+	Until   *time.Time `request:"query" alias:"until" timeFormat:"unix"`
+	Stamp   time.Time  `request:"header" alias:"X-Stamp" timeFormat:"unixMilli"`
+	Missing *time.Time `request:"query" alias:"missing"`
+}
+
+func (tf TimeFormatTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "TimeFormatTest",
+		Method:      request.GET,
+		Path:        "/days/{Day}",
+		Description: "A test of time.Time serialization",
+	}
+}
+
+type TimeLayoutTestRequest struct {
+	Day time.Time `request:"path" timeFormat:"2006-01-02"`
+}
+
+func (tl TimeLayoutTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "TimeLayoutTest",
+		Method:      request.GET,
+		Path:        "/days/{Day}",
+		Description: "A test of time.Time layouts",
+	}
+}
+
+func TestTimeFormat(t *testing.T) {
+	moment := time.Date(2024, time.March, 5, 14, 30, 0, 0, time.UTC)
+
+	t.Run(
+		"Default And Unix", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest(
+				"http://localhost:8080", TimeFormatTestRequest{Day: moment, Since: moment, Until: &moment, Stamp: moment},
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if r.URL.Path != "/days/2024-03-05T14:30:00Z" {
+				subT.Fatalf("expected an unquoted RFC3339 path, got '%s'", r.URL.Path)
+			}
+			query := r.URL.Query()
+			if query.Get("since") != "2024-03-05T14:30:00Z" {
+				subT.Fatalf("expected an unquoted RFC3339 query value, got '%s'", query.Get("since"))
+			}
+			if query.Get("until") != "1709649000" {
+				subT.Fatalf("expected unix seconds, got '%s'", query.Get("until"))
+			}
+			if r.Header.Get("X-Stamp") != "1709649000000" {
+				subT.Fatalf("expected unix milliseconds, got '%s'", r.Header.Get("X-Stamp"))
+			}
+			if query.Get("missing") != "" {
+				subT.Fatalf("expected an empty value for a nil time, got '%s'", query.Get("missing"))
+			}
+		},
+	)
+
+	t.Run(
+		"Layout Tag", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest("http://localhost:8080", TimeLayoutTestRequest{Day: moment})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if r.URL.Path != "/days/2024-03-05" {
+				subT.Fatalf("expected the tagged layout, got '%s'", r.URL.Path)
+			}
+		},
+	)
+}
+
+type CookieSliceTestRequest struct {
+	Tokens  []string       `request:"cookie" alias:"token"`
+	Cookies []*http.Cookie `request:"cookie"`
+}
+
+func (c CookieSliceTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "CookieSliceTest",
+		Method:      request.GET,
+		Path:        "/cookies",
+		Description: "A test of slice cookie fields",
+	}
+}
+
+func TestCookieSlices(t *testing.T) {
+	t.Run(
+		"String Slice", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest(
+				"http://localhost:8080", CookieSliceTestRequest{Tokens: []string{"a", "b", "c"}},
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			cookies := r.Cookies()
+			if len(cookies) != 3 {
+				subT.Fatalf("expected three cookies, got %v", cookies)
+			}
+			for i, value := range []string{"a", "b", "c"} {
+				if cookies[i].Name != "token" || cookies[i].Value != value {
+					subT.Fatalf("unexpected cookie %d: %s", i, cookies[i])
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"Cookie Slice", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest(
+				"http://localhost:8080", CookieSliceTestRequest{
+					Cookies: []*http.Cookie{{Name: "session", Value: "s1"}, nil, {Name: "csrf", Value: "c1"}},
+				},
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if session, err := r.Cookie("session"); err != nil || session.Value != "s1" {
+				subT.Fatalf("expected session cookie s1, got %v (%v)", session, err)
+			}
+			if csrf, err := r.Cookie("csrf"); err != nil || csrf.Value != "c1" {
+				subT.Fatalf("expected csrf cookie c1, got %v (%v)", csrf, err)
+			}
+			if len(r.Cookies()) != 2 {
+				subT.Fatalf("expected two cookies, got %v", r.Cookies())
+			}
+		},
+	)
+}
+
+type MapQueryTestRequest struct {
+	Filter  map[string]string         `request:"query" alias:"filter"`
+	Extra   map[string]int            `request:"query" alias:"extra" mapStyle:"form"`
+	Nested  map[string]map[string]int `request:"query" alias:"nested"`
+	Missing map[string]string         `request:"query" alias:"missing"`
+}
+
+func (m MapQueryTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "MapQueryTest",
+		Method:      request.GET,
+		Path:        "/search",
+		Description: "A test of map query expansion",
+	}
+}
+
+func TestMapQueryExpansion(t *testing.T) {
+	req := MapQueryTestRequest{
+		Filter: map[string]string{"status": "open", "owner": "alice", "label": "bug"},
+		Extra:  map[string]int{"page": 2, "limit": 10},
+		Nested: map[string]map[string]int{"range": {"min": 1, "max": 5}},
+	}
+
+	t.Run(
+		"Deterministic Expansion", func(subT *testing.T) {
+			for i := 0; i < 5; i++ {
+				r, err := gkBoot.GenerateClientRequest("http://localhost:8080", req)
+				if err != nil {
+					subT.Fatalf("unexpected error: %s", err)
+				}
+				expected := "filter%5Blabel%5D=bug&filter%5Bowner%5D=alice&filter%5Bstatus%5D=open&limit=10&" +
+					"nested%5Brange%5D=%7B%22max%22%3A5%2C%22min%22%3A1%7D&page=2"
+				if r.URL.RawQuery != expected {
+					subT.Fatalf("unexpected query %s", r.URL.RawQuery)
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"Nested Values JSON Encoded", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest("http://localhost:8080", req)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if r.URL.Query().Get("nested[range]") != `{"max":5,"min":1}` {
+				subT.Fatalf("expected the nested map as JSON, got '%s'", r.URL.Query().Get("nested[range]"))
+			}
+			if _, ok := r.URL.Query()["missing"]; ok {
+				subT.Fatalf("expected a nil map to add no parameters")
+			}
+		},
+	)
+}
+
+type ObjectQueryRange struct {
+	Min int `json:"min"`
+	Max int `json:"max,omitempty"`
+}
+
+type ObjectQueryPaging struct {
+	Page int `json:"page"`
+}
+
+type ObjectQueryFilter struct {
+	ObjectQueryPaging
+	Status  string            `json:"status"`
+	Labels  []string          `json:"labels" delimiter:"multi"`
+	Price   ObjectQueryRange  `alias:"price"`
+	Created *ObjectQueryRange `json:"created"`
+	Raw     ObjectQueryRange  `json:"raw" objectStyle:"json"`
+	Secret  string            `json:"-"`
+}
+
+type ObjectQueryTestRequest struct {
+	Filter  ObjectQueryFilter  `request:"query" alias:"filter"`
+	Dotted  *ObjectQueryRange  `request:"query" alias:"dotted" objectStyle:"dotted"`
+	AsJSON  ObjectQueryRange   `request:"query" alias:"json" objectStyle:"json"`
+	Missing *ObjectQueryFilter `request:"query" alias:"missing"`
+}
+
+func (o ObjectQueryTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "ObjectQueryTest",
+		Method:      request.GET,
+		Path:        "/search",
+		Description: "A test of struct query expansion",
+	}
+}
+
+func TestObjectQueryExpansion(t *testing.T) {
+	req := ObjectQueryTestRequest{
+		Filter: ObjectQueryFilter{
+			ObjectQueryPaging: ObjectQueryPaging{Page: 2},
+			Status:            "open",
+			Labels:            []string{"bug", "ui"},
+			Price:             ObjectQueryRange{Min: 10, Max: 20},
+			Raw:               ObjectQueryRange{Min: 1},
+			Secret:            "hidden",
+		},
+		Dotted: &ObjectQueryRange{Min: 3, Max: 4},
+		AsJSON: ObjectQueryRange{Min: 5, Max: 6},
+	}
+
+	r, err := gkBoot.GenerateClientRequest("http://localhost:8080", req)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := url.Values{
+		"filter[page]":       {"2"},
+		"filter[status]":     {"open"},
+		"filter[labels]":     {"bug", "ui"},
+		"filter[price][min]": {"10"},
+		"filter[price][max]": {"20"},
+		"filter[raw]":        {`{"min":1}`},
+		"dotted.min":         {"3"},
+		"dotted.max":         {"4"},
+		"json":               {`{"min":5,"max":6}`},
+	}
+
+	if !reflect.DeepEqual(r.URL.Query(), expected) {
+		t.Fatalf("expected query %v, got %v", expected, r.URL.Query())
+	}
+}
+
+type NullModeTestRequest struct {
+	Explicit *string `request:"query" alias:"explicit" nullMode:"explicit"`
+	Omitted  *string `request:"query" alias:"omitted"`
+}
+
+func (n NullModeTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "NullModeTest",
+		Method:      request.GET,
+		Path:        "/graphql",
+		Description: "A test of explicit null query parameters",
+	}
+}
+
+func TestNullMode(t *testing.T) {
+	t.Run(
+		"Explicit Null And Omitted", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest("http://localhost:8080", NullModeTestRequest{})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if r.URL.RawQuery != "explicit=null" {
+				subT.Fatalf("expected only explicit=null, got %s", r.URL.RawQuery)
+			}
+		},
+	)
+
+	t.Run(
+		"Set Values Sent As Is", func(subT *testing.T) {
+			value := "v"
+			r, err := gkBoot.GenerateClientRequest(
+				"http://localhost:8080", NullModeTestRequest{Explicit: &value, Omitted: &value},
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if r.URL.RawQuery != "explicit=v&omitted=v" {
+				subT.Fatalf("unexpected query %s", r.URL.RawQuery)
+			}
+		},
+	)
+}
+
+type PathEscapeTestRequest struct {
+	ID  string `request:"path"`
+	Raw string `request:"path" rawPath:"true"`
+}
+
+func (p PathEscapeTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "PathEscapeTest",
+		Method:      request.GET,
+		Path:        "/items/{ID}/files/{Raw}",
+		Description: "A test of path value escaping",
+	}
+}
+
+func TestPathEscaping(t *testing.T) {
+	var requestURI string
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				requestURI = r.RequestURI
+				w.WriteHeader(http.StatusOK)
+			},
+		),
+	)
+	defer srv.Close()
+
+	t.Run(
+		"Escaped By Default", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest(srv.URL, PathEscapeTestRequest{ID: "a/b c?d", Raw: "plain"})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if r.URL.EscapedPath() != "/items/a%2Fb%20c%3Fd/files/plain" {
+				subT.Fatalf("expected an escaped path segment, got '%s'", r.URL.EscapedPath())
+			}
+			if r.URL.Path != "/items/a/b c?d/files/plain" {
+				subT.Fatalf("expected the decoded path to hold the value, got '%s'", r.URL.Path)
+			}
+
+			resp, err := http.DefaultClient.Do(r)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			resp.Body.Close()
+			if requestURI != "/items/a%2Fb%20c%3Fd/files/plain" {
+				subT.Fatalf("expected the escaped segment on the wire, got '%s'", requestURI)
+			}
+		},
+	)
+
+	t.Run(
+		"Raw Opt Out", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest(srv.URL, PathEscapeTestRequest{ID: "1", Raw: "dir/sub%2Fname"})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if r.URL.EscapedPath() != "/items/1/files/dir/sub%2Fname" {
+				subT.Fatalf("expected the raw value verbatim, got '%s'", r.URL.EscapedPath())
+			}
+		},
+	)
+}
+
+type IntegerFormatTestRequest struct {
+	ID     uint64  `request:"path" format:"hex"`
+	Code   int     `request:"query" alias:"code" format:"%04d"`
+	Mask   uint8   `request:"header" alias:"X-Mask" format:"binary"`
+	Upper  int32   `request:"query" alias:"upper" format:"%X"`
+	Keys   []int   `request:"query" alias:"keys" format:"hex"`
+	Plain  int     `request:"query" alias:"plain"`
+	Ignore float64 `request:"query" alias:"ignore" format:"hex"`
+}
+
+func (i IntegerFormatTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "IntegerFormatTest",
+		Method:      request.GET,
+		Path:        "/objects/{ID}",
+		Description: "A test of integer format tags",
+	}
+}
+
+func TestIntegerFormat(t *testing.T) {
+	r, err := gkBoot.GenerateClientRequest(
+		"http://localhost:8080", IntegerFormatTestRequest{
+			ID: 48879, Code: 7, Mask: 5, Upper: 255, Keys: []int{10, 11}, Plain: 42, Ignore: 1.5,
+		},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if r.URL.Path != "/objects/beef" {
+		t.Fatalf("expected a hex path, got '%s'", r.URL.Path)
+	}
+	query := r.URL.Query()
+	if query.Get("code") != "0007" || query.Get("upper") != "FF" {
+		t.Fatalf("expected the fmt verbs applied, got '%s'", r.URL.RawQuery)
+	}
+	if query.Get("keys") != "a,b" {
+		t.Fatalf("expected hex slice elements, got '%s'", query.Get("keys"))
+	}
+	if query.Get("plain") != "42" || query.Get("ignore") != "1.5" {
+		t.Fatalf("expected base 10 and unformatted floats, got '%s'", r.URL.RawQuery)
+	}
+	if r.Header.Get("X-Mask") != "101" {
+		t.Fatalf("expected a binary header, got '%s'", r.Header.Get("X-Mask"))
+	}
+}
+
+type TrailingSlashTestRequest struct {
+	Path string `json:"-"`
+}
+
+func (t TrailingSlashTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "TrailingSlashTest",
+		Method:      request.GET,
+		Path:        t.Path,
+		Description: "A test of trailing slashes in the route path",
+	}
+}
+
+type TrailingSlashPathTestRequest struct {
+	ID int `request:"path" alias:"id"`
+}
+
+func (t TrailingSlashPathTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "TrailingSlashPathTest",
+		Method:      request.GET,
+		Path:        "/users/{id}/",
+		Description: "A test of a trailing slash after a path placeholder",
+	}
+}
+
+func TestTrailingSlash(t *testing.T) {
+	cases := []struct {
+		name    string
+		baseUrl string
+		request request.HttpRequest
+		url     string
+	}{
+		{
+			"Collection", "http://localhost:8080", TrailingSlashTestRequest{Path: "/users/"},
+			"http://localhost:8080/users/",
+		},
+		{
+			"Without Slash", "http://localhost:8080", TrailingSlashTestRequest{Path: "/users"},
+			"http://localhost:8080/users",
+		},
+		{"Root", "http://localhost:8080/api", TrailingSlashTestRequest{Path: "/"}, "http://localhost:8080/api/"},
+		{
+			"After Placeholder", "http://localhost:8080/api/", TrailingSlashPathTestRequest{ID: 7},
+			"http://localhost:8080/api/users/7/",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(
+			c.name, func(subT *testing.T) {
+				r, err := gkBoot.GenerateClientRequest(c.baseUrl, c.request)
+				if err != nil {
+					subT.Fatalf("unexpected error: %s", err)
+				}
+				if r.URL.String() != c.url {
+					subT.Fatalf("expected url %s, got %s", c.url, r.URL)
+				}
+			},
+		)
+	}
+}
+
+type UnfilledPathTestRequest struct {
+	ID int `request:"path" alias:"id"`
+}
+
+func (u UnfilledPathTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "UnfilledPathTest",
+		Method:      request.GET,
+		Path:        "/users/{id}/{org}/{team}",
+		Description: "A test of path placeholders no field fills",
+	}
+}
+
+func TestUnfilledPathPlaceholders(t *testing.T) {
+	t.Run(
+		"Placeholders Listed", func(subT *testing.T) {
+			_, err := gkBoot.GenerateClientRequest("http://localhost:8080", UnfilledPathTestRequest{ID: 1})
+			if err == nil {
+				subT.Fatalf("expected the unfilled placeholders to fail")
+			}
+			if !strings.Contains(err.Error(), "{org}, {team}") || strings.Contains(err.Error(), "{id},") {
+				subT.Fatalf("expected only the unfilled placeholders listed, got %s", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Value Resembling A Placeholder", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest(
+				"http://localhost:8080", PathEscapeTestRequest{ID: "{other}", Raw: "a"},
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if r.URL.EscapedPath() != "/items/%7Bother%7D/files/a" {
+				subT.Fatalf("expected the escaped braces, got %s", r.URL.EscapedPath())
+			}
+		},
+	)
+}
+
+type QueryRestTestRequest struct {
+	Page    int                 `request:"query" alias:"page"`
+	Filters map[string][]string `request:"queryRest"`
+}
+
+func (q QueryRestTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "QueryRestTest",
+		Method:      request.GET,
+		Path:        "/search",
+		Description: "A test of forwarding arbitrary query params",
+	}
+}
+
+type QueryRestAnyTestRequest struct {
+	Filters *map[string]any `request:"queryRest!"`
+}
+
+func (q QueryRestAnyTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "QueryRestAnyTest",
+		Method:      request.GET,
+		Path:        "/search",
+		Description: "A test of forwarding arbitrary query params of any type",
+	}
+}
+
+func TestQueryRest(t *testing.T) {
+	t.Run(
+		"Forwards Dynamic Params", func(subT *testing.T) {
+			incoming, _ := http.NewRequest(http.MethodGet, "http://proxy/search?tag=b&tag=a&color=red&size=", nil)
+
+			r, err := gkBoot.GenerateClientRequest(
+				"http://localhost:8080", QueryRestTestRequest{Page: 2, Filters: incoming.URL.Query()},
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if r.URL.RawQuery != "color=red&page=2&size=&tag=b&tag=a" {
+				subT.Fatalf("unexpected forwarded query %s", r.URL.RawQuery)
+			}
+		},
+	)
+
+	t.Run(
+		"Nil Map Adds Nothing", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest("http://localhost:8080", QueryRestTestRequest{Page: 1})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if r.URL.RawQuery != "page=1" {
+				subT.Fatalf("unexpected query %s", r.URL.RawQuery)
+			}
+		},
+	)
+
+	t.Run(
+		"Any Values And Required", func(subT *testing.T) {
+			filters := map[string]any{"limit": 5, "ids": []int{3, 1}}
+			r, err := gkBoot.GenerateClientRequest("http://localhost:8080", QueryRestAnyTestRequest{Filters: &filters})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if r.URL.RawQuery != "ids=3&ids=1&limit=5" {
+				subT.Fatalf("unexpected query %s", r.URL.RawQuery)
+			}
+
+			_, err = gkBoot.GenerateClientRequest("http://localhost:8080", QueryRestAnyTestRequest{})
+			if err == nil || !strings.Contains(err.Error(), "Filters") {
+				subT.Fatalf("expected the missing required map to fail, got %v", err)
+			}
+		},
+	)
+}
+
+type EscaperTestRequest struct {
+	Name   string `request:"path"`
+	Search string `request:"query" alias:"q"`
+	Label  string `request:"header" alias:"X-Label" urlEncode:"true"`
+}
+
+func (e EscaperTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "EscaperTest",
+		Method:      request.GET,
+		Path:        "/files/{Name}",
+		Description: "A test of custom escaping",
+	}
+}
+
+func TestEscaper(t *testing.T) {
+	req := EscaperTestRequest{Name: "my file:v1", Search: "a b*c", Label: "x y"}
+
+	t.Run(
+		"Standard Library By Default", func(subT *testing.T) {
+			r, err := gkBoot.GenerateClientRequest("http://localhost:8080", req)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if r.URL.RawQuery != "q=a+b%2Ac" {
+				subT.Fatalf("unexpected query %s", r.URL.RawQuery)
+			}
+			if r.URL.EscapedPath() != "/files/my%20file:v1" {
+				subT.Fatalf("unexpected path %s", r.URL.EscapedPath())
+			}
+			if r.Header.Get("X-Label") != "x+y" {
+				subT.Fatalf("unexpected header %s", r.Header.Get("X-Label"))
+			}
+		},
+	)
+
+	t.Run(
+		"Custom Escaper", func(subT *testing.T) {
+			escape := func(value string) string {
+				return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+			}
+
+			r, err := gkBoot.GenerateClientRequestWithOptions(
+				"http://localhost:8080", req, gkBoot.WithEscaper(escape),
+				gkBoot.WithQueryValues(url.Values{"page size": {"10 20"}}),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if r.URL.RawQuery != "page%20size=10%2020&q=a%20b%2Ac" {
+				subT.Fatalf("unexpected query %s", r.URL.RawQuery)
+			}
+			if r.URL.Query().Get("q") != "a b*c" {
+				subT.Fatalf("expected the escaped value to decode, got %s", r.URL.Query().Get("q"))
+			}
+			if r.URL.EscapedPath() != "/files/my%20file%3Av1" {
+				subT.Fatalf("unexpected path %s", r.URL.EscapedPath())
+			}
+			if r.Header.Get("X-Label") != "x%20y" {
+				subT.Fatalf("unexpected header %s", r.Header.Get("X-Label"))
+			}
+		},
+	)
+
+	t.Run(
+		"RFC 3986", func(subT *testing.T) {
+			if escaped := gkBoot.EscapeRFC3986("a b+c~d/é"); escaped != "a%20b%2Bc~d%2F%C3%A9" {
+				subT.Fatalf("unexpected escaping %s", escaped)
+			}
+		},
+	)
+}
+
+type ExplodeTestRequest struct {
+	IDs      []int    `query:"ids" explode:"true"`
+	Tags     []string `query:"tags" explode:"true"`
+	Pages    []int    `query:"pages" explode:"false"`
+	Names    []string `query:"names" explode:"false"`
+	Default  []string `query:"default"`
+	Override []string `query:"override" explode:"true" delimiter:"pipe"`
+}
+
+func (e ExplodeTestRequest) Info() request.HttpRouteInfo {
+	return request.HttpRouteInfo{
+		Name:        "ExplodeTest",
+		Method:      request.GET,
+		Path:        "/explode",
+		Description: "A test of the OpenAPI explode hint",
+	}
+}
+
+func TestQueryExplode(t *testing.T) {
+	r, err := gkBoot.GenerateClientRequest(
+		"http://localhost:8080", ExplodeTestRequest{
+			IDs:      []int{1, 2},
+			Tags:     []string{"a", "b"},
+			Pages:    []int{3, 4},
+			Names:    []string{"c", "d"},
+			Default:  []string{"e", "f"},
+			Override: []string{"g", "h"},
+		},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	query := r.URL.Query()
+
+	t.Run(
+		"Explode Repeats Keys", func(subT *testing.T) {
+			if ids := query["ids"]; len(ids) != 2 || ids[0] != "1" || ids[1] != "2" {
+				subT.Fatalf("expected repeated ids keys, got %v", ids)
+			}
+			if tags := query["tags"]; len(tags) != 2 || tags[0] != "a" || tags[1] != "b" {
+				subT.Fatalf("expected repeated tags keys, got %v", tags)
+			}
+		},
+	)
+
+	t.Run(
+		"No Explode Joins", func(subT *testing.T) {
+			if pages := query["pages"]; len(pages) != 1 || pages[0] != "3,4" {
+				subT.Fatalf("expected '3,4', got %v", pages)
+			}
+			if names := query["names"]; len(names) != 1 || names[0] != "c,d" {
+				subT.Fatalf("expected 'c,d', got %v", names)
+			}
+		},
+	)
+
+	t.Run(
+		"Default And Delimiter Precedence", func(subT *testing.T) {
+			if values := query["default"]; len(values) != 1 || values[0] != "e,f" {
+				subT.Fatalf("expected the comma default, got %v", values)
+			}
+			if values := query["override"]; len(values) != 1 || values[0] != "g|h" {
+				subT.Fatalf("expected the delimiter tag to win, got %v", values)
+			}
+		},
+	)
+}
@@ -0,0 +1,319 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yomiji/gkBoot"
+)
+
+// newRemoteAddrServer
+//
+// serves the IP the request was received from as the value of the response
+func newRemoteAddrServer() *httptest.Server {
+	return httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				host, _, _ := net.SplitHostPort(r.RemoteAddr)
+				_, _ = w.Write([]byte(`{"value":"` + host + `"}`))
+			},
+		),
+	)
+}
+
+func TestWithLocalAddr(t *testing.T) {
+	srv := newRemoteAddrServer()
+	defer srv.Close()
+
+	t.Run(
+		"Loopback", func(subT *testing.T) {
+			resp := new(OptionsTestResponse)
+			err := gkBoot.DoRequestWithOptions(
+				srv.URL, OptionsTestRequest{Status: 200}, resp, gkBoot.WithLocalAddr(net.ParseIP("127.0.0.1")),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.Value != "127.0.0.1" {
+				subT.Fatalf("expected the request from 127.0.0.1, got '%s'", resp.Value)
+			}
+		},
+	)
+
+	t.Run(
+		"Unsupported Transport", func(subT *testing.T) {
+			client := &http.Client{Transport: new(countingTransport)}
+
+			err := gkBoot.DoRequestWithOptions(
+				srv.URL, OptionsTestRequest{Status: 200}, new(OptionsTestResponse), gkBoot.WithHTTPClient(client),
+				gkBoot.WithLocalAddr(net.ParseIP("127.0.0.1")),
+			)
+			if err == nil || !strings.Contains(err.Error(), "*http.Transport") {
+				subT.Fatalf("expected an unsupported transport error, got %v", err)
+			}
+			if client.Transport.(*countingTransport).calls != 0 {
+				subT.Fatalf("expected no request through the unsupported transport")
+			}
+		},
+	)
+
+	// binding a specific address of a multi-homed host, such as 127.0.0.2 on Linux, depends on the environment
+	t.Run(
+		"Environment Address", func(subT *testing.T) {
+			addr := os.Getenv("GKBOOT_TEST_LOCAL_ADDR")
+			if addr == "" {
+				subT.Skip("GKBOOT_TEST_LOCAL_ADDR not set")
+			}
+
+			resp := new(OptionsTestResponse)
+			err := gkBoot.DoRequestWithOptions(
+				srv.URL, OptionsTestRequest{Status: 200}, resp, gkBoot.WithLocalAddr(net.ParseIP(addr)),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.Value != addr {
+				subT.Fatalf("expected the request from %s, got '%s'", addr, resp.Value)
+			}
+		},
+	)
+}
+
+// newProtoServer
+//
+// serves the protocol of the request as the value of the response over TLS, offering HTTP/2 when enabled
+func newProtoServer(http2 bool) *httptest.Server {
+	srv := httptest.NewUnstartedServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"value":"` + r.Proto + `"}`))
+			},
+		),
+	)
+	srv.EnableHTTP2 = http2
+	srv.StartTLS()
+
+	return srv
+}
+
+func TestProtocolSelection(t *testing.T) {
+	h1 := newProtoServer(false)
+	defer h1.Close()
+
+	h2 := newProtoServer(true)
+	defer h2.Close()
+
+	tlsConfigFor := func(srv *httptest.Server) *tls.Config {
+		pool := x509.NewCertPool()
+		pool.AddCert(srv.Certificate())
+
+		return &tls.Config{RootCAs: pool}
+	}
+
+	t.Run(
+		"TLS Reaches HTTP/1.1 Server", func(subT *testing.T) {
+			resp := new(OptionsTestResponse)
+			err := gkBoot.DoRequestWithOptions(
+				h1.URL, OptionsTestRequest{Status: 200}, resp, gkBoot.WithClientTLS(tlsConfigFor(h1)),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.Value != "HTTP/1.1" {
+				subT.Fatalf("expected HTTP/1.1, got '%s'", resp.Value)
+			}
+		},
+	)
+
+	t.Run(
+		"TLS Negotiates HTTP/2", func(subT *testing.T) {
+			tlsConfig := tlsConfigFor(h2)
+
+			resp := new(OptionsTestResponse)
+			err := gkBoot.DoRequestWithOptions(
+				h2.URL, OptionsTestRequest{Status: 200}, resp, gkBoot.WithClientTLS(tlsConfig),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.Value != "HTTP/2.0" {
+				subT.Fatalf("expected HTTP/2.0, got '%s'", resp.Value)
+			}
+			if len(tlsConfig.NextProtos) != 0 {
+				subT.Fatalf("expected the given TLS configuration left untouched, got %v", tlsConfig.NextProtos)
+			}
+		},
+	)
+
+	t.Run(
+		"Forced HTTP/2", func(subT *testing.T) {
+			resp := new(OptionsTestResponse)
+			err := gkBoot.DoRequestWithOptions(
+				h2.URL, OptionsTestRequest{Status: 200}, resp, gkBoot.WithClientTLS(tlsConfigFor(h2)),
+				gkBoot.WithForceHTTP2(),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.Value != "HTTP/2.0" {
+				subT.Fatalf("expected HTTP/2.0, got '%s'", resp.Value)
+			}
+		},
+	)
+
+	t.Run(
+		"Forced HTTP/2 Fails HTTP/1.1 Server", func(subT *testing.T) {
+			err := gkBoot.DoRequestWithOptions(
+				h1.URL, OptionsTestRequest{Status: 200}, new(OptionsTestResponse),
+				gkBoot.WithClientTLS(tlsConfigFor(h1)), gkBoot.WithForceHTTP2(),
+			)
+			if err == nil {
+				subT.Fatalf("expected an error from a server without HTTP/2")
+			}
+		},
+	)
+}
+
+func TestDefaultTLSConfig(t *testing.T) {
+	srv := newProtoServer(false)
+	defer srv.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+	trusting := &tls.Config{RootCAs: pool}
+
+	t.Run(
+		"Untrusted Without Default", func(subT *testing.T) {
+			err := gkBoot.DoRequestWithOptions(srv.URL, OptionsTestRequest{Status: 200}, new(OptionsTestResponse))
+			if err == nil {
+				subT.Fatalf("expected the test certificate to be untrusted")
+			}
+		},
+	)
+
+	t.Run(
+		"Default Used", func(subT *testing.T) {
+			gkBoot.SetDefaultTLSConfig(trusting)
+			defer gkBoot.SetDefaultTLSConfig(nil)
+
+			if gkBoot.DefaultTLSConfig() != trusting {
+				subT.Fatalf("expected the default TLS configuration to be set")
+			}
+
+			resp := new(OptionsTestResponse)
+			err := gkBoot.DoRequestWithOptions(srv.URL, OptionsTestRequest{Status: 200}, resp)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+
+			r, err := gkBoot.GenerateClientRequest(srv.URL, OptionsTestRequest{Status: 200})
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			err = gkBoot.DoGeneratedRequest(r, new(OptionsTestResponse), gkBoot.HTTP2GlobalCA...)
+			if err != nil {
+				subT.Fatalf("expected a nil TLS configuration to use the default, got %s", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Option Overrides Default", func(subT *testing.T) {
+			gkBoot.SetDefaultTLSConfig(trusting)
+			defer gkBoot.SetDefaultTLSConfig(nil)
+
+			err := gkBoot.DoRequestWithOptions(
+				srv.URL, OptionsTestRequest{Status: 200}, new(OptionsTestResponse),
+				gkBoot.WithClientTLS(&tls.Config{RootCAs: x509.NewCertPool()}),
+			)
+			if err == nil {
+				subT.Fatalf("expected the given TLS configuration to take precedence")
+			}
+		},
+	)
+
+	t.Run(
+		"Concurrent Use", func(subT *testing.T) {
+			defer gkBoot.SetDefaultTLSConfig(nil)
+
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(2)
+				go func() {
+					defer wg.Done()
+					gkBoot.SetDefaultTLSConfig(trusting)
+				}()
+				go func() {
+					defer wg.Done()
+					_ = gkBoot.DefaultTLSConfig()
+				}()
+			}
+			wg.Wait()
+		},
+	)
+}
+
+func TestTransportCacheBounded(t *testing.T) {
+	var mu sync.Mutex
+	open := 0
+	srv := httptest.NewUnstartedServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"value":"ok"}`))
+			},
+		),
+	)
+	srv.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch state {
+		case http.StateNew:
+			open++
+		case http.StateClosed, http.StateHijacked:
+			open--
+		}
+	}
+	srv.StartTLS()
+	defer srv.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+
+	t.Run(
+		"Fresh TLS Configs Do Not Accumulate Connections", func(subT *testing.T) {
+			const calls = 150
+			for i := 0; i < calls; i++ {
+				err := gkBoot.DoRequestWithOptions(
+					srv.URL, OptionsTestRequest{Status: 200}, new(OptionsTestResponse),
+					gkBoot.WithClientTLS(&tls.Config{RootCAs: pool}),
+				)
+				if err != nil {
+					subT.Fatalf("unexpected error: %s", err)
+				}
+			}
+
+			// the server notices the connections closed by evicted transports asynchronously
+			deadline := time.Now().Add(2 * time.Second)
+			for {
+				mu.Lock()
+				current := open
+				mu.Unlock()
+				if current < calls/2 {
+					break
+				}
+				if time.Now().After(deadline) {
+					subT.Fatalf("expected evicted transports to close their connections, %d remain open", current)
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		},
+	)
+}
@@ -0,0 +1,404 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/yomiji/gkBoot"
+	"github.com/yomiji/gkBoot/response"
+)
+
+type APIError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (a *APIError) Error() string {
+	return a.Code + ": " + a.Message
+}
+
+func TestTypedErrors(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Query().Get("status") {
+				case "404":
+					w.Header().Set("Content-Type", "application/problem+json")
+					w.WriteHeader(http.StatusNotFound)
+					_, _ = w.Write([]byte(`{"code":"not_found","message":"no such widget"}`))
+				case "502":
+					w.Header().Set("Content-Type", "text/html")
+					w.WriteHeader(http.StatusBadGateway)
+					_, _ = w.Write([]byte("<html>bad gateway</html>"))
+				case "503":
+					w.WriteHeader(http.StatusServiceUnavailable)
+				default:
+					_, _ = w.Write([]byte(`{"value":"ok"}`))
+				}
+			},
+		),
+	)
+	defer srv.Close()
+
+	t.Run(
+		"Decodes JSON Error", func(subT *testing.T) {
+			resp := new(OptionsTestResponse)
+			err := gkBoot.DoRequestTyped[OptionsTestRequest, OptionsTestResponse, *APIError](
+				srv.URL, OptionsTestRequest{Status: 404}, resp,
+			)
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				subT.Fatalf("expected an *APIError, got %v", err)
+			}
+			if apiErr.Code != "not_found" || apiErr.Message != "no such widget" {
+				subT.Fatalf("unexpected decoded error %+v", apiErr)
+			}
+		},
+	)
+
+	t.Run(
+		"Success Unaffected", func(subT *testing.T) {
+			resp := new(OptionsTestResponse)
+			err := gkBoot.DoRequestTyped[OptionsTestRequest, OptionsTestResponse, *APIError](
+				srv.URL, OptionsTestRequest{Status: 200}, resp,
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.Value != "ok" {
+				subT.Fatalf("expected decoded value 'ok', got '%s'", resp.Value)
+			}
+		},
+	)
+
+	t.Run(
+		"Non JSON Falls Back", func(subT *testing.T) {
+			resp := new(OptionsTestResponse)
+			_ = gkBoot.DoRequestTyped[OptionsTestRequest, OptionsTestResponse, *APIError](
+				srv.URL, OptionsTestRequest{Status: 502}, resp,
+			)
+			if resp.StatusCode() != http.StatusBadGateway || resp.Error() == "" {
+				subT.Fatalf("expected the string error behavior, got status %d", resp.StatusCode())
+			}
+		},
+	)
+
+	t.Run(
+		"Empty Body Falls Back", func(subT *testing.T) {
+			err := gkBoot.DoRequestWithOptions[OptionsTestRequest, any](
+				srv.URL, OptionsTestRequest{Status: 503}, nil, gkBoot.WithErrorType[*APIError](),
+			)
+			var apiErr *APIError
+			if errors.As(err, &apiErr) {
+				subT.Fatalf("expected an empty body not to decode, got %+v", apiErr)
+			}
+			if err == nil {
+				subT.Fatalf("expected the default error for a 503")
+			}
+		},
+	)
+}
+
+// decodeTestFieldErrors
+//
+// decodes a {"errors":[{"field":"...","message":"...","code":"..."}]} payload
+func decodeTestFieldErrors(body []byte) ([]gkBoot.FieldError, error) {
+	var payload struct {
+		Errors []struct {
+			Field   string `json:"field"`
+			Message string `json:"message"`
+			Code    string `json:"code"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+
+	fields := make([]gkBoot.FieldError, 0, len(payload.Errors))
+	for _, e := range payload.Errors {
+		fields = append(fields, gkBoot.FieldError{Field: e.Field, Message: e.Message, Code: e.Code})
+	}
+	return fields, nil
+}
+
+func TestFieldErrors(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Query().Get("status") {
+				case "422":
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusUnprocessableEntity)
+					_, _ = w.Write(
+						[]byte(`{"errors":[` +
+							`{"field":"name","message":"must not be empty","code":"required"},` +
+							`{"field":"address.zip","message":"must be 5 digits","code":"pattern"}]}`),
+					)
+				case "423":
+					w.WriteHeader(http.StatusUnprocessableEntity)
+					_, _ = w.Write([]byte("not json"))
+				default:
+					_, _ = w.Write([]byte(`{"value":"ok"}`))
+				}
+			},
+		),
+	)
+	defer srv.Close()
+
+	t.Run(
+		"Decodes 422 Payload", func(subT *testing.T) {
+			err := gkBoot.DoRequestWithOptions(
+				srv.URL, OptionsTestRequest{Status: 422}, new(OptionsTestResponse),
+				gkBoot.WithFieldErrorsDecoder(decodeTestFieldErrors),
+			)
+			var validationErr *gkBoot.ValidationError
+			if !errors.As(err, &validationErr) {
+				subT.Fatalf("expected a *ValidationError, got %v", err)
+			}
+			if validationErr.StatusCode != http.StatusUnprocessableEntity || len(validationErr.Fields) != 2 {
+				subT.Fatalf("unexpected validation error %+v", validationErr)
+			}
+			if validationErr.Fields[1].Field != "address.zip" || validationErr.Fields[1].Code != "pattern" {
+				subT.Fatalf("unexpected second field error %+v", validationErr.Fields[1])
+			}
+
+			var fieldErr gkBoot.FieldError
+			if !errors.As(err, &fieldErr) || fieldErr.Field != "name" {
+				subT.Fatalf("expected to unwrap the first field error, got %+v", fieldErr)
+			}
+			if !errors.Is(err, validationErr.Fields[1]) {
+				subT.Fatalf("expected the field list to be unwrapped")
+			}
+		},
+	)
+
+	t.Run(
+		"No Response Object", func(subT *testing.T) {
+			err := gkBoot.DoRequestNoResponse(
+				srv.URL, OptionsTestRequest{Status: 422}, gkBoot.WithFieldErrorsDecoder(decodeTestFieldErrors),
+			)
+			var validationErr *gkBoot.ValidationError
+			if !errors.As(err, &validationErr) || len(validationErr.Fields) != 2 {
+				subT.Fatalf("expected a *ValidationError, got %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Undecodable Payload Falls Through", func(subT *testing.T) {
+			resp := new(OptionsTestResponse)
+			err := gkBoot.DoRequestWithOptions(
+				srv.URL, OptionsTestRequest{Status: 423}, resp, gkBoot.WithFieldErrorsDecoder(decodeTestFieldErrors),
+			)
+			var validationErr *gkBoot.ValidationError
+			if errors.As(err, &validationErr) {
+				subT.Fatalf("expected no validation error for an undecodable payload")
+			}
+			if resp.StatusCode() != http.StatusUnprocessableEntity {
+				subT.Fatalf("expected the 422 to reach the response, got %d", resp.StatusCode())
+			}
+		},
+	)
+}
+
+func TestStatusErrors(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Query().Get("status") {
+				case "401":
+					w.WriteHeader(http.StatusUnauthorized)
+				case "429":
+					w.Header().Set("Retry-After", "30")
+					w.WriteHeader(http.StatusTooManyRequests)
+					_, _ = w.Write([]byte("slow down"))
+				case "404":
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusNotFound)
+					_, _ = w.Write([]byte(`{"code":"not_found","message":"no such widget"}`))
+				case "500":
+					w.WriteHeader(http.StatusInternalServerError)
+				default:
+					_, _ = w.Write([]byte(`{"value":"ok"}`))
+				}
+			},
+		),
+	)
+	defer srv.Close()
+
+	opts := []gkBoot.ClientOption{
+		gkBoot.WithStatusError(gkBoot.StatusRange{From: 401, To: 401}, gkBoot.ErrUnauthorized),
+		gkBoot.WithStatusError(gkBoot.StatusRange{From: 404, To: 404}, gkBoot.ErrNotFound),
+		gkBoot.WithStatusError(gkBoot.StatusRange{From: 429, To: 429}, gkBoot.ErrRateLimited),
+	}
+
+	t.Run(
+		"Sentinel Matched", func(subT *testing.T) {
+			err := gkBoot.DoRequestNoResponse(srv.URL, OptionsTestRequest{Status: 401}, opts...)
+			if !errors.Is(err, gkBoot.ErrUnauthorized) {
+				subT.Fatalf("expected ErrUnauthorized, got %v", err)
+			}
+			if errors.Is(err, gkBoot.ErrRateLimited) {
+				subT.Fatalf("expected only the mapped sentinel to match, got %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Retry After Parsed", func(subT *testing.T) {
+			resp := new(OptionsTestResponse)
+			err := gkBoot.DoRequestWithOptions(srv.URL, OptionsTestRequest{Status: 429}, resp, opts...)
+			if !errors.Is(err, gkBoot.ErrRateLimited) {
+				subT.Fatalf("expected ErrRateLimited, got %v", err)
+			}
+			var statusErr *gkBoot.StatusError
+			if !errors.As(err, &statusErr) {
+				subT.Fatalf("expected a *StatusError, got %T", err)
+			}
+			if statusErr.RetryAfter != 30*time.Second || statusErr.StatusCode != http.StatusTooManyRequests {
+				subT.Fatalf(
+					"expected a 429 to retry after 30s, got %d after %s", statusErr.StatusCode, statusErr.RetryAfter,
+				)
+			}
+			if string(statusErr.Body) != "slow down" {
+				subT.Fatalf("expected the body kept, got %q", statusErr.Body)
+			}
+		},
+	)
+
+	t.Run(
+		"Typed Error Kept As Cause", func(subT *testing.T) {
+			err := gkBoot.DoRequestNoResponse(
+				srv.URL, OptionsTestRequest{Status: 404}, append(opts, gkBoot.WithErrorType[*APIError]())...,
+			)
+			if !errors.Is(err, gkBoot.ErrNotFound) {
+				subT.Fatalf("expected ErrNotFound, got %v", err)
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.Code != "not_found" {
+				subT.Fatalf("expected the decoded *APIError, got %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Unmapped Status", func(subT *testing.T) {
+			err := gkBoot.DoRequestNoResponse(srv.URL, OptionsTestRequest{Status: 500}, opts...)
+			var statusErr *gkBoot.StatusError
+			if err == nil || errors.As(err, &statusErr) {
+				subT.Fatalf("expected the default error for an unmapped status, got %v", err)
+			}
+		},
+	)
+}
+
+type OptionalTestResponse struct {
+	Value string `json:"value"`
+	response.Presence
+}
+
+func TestNotFoundAsAbsent(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("status") == "404" {
+					w.WriteHeader(http.StatusNotFound)
+					_, _ = w.Write([]byte("no such widget"))
+					return
+				}
+				_, _ = w.Write([]byte(`{"value":"ok"}`))
+			},
+		),
+	)
+	defer srv.Close()
+
+	t.Run(
+		"Optional Marked Absent", func(subT *testing.T) {
+			resp := new(OptionalTestResponse)
+			err := gkBoot.DoRequestWithOptions(
+				srv.URL, OptionsTestRequest{Status: 404}, resp, gkBoot.WithNotFoundAsAbsent(),
+			)
+			if err != nil {
+				subT.Fatalf("expected no error for an absent resource, got %v", err)
+			}
+			if !resp.Absent() || resp.Value != "" {
+				subT.Fatalf("expected an absent and empty response, got %+v", resp)
+			}
+		},
+	)
+
+	t.Run(
+		"Optional Present", func(subT *testing.T) {
+			resp := new(OptionalTestResponse)
+			err := gkBoot.DoRequestWithOptions(
+				srv.URL, OptionsTestRequest{Status: 200}, resp, gkBoot.WithNotFoundAsAbsent(),
+			)
+			if err != nil {
+				subT.Fatalf("unexpected error: %s", err)
+			}
+			if resp.Absent() || resp.Value != "ok" {
+				subT.Fatalf("expected a present response, got %+v", resp)
+			}
+		},
+	)
+
+	t.Run(
+		"Sentinel Returned", func(subT *testing.T) {
+			err := gkBoot.DoRequestWithOptions(
+				srv.URL, OptionsTestRequest{Status: 404}, new(OptionsTestResponse), gkBoot.WithNotFoundAsAbsent(),
+			)
+			if !errors.Is(err, gkBoot.ErrNotFound) {
+				subT.Fatalf("expected ErrNotFound, got %v", err)
+			}
+			var statusErr *gkBoot.StatusError
+			if !errors.As(err, &statusErr) || string(statusErr.Body) != "no such widget" {
+				subT.Fatalf("expected a *StatusError keeping the body, got %v", err)
+			}
+
+			err = gkBoot.DoRequestNoResponse(srv.URL, OptionsTestRequest{Status: 404}, gkBoot.WithNotFoundAsAbsent())
+			if !errors.Is(err, gkBoot.ErrNotFound) {
+				subT.Fatalf("expected ErrNotFound without a response object, got %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Unreadable Body Still Not Found", func(subT *testing.T) {
+			unreadable := func(next gkBoot.RoundTripFunc) gkBoot.RoundTripFunc {
+				return func(r *http.Request) (*http.Response, error) {
+					return &http.Response{
+						StatusCode: http.StatusNotFound,
+						Header:     http.Header{},
+						Body:       io.NopCloser(iotest.ErrReader(errors.New("connection reset"))),
+						Request:    r,
+					}, nil
+				}
+			}
+
+			resp := new(OptionsTestResponse)
+			err := gkBoot.DoRequestWithOptions(
+				srv.URL, OptionsTestRequest{Status: 404}, resp, gkBoot.WithNotFoundAsAbsent(),
+				gkBoot.WithMiddleware(unreadable),
+			)
+			if !errors.Is(err, gkBoot.ErrNotFound) {
+				subT.Fatalf("expected ErrNotFound for an unreadable body, got %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Not Absent By Default", func(subT *testing.T) {
+			resp := new(OptionalTestResponse)
+			err := gkBoot.DoRequestWithOptions(srv.URL, OptionsTestRequest{Status: 404}, resp)
+			if err == nil || errors.Is(err, gkBoot.ErrNotFound) || resp.Absent() {
+				subT.Fatalf("expected the usual error path, got %v", err)
+			}
+		},
+	)
+}
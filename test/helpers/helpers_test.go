@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/yomiji/gkBoot/helpers"
+	"github.com/yomiji/gkBoot/logging"
+	"github.com/yomiji/gkBoot/response"
 )
 
 func TestHeadersInject(t *testing.T) {
@@ -30,3 +32,86 @@ func TestRateLimiter(t *testing.T) {
 		}
 	}
 }
+
+func TestRateLimiter64(t *testing.T) {
+	var ctx = context.Background()
+	var beyond32Bit int64 = 5 << 30
+	helpers.SetRequestBodyLimit64(&ctx, beyond32Bit)
+	if limit := helpers.GetRequestBodyLimit64(ctx); limit == nil {
+		t.Fatal("Unable to retrieve limit from context injection: nil")
+	} else {
+		if *limit != beyond32Bit {
+			t.Fatal("expected limit not valid")
+		}
+	}
+}
+
+func TestAdditionalLogsOrdered(t *testing.T) {
+	logs := new(response.ExpandedLogging)
+	logs.Log("zeta", 1, "alpha", 2, "mid", 3)
+	logs.Log("alpha", 20, "omega")
+
+	ordered := helpers.GetAdditionalLogsOrdered(logs)
+	expected := []response.KV{{Key: "zeta", Value: 1}, {Key: "alpha", Value: 20}, {Key: "mid", Value: 3}, {Key: "omega", Value: response.MissingValue}}
+	if len(ordered) != len(expected) {
+		t.Fatalf("expected %d values, got %v", len(expected), ordered)
+	}
+	for i := range expected {
+		if ordered[i] != expected[i] {
+			t.Fatalf("expected %v at position %d, got %v", expected[i], i, ordered[i])
+		}
+	}
+	if all := helpers.GetAdditionalLogs(logs); len(all) != 4 || all["alpha"] != 20 {
+		t.Fatalf("expected the map view to match, got %v", all)
+	}
+}
+
+func TestAdditionalLogsOddArity(t *testing.T) {
+	logs := new(response.ExpandedLogging)
+	logs.Log("user", "alice", "dangling")
+
+	all := helpers.GetAdditionalLogs(logs)
+	if all["user"] != "alice" {
+		t.Fatalf("expected the paired value to be kept, got %v", all)
+	}
+	if value, ok := all["dangling"]; !ok || value != response.MissingValue {
+		t.Fatalf("expected the dangling key to be recorded as %s, got %v", response.MissingValue, value)
+	}
+}
+
+type capturingLogger struct {
+	elements []interface{}
+}
+
+func (c *capturingLogger) Log(elem ...interface{}) error {
+	c.elements = elem
+	return nil
+}
+
+type loggedService struct{}
+
+func (loggedService) Execute(context.Context, any) (any, error) {
+	logs := new(response.ExpandedLogging)
+	logs.Log("zeta", 1, "alpha", 2)
+	return logs, nil
+}
+
+func TestLoggingWrapperOrderedLogs(t *testing.T) {
+	logger := new(capturingLogger)
+	srv := logging.GenerateLoggingWrapper(logger)(loggedService{})
+	if _, err := srv.Execute(context.Background(), nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for i := 0; i+1 < len(logger.elements); i += 2 {
+		if logger.elements[i] != "AdditionalLogs" {
+			continue
+		}
+		ordered, ok := logger.elements[i+1].([]response.KV)
+		if !ok || len(ordered) != 2 || ordered[0].Key != "zeta" || ordered[1].Key != "alpha" {
+			t.Fatalf("expected the additional logs in logged order, got %v", logger.elements[i+1])
+		}
+		return
+	}
+	t.Fatalf("expected additional logs, got %v", logger.elements)
+}